@@ -1,14 +1,23 @@
 package http
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/vinod901/opendq-go/internal/alerting"
 	"github.com/vinod901/opendq-go/internal/check"
+	"github.com/vinod901/opendq-go/internal/crawler"
 	"github.com/vinod901/opendq-go/internal/datasource"
+	"github.com/vinod901/opendq-go/internal/profile"
 	"github.com/vinod901/opendq-go/internal/scheduler"
+	"github.com/vinod901/opendq-go/internal/tablediff"
+	"github.com/vinod901/opendq-go/internal/tenant"
 	"github.com/vinod901/opendq-go/internal/view"
 )
 
@@ -19,6 +28,9 @@ type DataQualityHandler struct {
 	schedulerManager  *scheduler.Manager
 	alertManager      *alerting.Manager
 	viewManager       *view.Manager
+	crawlerManager    *crawler.Manager
+	profileManager    *profile.Manager
+	diffManager       *tablediff.Manager
 }
 
 // NewDataQualityHandler creates a new data quality handler
@@ -28,6 +40,9 @@ func NewDataQualityHandler(
 	schedulerManager *scheduler.Manager,
 	alertManager *alerting.Manager,
 	viewManager *view.Manager,
+	crawlerManager *crawler.Manager,
+	profileManager *profile.Manager,
+	diffManager *tablediff.Manager,
 ) *DataQualityHandler {
 	return &DataQualityHandler{
 		datasourceManager: datasourceManager,
@@ -35,6 +50,9 @@ func NewDataQualityHandler(
 		schedulerManager:  schedulerManager,
 		alertManager:      alertManager,
 		viewManager:       viewManager,
+		crawlerManager:    crawlerManager,
+		profileManager:    profileManager,
+		diffManager:       diffManager,
 	}
 }
 
@@ -48,6 +66,12 @@ func (h *DataQualityHandler) RegisterRoutes(mux *http.ServeMux) {
 	// Check routes
 	mux.HandleFunc("/api/v1/checks", h.handleChecks)
 	mux.HandleFunc("/api/v1/checks/", h.handleCheck)
+	mux.HandleFunc("/api/v1/checks/bulk", h.createChecksBulk)
+	mux.HandleFunc("/api/v1/checks/compile", h.compileCheck)
+	mux.HandleFunc("/api/v1/checks/dry-run", h.dryRunCheck)
+
+	// Tenant query budget routes
+	mux.HandleFunc("/api/v1/query-budgets/", h.handleQueryBudget)
 
 	// Schedule routes
 	mux.HandleFunc("/api/v1/schedules", h.handleSchedules)
@@ -61,6 +85,54 @@ func (h *DataQualityHandler) RegisterRoutes(mux *http.ServeMux) {
 	// View routes
 	mux.HandleFunc("/api/v1/views", h.handleViews)
 	mux.HandleFunc("/api/v1/views/", h.handleView)
+
+	// Table diff routes
+	mux.HandleFunc("/api/v1/diffs", h.handleDiffs)
+	mux.HandleFunc("/api/v1/diffs/", h.getDiff)
+}
+
+// Tenant scoping
+
+// callerTenant returns the ID of the tenant a multi-tenant request acts for,
+// or "" without multi-tenancy. A tenant slug that does not resolve is an
+// error.
+func callerTenant(r *http.Request) (string, error) {
+	if tenantID, err := tenant.GetTenantID(r.Context()); err == nil {
+		return tenantID, nil
+	}
+	if slug, err := tenant.GetTenantSlug(r.Context()); err == nil {
+		return "", fmt.Errorf("tenant not found: %s", slug)
+	}
+	return "", nil
+}
+
+// authorizeDatasources checks that the datasources belong to the caller's
+// tenant; those of other tenants are reported as not found. Without
+// multi-tenancy every datasource is accessible.
+func (h *DataQualityHandler) authorizeDatasources(r *http.Request, ids ...string) (int, error) {
+	tenantID, err := callerTenant(r)
+	if err != nil {
+		return http.StatusForbidden, err
+	}
+	if tenantID == "" {
+		return 0, nil
+	}
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		ds, err := h.datasourceManager.GetDatasource(r.Context(), id)
+		if err != nil || ds.TenantID != tenantID {
+			return http.StatusNotFound, fmt.Errorf("datasource not found: %s", id)
+		}
+	}
+	return 0, nil
+}
+
+// authorizeCheck checks that the datasources a check definition reads belong
+// to the caller's tenant
+func (h *DataQualityHandler) authorizeCheck(r *http.Request, chk *check.Check) (int, error) {
+	return h.authorizeDatasources(r, chk.DatasourceIDs()...)
 }
 
 // Helper to extract ID from path
@@ -74,6 +146,17 @@ func extractIDFromPath(path, prefix string) string {
 	return ""
 }
 
+// Helper to extract the table name from /api/v1/datasources/{id}/tables/{table}/...
+func extractTableFromPath(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i, part := range parts {
+		if part == "tables" && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
 // Datasource handlers
 
 func (h *DataQualityHandler) handleDatasources(w http.ResponseWriter, r *http.Request) {
@@ -89,8 +172,25 @@ func (h *DataQualityHandler) handleDatasources(w http.ResponseWriter, r *http.Re
 
 func (h *DataQualityHandler) handleDatasource(w http.ResponseWriter, r *http.Request) {
 	id := extractIDFromPath(r.URL.Path, "/api/v1/datasources")
+	if status, err := h.authorizeDatasources(r, id); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
 
-	// Check for sub-resources
+	// Check for sub-resources; table sub-resources first, since table names may
+	// contain the other sub-resource names
+	if strings.HasSuffix(r.URL.Path, "/profile") {
+		h.handleTableProfile(w, r, id)
+		return
+	}
+	if strings.HasSuffix(r.URL.Path, "/recommendations") {
+		h.recommendTableChecks(w, r, id)
+		return
+	}
+	if strings.Contains(r.URL.Path, "/relationships") {
+		h.discoverRelationships(w, r, id)
+		return
+	}
 	if strings.Contains(r.URL.Path, "/checks") {
 		h.listDatasourceChecks(w, r, id)
 		return
@@ -99,6 +199,22 @@ func (h *DataQualityHandler) handleDatasource(w http.ResponseWriter, r *http.Req
 		h.listDatasourceTables(w, r, id)
 		return
 	}
+	if strings.Contains(r.URL.Path, "/queries") {
+		h.listDatasourceQueries(w, r, id)
+		return
+	}
+	if strings.Contains(r.URL.Path, "/snapshots") {
+		h.listDatasourceSnapshots(w, r, id)
+		return
+	}
+	if strings.Contains(r.URL.Path, "/drift") {
+		h.listDatasourceDrift(w, r, id)
+		return
+	}
+	if strings.Contains(r.URL.Path, "/crawl") {
+		h.crawlDatasource(w, r, id)
+		return
+	}
 
 	switch r.Method {
 	case http.MethodGet:
@@ -131,6 +247,14 @@ func (h *DataQualityHandler) createDatasource(w http.ResponseWriter, r *http.Req
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	tenantID, err := callerTenant(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if tenantID != "" {
+		ds.TenantID = tenantID
+	}
 
 	if err := h.datasourceManager.CreateDatasource(r.Context(), &ds); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -224,6 +348,101 @@ func (h *DataQualityHandler) listDatasourceTables(w http.ResponseWriter, r *http
 	json.NewEncoder(w).Encode(tables)
 }
 
+// handleTableProfile serves /api/v1/datasources/{id}/tables/{table}/profile:
+// POST profiles the table, GET returns its stored profile history
+func (h *DataQualityHandler) handleTableProfile(w http.ResponseWriter, r *http.Request, id string) {
+	table := extractTableFromPath(r.URL.Path)
+	if table == "" {
+		http.Error(w, "table is required", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		profiles, err := h.profileManager.ListProfiles(r.Context(), id, table)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(profiles)
+	case http.MethodPost:
+		var opts profile.Options
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		tableProfile, err := h.profileManager.ProfileTable(r.Context(), id, table, opts)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(tableProfile)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// recommendTableChecks serves POST /api/v1/datasources/{id}/tables/{table}/recommendations
+func (h *DataQualityHandler) recommendTableChecks(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	table := extractTableFromPath(r.URL.Path)
+	if table == "" {
+		http.Error(w, "table is required", http.StatusBadRequest)
+		return
+	}
+
+	var opts profile.RecommendOptions
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	recommendations, err := h.profileManager.RecommendChecks(r.Context(), id, table, opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recommendations)
+}
+
+// discoverRelationships serves POST /api/v1/datasources/{id}/relationships
+func (h *DataQualityHandler) discoverRelationships(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var opts profile.DiscoveryOptions
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	relationships, err := h.profileManager.DiscoverRelationships(r.Context(), id, opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(relationships)
+}
+
 func (h *DataQualityHandler) listDatasourceChecks(w http.ResponseWriter, r *http.Request, id string) {
 	checks, err := h.checkManager.ListChecks(r.Context(), "", id)
 	if err != nil {
@@ -235,6 +454,145 @@ func (h *DataQualityHandler) listDatasourceChecks(w http.ResponseWriter, r *http
 	json.NewEncoder(w).Encode(checks)
 }
 
+func (h *DataQualityHandler) listDatasourceQueries(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := datasource.QueryLogFilter{
+		DatasourceID: id,
+		OriginType:   datasource.OriginType(query.Get("origin_type")),
+		OriginID:     query.Get("origin_id"),
+		UserID:       query.Get("user_id"),
+	}
+
+	var err error
+	if since := query.Get("since"); since != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			http.Error(w, "invalid since: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if until := query.Get("until"); until != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			http.Error(w, "invalid until: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			http.Error(w, "invalid limit: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	entries, err := h.datasourceManager.ListQueries(r.Context(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+func (h *DataQualityHandler) listDatasourceSnapshots(w http.ResponseWriter, r *http.Request, id string) {
+	snapshots, err := h.crawlerManager.ListSnapshots(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(snapshots)
+}
+
+func (h *DataQualityHandler) listDatasourceDrift(w http.ResponseWriter, r *http.Request, id string) {
+	events, err := h.crawlerManager.ListDriftEvents(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+func (h *DataQualityHandler) crawlDatasource(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	snapshot, event, err := h.crawlerManager.Crawl(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"snapshot": snapshot,
+		"drift":    event,
+	})
+}
+
+// Query budget handlers
+
+func (h *DataQualityHandler) handleQueryBudget(w http.ResponseWriter, r *http.Request) {
+	tenantID := extractIDFromPath(r.URL.Path, "/api/v1/query-budgets")
+	if tenantID == "" {
+		http.Error(w, "tenant ID is required", http.StatusBadRequest)
+		return
+	}
+	callerID, err := callerTenant(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if callerID != "" && callerID != tenantID {
+		http.Error(w, "query budgets of other tenants are not accessible", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		budget, err := h.checkManager.GetTenantQueryBudget(r.Context(), tenantID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if budget == nil {
+			http.Error(w, "query budget not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(budget)
+	case http.MethodPut:
+		var budget datasource.QueryBudget
+		if err := json.NewDecoder(r.Body).Decode(&budget); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.checkManager.SetTenantQueryBudget(r.Context(), tenantID, &budget); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(budget)
+	case http.MethodDelete:
+		if err := h.checkManager.SetTenantQueryBudget(r.Context(), tenantID, nil); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
 // Check handlers
 
 func (h *DataQualityHandler) handleChecks(w http.ResponseWriter, r *http.Request) {
@@ -250,6 +608,15 @@ func (h *DataQualityHandler) handleChecks(w http.ResponseWriter, r *http.Request
 
 func (h *DataQualityHandler) handleCheck(w http.ResponseWriter, r *http.Request) {
 	id := extractIDFromPath(r.URL.Path, "/api/v1/checks")
+	if chk, err := h.checkManager.GetCheck(r.Context(), id); err == nil {
+		if status, err := h.authorizeCheck(r, chk); err != nil {
+			if status == http.StatusNotFound {
+				err = fmt.Errorf("check not found: %s", id)
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+	}
 
 	// Check for sub-resources
 	if strings.Contains(r.URL.Path, "/run") {
@@ -260,6 +627,14 @@ func (h *DataQualityHandler) handleCheck(w http.ResponseWriter, r *http.Request)
 		h.getCheckResults(w, r, id)
 		return
 	}
+	if strings.Contains(r.URL.Path, "/baseline") {
+		h.resetCheckBaseline(w, r, id)
+		return
+	}
+	if strings.Contains(r.URL.Path, "/failing-rows") {
+		h.getFailingRows(w, r, id)
+		return
+	}
 
 	switch r.Method {
 	case http.MethodGet:
@@ -293,9 +668,13 @@ func (h *DataQualityHandler) createCheck(w http.ResponseWriter, r *http.Request)
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if status, err := h.authorizeCheck(r, &chk); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
 
 	if err := h.checkManager.CreateCheck(r.Context(), &chk); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeCheckError(w, err, http.StatusBadRequest)
 		return
 	}
 
@@ -304,6 +683,108 @@ func (h *DataQualityHandler) createCheck(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(chk)
 }
 
+// createChecksBulk serves POST /api/v1/checks/bulk, creating every check in
+// the request body, e.g. accepted recommendations
+func (h *DataQualityHandler) createChecksBulk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var checks []*check.Check
+	if err := json.NewDecoder(r.Body).Decode(&checks); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, chk := range checks {
+		if status, err := h.authorizeCheck(r, chk); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+	}
+
+	if err := h.checkManager.CreateChecks(r.Context(), checks); err != nil {
+		writeCheckError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(checks)
+}
+
+// compileCheck serves POST /api/v1/checks/compile, returning the SQL the
+// check definition in the request body would send, without saving it
+func (h *DataQualityHandler) compileCheck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var chk check.Check
+	if err := json.NewDecoder(r.Body).Decode(&chk); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if status, err := h.authorizeCheck(r, &chk); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	compiled, err := h.checkManager.CompileCheck(r.Context(), &chk)
+	if err != nil {
+		writeCheckError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(compiled)
+}
+
+// dryRunCheck serves POST /api/v1/checks/dry-run, executing the check
+// definition in the request body and returning its result without saving
+// either
+func (h *DataQualityHandler) dryRunCheck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var chk check.Check
+	if err := json.NewDecoder(r.Body).Decode(&chk); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if status, err := h.authorizeCheck(r, &chk); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	result, err := h.checkManager.DryRunCheck(r.Context(), &chk)
+	if err != nil {
+		writeCheckError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// writeCheckError responds to an invalid check definition with its field
+// errors as JSON, and to other errors with status
+func writeCheckError(w http.ResponseWriter, err error, status int) {
+	var invalid *check.ValidationError
+	if errors.As(err, &invalid) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(invalid)
+		return
+	}
+	http.Error(w, err.Error(), status)
+}
+
 func (h *DataQualityHandler) getCheck(w http.ResponseWriter, r *http.Request, id string) {
 	chk, err := h.checkManager.GetCheck(r.Context(), id)
 	if err != nil {
@@ -323,7 +804,7 @@ func (h *DataQualityHandler) updateCheck(w http.ResponseWriter, r *http.Request,
 	}
 
 	if err := h.checkManager.UpdateCheck(r.Context(), id, updates); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeCheckError(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -357,6 +838,83 @@ func (h *DataQualityHandler) runCheck(w http.ResponseWriter, r *http.Request, id
 	json.NewEncoder(w).Encode(result)
 }
 
+// resetCheckBaseline serves DELETE /api/v1/checks/{id}/baseline; the next run
+// of the distribution check captures a new baseline
+func (h *DataQualityHandler) resetCheckBaseline(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := h.checkManager.ResetDistributionBaseline(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getFailingRows serves GET /api/v1/checks/{id}/failing-rows, exporting the
+// rows currently failing a row-level check as JSON or, with format=csv, CSV.
+// All failing rows are returned unless limit is given.
+func (h *DataQualityHandler) getFailingRows(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	var limit int
+	if l := query.Get("limit"); l != "" {
+		var err error
+		if limit, err = strconv.Atoi(l); err != nil {
+			http.Error(w, "invalid limit: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	rows, err := h.checkManager.FailingRows(r.Context(), id, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch query.Get("format") {
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rows)
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", id+"-failing-rows.csv"))
+		writer := csv.NewWriter(w)
+		writer.Write(rows.Columns)
+		for _, row := range rows.Rows {
+			record := make([]string, len(rows.Columns))
+			for i, col := range rows.Columns {
+				record[i] = csvValue(row[col])
+			}
+			writer.Write(record)
+		}
+		writer.Flush()
+	default:
+		http.Error(w, "unsupported format: "+query.Get("format"), http.StatusBadRequest)
+	}
+}
+
+// csvValue renders a row value for CSV export, NULL as an empty field
+func csvValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(val)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
 func (h *DataQualityHandler) getCheckResults(w http.ResponseWriter, r *http.Request, id string) {
 	results, err := h.checkManager.GetCheckResults(r.Context(), id, 100)
 	if err != nil {
@@ -793,3 +1351,72 @@ func (h *DataQualityHandler) getViewSQL(w http.ResponseWriter, r *http.Request,
 		"sql": sql,
 	})
 }
+
+// Table diff handlers
+
+// handleDiffs serves /api/v1/diffs: POST starts a row-level diff between two
+// tables and returns its job, GET lists the jobs
+func (h *DataQualityHandler) handleDiffs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		jobs, err := h.diffManager.ListJobs(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		visible := make([]*tablediff.Job, 0, len(jobs))
+		for _, job := range jobs {
+			if _, err := h.authorizeDatasources(r, job.Request.SourceDatasourceID, job.Request.TargetDatasourceID); err == nil {
+				visible = append(visible, job)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(visible)
+	case http.MethodPost:
+		var req tablediff.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if status, err := h.authorizeDatasources(r, req.SourceDatasourceID, req.TargetDatasourceID); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		job, err := h.diffManager.StartDiff(r.Context(), req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(job)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getDiff serves GET /api/v1/diffs/{id}, returning a diff job with its
+// progress and, once completed, its result
+func (h *DataQualityHandler) getDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := extractIDFromPath(r.URL.Path, "/api/v1/diffs")
+	job, err := h.diffManager.GetJob(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if status, err := h.authorizeDatasources(r, job.Request.SourceDatasourceID, job.Request.TargetDatasourceID); err != nil {
+		if status == http.StatusNotFound {
+			err = fmt.Errorf("diff job not found: %s", id)
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
@@ -15,11 +15,14 @@ import (
 	"github.com/vinod901/opendq-go/internal/auth"
 	"github.com/vinod901/opendq-go/internal/authorization"
 	"github.com/vinod901/opendq-go/internal/check"
+	"github.com/vinod901/opendq-go/internal/crawler"
 	"github.com/vinod901/opendq-go/internal/datasource"
 	"github.com/vinod901/opendq-go/internal/lineage"
 	"github.com/vinod901/opendq-go/internal/middleware"
 	"github.com/vinod901/opendq-go/internal/policy"
+	"github.com/vinod901/opendq-go/internal/profile"
 	"github.com/vinod901/opendq-go/internal/scheduler"
+	"github.com/vinod901/opendq-go/internal/tablediff"
 	"github.com/vinod901/opendq-go/internal/tenant"
 	"github.com/vinod901/opendq-go/internal/view"
 	"github.com/vinod901/opendq-go/internal/workflow"
@@ -63,6 +66,9 @@ func run() error {
 		components.schedulerManager,
 		components.alertManager,
 		components.viewManager,
+		components.crawlerManager,
+		components.profileManager,
+		components.diffManager,
 	)
 
 	// Set up router
@@ -95,6 +101,14 @@ func run() error {
 		httpHandler = authzMiddleware.Handle(httpHandler)
 	}
 
+	// Start schema crawler
+	if cfg.Crawler.Enabled {
+		if err := components.crawlerManager.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start schema crawler: %w", err)
+		}
+		defer components.crawlerManager.Stop()
+	}
+
 	// Create HTTP server
 	server := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
@@ -147,6 +161,9 @@ type components struct {
 	schedulerManager  *scheduler.Manager
 	alertManager      *alerting.Manager
 	viewManager       *view.Manager
+	crawlerManager    *crawler.Manager
+	profileManager    *profile.Manager
+	diffManager       *tablediff.Manager
 }
 
 func initializeComponents(ctx context.Context, cfg *config.Config) (*components, error) {
@@ -219,6 +236,16 @@ func initializeComponents(ctx context.Context, cfg *config.Config) (*components,
 
 	// Initialize check manager
 	comp.checkManager = check.NewManager(comp.datasourceManager)
+	comp.checkManager.SetConcurrencyLimits(check.ConcurrencyLimits{
+		MaxConcurrent:    cfg.Checks.MaxConcurrent,
+		MaxPerDatasource: cfg.Checks.MaxPerDatasource,
+		CheckTimeout:     time.Duration(cfg.Checks.TimeoutSeconds) * time.Second,
+	})
+	comp.checkManager.SetRetryPolicy(check.RetryPolicy{
+		MaxAttempts:    cfg.Checks.RetryAttempts,
+		InitialBackoff: time.Duration(cfg.Checks.RetryBackoffMS) * time.Millisecond,
+		MaxBackoff:     time.Duration(cfg.Checks.RetryMaxBackoffMS) * time.Millisecond,
+	})
 	log.Println("Check manager initialized")
 
 	// Initialize scheduler manager
@@ -229,5 +256,39 @@ func initializeComponents(ctx context.Context, cfg *config.Config) (*components,
 	comp.viewManager = view.NewManager(comp.datasourceManager)
 	log.Println("View manager initialized")
 
+	// Initialize schema crawler and alert on drift
+	comp.crawlerManager = crawler.NewManager(comp.datasourceManager, time.Duration(cfg.Crawler.IntervalMinutes)*time.Minute)
+	comp.crawlerManager.OnDrift(func(ctx context.Context, event *crawler.DriftEvent) {
+		severity := alerting.SeverityInfo
+		if event.Breaking {
+			severity = alerting.SeverityHigh
+		}
+		alert := &alerting.Alert{
+			Title:    fmt.Sprintf("Schema drift detected in datasource %s", event.DatasourceID),
+			Message:  event.Summary(),
+			Severity: severity,
+			Details: map[string]interface{}{
+				"datasource_id": event.DatasourceID,
+				"from_version":  event.FromVersion,
+				"to_version":    event.ToVersion,
+				"changes":       event.Changes,
+			},
+			Timestamp: event.DetectedAt,
+		}
+		if err := comp.alertManager.SendAlertToAll(ctx, event.TenantID, alert); err != nil {
+			log.Printf("Warning: failed to send drift alert: %v", err)
+		}
+	})
+	log.Println("Schema crawler initialized")
+
+	// Initialize profile manager
+	comp.profileManager = profile.NewManager(comp.datasourceManager)
+	log.Println("Profile manager initialized")
+
+	// Initialize table diff manager
+	comp.diffManager = tablediff.NewManager(comp.datasourceManager)
+	comp.diffManager.SetRunLimiter(comp.checkManager)
+	log.Println("Table diff manager initialized")
+
 	return comp, nil
 }
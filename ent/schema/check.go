@@ -52,6 +52,24 @@ func (Check) Fields() []ent.Field {
 		field.String("view_id").
 			Optional().
 			Comment("Associated view ID for logical view checks"),
+		field.Int("timeout_seconds").
+			Default(0).
+			Comment("Deadline of a run, 0 for the manager's check timeout"),
+		field.JSON("filters", []map[string]interface{}{}).
+			Optional().
+			Comment("Structured row filters, combined with where by AND"),
+		field.Text("where").
+			Optional().
+			Comment("Raw SQL predicate restricting the evaluated rows"),
+		field.JSON("segment_by", []string{}).
+			Optional().
+			Comment("Columns the check is evaluated per group of"),
+		field.Int("max_segments").
+			Default(0).
+			Comment("Segments listed in a result, 0 for the default of 100"),
+		field.JSON("depends_on", []string{}).
+			Optional().
+			Comment("IDs of the checks that must pass before this one runs"),
 		field.Time("created_at").
 			Default(time.Now).
 			Immutable(),
@@ -33,6 +33,15 @@ func (CheckResult) Fields() []ent.Field {
 			Optional(),
 		field.JSON("details", map[string]interface{}{}).
 			Optional(),
+		field.JSON("failing_rows", map[string]interface{}{}).
+			Optional().
+			Comment("Sample of the rows that failed"),
+		field.JSON("watermark", map[string]interface{}{}).
+			Optional().
+			Comment("Highest watermark evaluated by an incremental check"),
+		field.JSON("segments", []map[string]interface{}{}).
+			Optional().
+			Comment("Per-segment outcomes of a segmented check"),
 		field.Int64("duration_ms").
 			Default(0).
 			Comment("Execution duration in milliseconds"),
@@ -31,6 +31,9 @@ func (Datasource) Fields() []ent.Field {
 			Comment("Connection configuration"),
 		field.JSON("metadata", map[string]interface{}{}).
 			Optional(),
+		field.JSON("query_budget", map[string]interface{}{}).
+			Optional().
+			Comment("Limits on estimated bytes scanned and cost per check run"),
 		field.Bool("active").
 			Default(true),
 		field.Time("created_at").
@@ -53,6 +56,7 @@ func (Datasource) Edges() []ent.Edge {
 			Required(),
 		edge.To("checks", Check.Type),
 		edge.To("views", View.Type),
+		edge.To("profiles", TableProfile.Type),
 	}
 }
 
@@ -0,0 +1,56 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// TableProfile holds the schema definition for the TableProfile entity.
+type TableProfile struct {
+	ent.Schema
+}
+
+// Fields of the TableProfile.
+func (TableProfile) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Unique().
+			Immutable(),
+		field.String("table_name").
+			NotEmpty(),
+		field.Int64("row_count").
+			Default(0),
+		field.JSON("columns", []map[string]interface{}{}).
+			Comment("Per-column statistics, top values and histograms"),
+		field.JSON("sample", map[string]interface{}{}).
+			Optional().
+			Comment("Sample configuration, if the profile was computed over a sample"),
+		field.Int64("duration_ms").
+			Default(0).
+			Comment("Profiling duration in milliseconds"),
+		field.Time("profiled_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+// Edges of the TableProfile.
+func (TableProfile) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("datasource", Datasource.Type).
+			Ref("profiles").
+			Unique().
+			Required(),
+	}
+}
+
+// Indexes of the TableProfile.
+func (TableProfile) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("table_name", "profiled_at"),
+	}
+}
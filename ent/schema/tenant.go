@@ -27,6 +27,9 @@ func (Tenant) Fields() []ent.Field {
 			NotEmpty(),
 		field.JSON("metadata", map[string]interface{}{}).
 			Optional(),
+		field.JSON("query_budget", map[string]interface{}{}).
+			Optional().
+			Comment("Limits on estimated bytes scanned and cost per check run"),
 		field.Bool("active").
 			Default(true),
 		field.Time("created_at").
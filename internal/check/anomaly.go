@@ -0,0 +1,234 @@
+package check
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// AnomalyMethod is how an anomaly threshold learns a metric's expected range
+type AnomalyMethod string
+
+const (
+	AnomalyZScore   AnomalyMethod = "zscore"   // Mean ± sensitivity standard deviations
+	AnomalyMAD      AnomalyMethod = "mad"      // Median ± sensitivity scaled median absolute deviations
+	AnomalySeasonal AnomalyMethod = "seasonal" // Level plus seasonal offset ± sensitivity scaled MAD of residuals
+)
+
+// Seasonality is the period of a seasonal anomaly model
+type Seasonality string
+
+const (
+	SeasonalityDaily  Seasonality = "daily"  // Values vary by hour of day
+	SeasonalityWeekly Seasonality = "weekly" // Values vary by day of week
+)
+
+const (
+	defaultSensitivity   = 3.0
+	defaultWarmupRuns    = 7
+	defaultAnomalyWindow = 30
+	// madScale makes the median absolute deviation a consistent estimator of
+	// the standard deviation for normally distributed data
+	madScale = 1.4826
+)
+
+// metricPoint is a metric value observed by a prior run
+type metricPoint struct {
+	at    time.Time
+	value float64
+}
+
+// metricHistory returns the numeric metric values of a check's previous runs, oldest first
+func (m *Manager) metricHistory(checkID string) []metricPoint {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var history []metricPoint
+	for _, r := range m.results[checkID] {
+		if r.Status == StatusError || r.Status == StatusSkipped {
+			continue
+		}
+		if value, ok := metricValue(r.ActualValue); ok {
+			history = append(history, metricPoint{at: r.Timestamp, value: value})
+		}
+	}
+	sort.SliceStable(history, func(i, j int) bool { return history[i].at.Before(history[j].at) })
+	return history
+}
+
+// metricValue returns a result's actual value as a number, if it is one
+func metricValue(v interface{}) (float64, bool) {
+	switch v.(type) {
+	case float64, float32, int64, int, int32:
+		return toFloat64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// applyAnomalyThreshold replaces the executor's static evaluation with bounds
+// learned from the check's own history. During warm-up the static evaluation
+// stands and the result is only annotated.
+func (m *Manager) applyAnomalyThreshold(check *Check, result *CheckResult, now time.Time) error {
+	value, ok := metricValue(result.ActualValue)
+	if !ok {
+		return fmt.Errorf("anomaly thresholds require a numeric metric, %s checks report %T", check.Type, result.ActualValue)
+	}
+
+	threshold := check.Threshold
+	method := threshold.AnomalyMethod
+	if method == "" {
+		method = AnomalyZScore
+	}
+	sensitivity := threshold.Sensitivity
+	if sensitivity <= 0 {
+		sensitivity = defaultSensitivity
+	}
+	warmup := threshold.WarmupRuns
+	if warmup <= 0 {
+		warmup = defaultWarmupRuns
+	}
+
+	history := m.metricHistory(check.ID)
+	if len(history) > defaultAnomalyWindow && method != AnomalySeasonal {
+		history = history[len(history)-defaultAnomalyWindow:]
+	}
+
+	info := map[string]interface{}{
+		"method":         method,
+		"sensitivity":    sensitivity,
+		"history_points": len(history),
+	}
+	if result.Details == nil {
+		result.Details = make(map[string]interface{})
+	}
+	result.Details["anomaly"] = info
+
+	if len(history) < warmup {
+		info["warming_up"] = true
+		info["warmup_runs"] = warmup
+		return nil
+	}
+
+	expected, spread, err := anomalyModel(method, threshold.Seasonality, history, now)
+	if err != nil {
+		return err
+	}
+	lower, upper := expected-sensitivity*spread, expected+sensitivity*spread
+	info["expected"] = expected
+	info["lower_bound"] = lower
+	info["upper_bound"] = upper
+	if spread > 0 {
+		info["score"] = (value - expected) / spread
+	}
+
+	result.ExpectedValue = expected
+	if value < lower || value > upper {
+		result.Status = StatusFailed
+		result.Message = fmt.Sprintf("value %g is anomalous: outside [%g, %g] learned from %d runs", value, lower, upper, len(history))
+	} else {
+		result.Status = StatusPassed
+		result.Message = fmt.Sprintf("value %g is within [%g, %g] learned from %d runs", value, lower, upper, len(history))
+	}
+	return nil
+}
+
+// anomalyModel returns the expected value at now and the spread that
+// sensitivity multiplies to form the bounds
+func anomalyModel(method AnomalyMethod, seasonality Seasonality, history []metricPoint, now time.Time) (float64, float64, error) {
+	values := make([]float64, len(history))
+	for i, p := range history {
+		values[i] = p.value
+	}
+
+	switch method {
+	case AnomalyZScore:
+		mean, stddev := meanStdDev(values)
+		return mean, stddev, nil
+	case AnomalyMAD:
+		center := median(values)
+		return center, madScale * medianAbsoluteDeviation(values, center), nil
+	case AnomalySeasonal:
+		return seasonalModel(seasonality, history, now)
+	default:
+		return 0, 0, fmt.Errorf("unsupported anomaly method: %s", method)
+	}
+}
+
+// seasonalModel decomposes history into a level (the median), a seasonal
+// offset per hour of day or day of week (the median deviation from the level
+// in that season) and residuals. The expectation is the level plus the offset
+// of now's season; the spread is the scaled MAD of the residuals.
+func seasonalModel(seasonality Seasonality, history []metricPoint, now time.Time) (float64, float64, error) {
+	var season func(time.Time) int
+	switch seasonality {
+	case SeasonalityDaily:
+		season = func(t time.Time) int { return t.Hour() }
+	case SeasonalityWeekly, "":
+		season = func(t time.Time) int { return int(t.Weekday()) }
+	default:
+		return 0, 0, fmt.Errorf("unsupported seasonality: %s", seasonality)
+	}
+
+	values := make([]float64, len(history))
+	for i, p := range history {
+		values[i] = p.value
+	}
+	level := median(values)
+
+	deviations := make(map[int][]float64)
+	for _, p := range history {
+		s := season(p.at)
+		deviations[s] = append(deviations[s], p.value-level)
+	}
+	offsets := make(map[int]float64, len(deviations))
+	for s, d := range deviations {
+		offsets[s] = median(d)
+	}
+
+	residuals := make([]float64, len(history))
+	for i, p := range history {
+		residuals[i] = p.value - level - offsets[season(p.at)]
+	}
+	spread := madScale * medianAbsoluteDeviation(residuals, median(residuals))
+
+	return level + offsets[season(now)], spread, nil
+}
+
+func meanStdDev(values []float64) (float64, float64) {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)-1))
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func medianAbsoluteDeviation(values []float64, center float64) float64 {
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - center)
+	}
+	return median(deviations)
+}
@@ -0,0 +1,106 @@
+package check
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+func seedMetricHistory(m *Manager, checkID string, start time.Time, step time.Duration, values ...float64) {
+	for i, v := range values {
+		m.results[checkID] = append(m.results[checkID], &CheckResult{
+			CheckID:     checkID,
+			Status:      StatusPassed,
+			ActualValue: v,
+			Timestamp:   start.Add(time.Duration(i) * step),
+		})
+	}
+}
+
+func anomalyResult(value interface{}) *CheckResult {
+	return &CheckResult{Status: StatusPassed, ActualValue: value, Details: map[string]interface{}{}}
+}
+
+func TestApplyAnomalyThreshold_WarmUp(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	check := &Check{ID: "c", Type: TypeMeanValue, Threshold: Threshold{Type: ThresholdAnomaly}}
+	seedMetricHistory(m, "c", time.Now().Add(-72*time.Hour), time.Hour, 10, 11, 10)
+
+	result := anomalyResult(1000.0)
+	if err := m.applyAnomalyThreshold(check, result, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	info := result.Details["anomaly"].(map[string]interface{})
+	if result.Status != StatusPassed || info["warming_up"] != true {
+		t.Errorf("expected static status during warm-up, got %s: %v", result.Status, info)
+	}
+}
+
+func TestApplyAnomalyThreshold_Methods(t *testing.T) {
+	history := []float64{100, 102, 98, 101, 99, 100, 103, 97, 100, 101}
+	for _, method := range []AnomalyMethod{AnomalyZScore, AnomalyMAD} {
+		m := NewManager(datasource.NewManager())
+		check := &Check{ID: "c", Type: TypeRowCount, Threshold: Threshold{Type: ThresholdAnomaly, AnomalyMethod: method}}
+		seedMetricHistory(m, "c", time.Now().Add(-240*time.Hour), 24*time.Hour, history...)
+
+		normal := anomalyResult(int64(101))
+		if err := m.applyAnomalyThreshold(check, normal, time.Now()); err != nil {
+			t.Fatalf("%s: unexpected error: %v", method, err)
+		}
+		if normal.Status != StatusPassed {
+			t.Errorf("%s: expected pass, got %s: %s", method, normal.Status, normal.Message)
+		}
+
+		spike := anomalyResult(int64(150))
+		if err := m.applyAnomalyThreshold(check, spike, time.Now()); err != nil {
+			t.Fatalf("%s: unexpected error: %v", method, err)
+		}
+		info := spike.Details["anomaly"].(map[string]interface{})
+		if spike.Status != StatusFailed || info["upper_bound"].(float64) >= 150 {
+			t.Errorf("%s: expected anomaly, got %s: %v", method, spike.Status, info)
+		}
+	}
+}
+
+func TestApplyAnomalyThreshold_Seasonal(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	check := &Check{ID: "c", Type: TypeRowCount, Threshold: Threshold{Type: ThresholdAnomaly, AnomalyMethod: AnomalySeasonal, Seasonality: SeasonalityWeekly}}
+
+	// Three weeks of daily loads, with weekends an order of magnitude lower
+	start := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC) // Monday
+	for day := 0; day < 21; day++ {
+		at := start.AddDate(0, 0, day)
+		value := 1000.0 + float64(day%3)
+		if at.Weekday() == time.Saturday || at.Weekday() == time.Sunday {
+			value = 100 + float64(day%3)
+		}
+		seedMetricHistory(m, "c", at, 0, value)
+	}
+
+	saturday := start.AddDate(0, 0, 26)
+	weekendLoad := anomalyResult(101.0)
+	if err := m.applyAnomalyThreshold(check, weekendLoad, saturday); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if weekendLoad.Status != StatusPassed {
+		t.Errorf("expected a normal weekend load to pass, got %s: %s", weekendLoad.Status, weekendLoad.Message)
+	}
+
+	monday := start.AddDate(0, 0, 21)
+	weekdayDrop := anomalyResult(101.0)
+	if err := m.applyAnomalyThreshold(check, weekdayDrop, monday); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if weekdayDrop.Status != StatusFailed {
+		t.Errorf("expected a weekend-sized load on a weekday to fail, got %s: %s", weekdayDrop.Status, weekdayDrop.Message)
+	}
+}
+
+func TestApplyAnomalyThreshold_NonNumeric(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	check := &Check{ID: "c", Type: TypeColumnType, Threshold: Threshold{Type: ThresholdAnomaly}}
+	if err := m.applyAnomalyThreshold(check, anomalyResult("varchar"), time.Now()); err == nil {
+		t.Error("expected error for a non-numeric metric")
+	}
+}
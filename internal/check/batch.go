@@ -0,0 +1,200 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// maxBatchSize caps the checks fused into one query, keeping its select list
+// within what warehouses comfortably plan
+const maxBatchSize = 25
+
+// specBuilders are the aggregate builders of the check types whose metrics
+// can be computed together in a single scan of their table
+var specBuilders = map[Type]func(context.Context, *Check, datasource.Connector) (aggregateSpec, error){
+	TypeNullCheck:     nullSpec,
+	TypeMinValue:      valueSpec,
+	TypeMaxValue:      valueSpec,
+	TypeMeanValue:     valueSpec,
+	TypeSumValue:      valueSpec,
+	TypeRange:         rangeSpec,
+	TypeSetMembership: setMembershipSpec,
+	TypeRegex:         regexSpec,
+	TypeFormat:        formatSpec,
+}
+
+// plannedCheck is a check whose aggregate joins a batch
+type plannedCheck struct {
+	check *Check
+	spec  aggregateSpec
+}
+
+// batch is a set of checks reading the same rows, computed by one query
+type batch struct {
+	from   string
+	checks []plannedCheck
+}
+
+// batchable reports whether a check's run is a plain aggregate over its rows.
+// Incremental and segmented checks shape their own queries, and checks with
+// a timeout of their own keep it.
+func (m *Manager) batchable(ctx context.Context, check *Check, connector datasource.Connector) bool {
+	if _, ok := specBuilders[check.Type]; !ok {
+		return false
+	}
+	if check.Parameters.Incremental != nil || len(check.SegmentBy) > 0 {
+		return false
+	}
+	if check.Parameters.Sample != nil && !supportsSampling(check.Type) {
+		return false
+	}
+	return check.TimeoutSeconds <= 0
+}
+
+// planBatches groups checks by the rows they read, i.e. by table, filter and
+// sample, so that each group is computed by one scan. Checks that cannot be
+// fused, or that would be alone in their group, are returned to be run on
+// their own.
+func (m *Manager) planBatches(ctx context.Context, checks []*Check, connector datasource.Connector) ([]*batch, []*Check) {
+	var batches []*batch
+	var singles []*Check
+	open := make(map[string]*batch)
+
+	for _, check := range checks {
+		if !m.batchable(ctx, check, connector) {
+			singles = append(singles, check)
+			continue
+		}
+		// A check whose aggregate cannot be built runs alone and reports the error
+		spec, err := specBuilders[check.Type](ctx, check, connector)
+		if err != nil {
+			singles = append(singles, check)
+			continue
+		}
+
+		b := open[spec.from]
+		if b == nil || len(b.checks) >= maxBatchSize {
+			b = &batch{from: spec.from}
+			open[spec.from] = b
+			batches = append(batches, b)
+		}
+		b.checks = append(b.checks, plannedCheck{check: check, spec: spec})
+	}
+
+	fused := batches[:0]
+	for _, b := range batches {
+		if len(b.checks) == 1 {
+			singles = append(singles, b.checks[0].check)
+			continue
+		}
+		fused = append(fused, b)
+	}
+	return fused, singles
+}
+
+// runBatch computes the aggregates of a batch in one query and fans the row
+// back out into one result per check. Each aggregate's alias is prefixed with
+// its check's position in the batch.
+func (m *Manager) runBatch(ctx context.Context, b *batch, connector datasource.Connector) []*CheckResult {
+	ids := make([]string, len(b.checks))
+	selects := make([]string, len(b.checks))
+	for i, planned := range b.checks {
+		ids[i] = planned.check.ID
+		selects[i] = planned.spec.selectList(batchPrefix(i))
+	}
+
+	// The fused query is estimated once, against the tightest budget of its checks
+	costGuard := m.batchCostEstimation(ctx, b, connector)
+	if costGuard != nil {
+		connector = costGuard
+	}
+
+	startTime := time.Now()
+	query := fmt.Sprintf("SELECT %s\n\t\tFROM %s", strings.Join(selects, ",\n\t\t\t"), b.from)
+	row, attempts, err := m.queryBatch(ctx, b, connector, query, strings.Join(ids, ","))
+
+	results := make([]*CheckResult, len(b.checks))
+	for i, planned := range b.checks {
+		var result *CheckResult
+		runErr := err
+		if err == nil {
+			result, runErr = planned.spec.evaluate(unprefixRow(row, batchPrefix(i)))
+		}
+		if runErr == nil {
+			if result.Details == nil {
+				result.Details = make(map[string]interface{})
+			}
+			result.Details["batch_size"] = len(b.checks)
+		}
+
+		checkCtx := datasource.WithQueryOrigin(ctx, datasource.QueryOrigin{Type: datasource.OriginCheck, ID: planned.check.ID})
+		results[i] = m.completeRun(checkCtx, planned.check, connector, costGuard, result, attempts, runErr, startTime)
+	}
+	return results
+}
+
+// batchCostEstimation wraps a connector to estimate the cost of a batch's
+// query against the tightest budget of its checks, like withCostEstimation
+// does for a single check
+func (m *Manager) batchCostEstimation(ctx context.Context, b *batch, connector datasource.Connector) *budgetedConnector {
+	var budget datasource.QueryBudget
+	estimate := false
+	for _, planned := range b.checks {
+		budget = budget.Merge(m.queryBudget(ctx, planned.check))
+		estimate = estimate || planned.check.Parameters.EstimateCost
+	}
+	if budget.IsZero() && !estimate {
+		return nil
+	}
+	estimator, ok := connector.(datasource.CostEstimator)
+	if !ok {
+		return nil
+	}
+	return newBudgetedConnector(connector, estimator, budget)
+}
+
+// queryBatch runs a batch query in one run slot, under the default check
+// timeout, retrying transient failures, and returns its row and failed
+// attempts
+func (m *Manager) queryBatch(ctx context.Context, b *batch, connector datasource.Connector, query, origin string) (map[string]interface{}, []Attempt, error) {
+	ctx, done, err := m.startRun(ctx, b.checks[0].check)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer done()
+
+	ctx = datasource.WithQueryOrigin(ctx, datasource.QueryOrigin{Type: datasource.OriginCheck, ID: origin})
+	var row map[string]interface{}
+	attempts, err := m.withRetries(ctx, func() error {
+		queryResult, err := connector.Query(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to execute batch query: %w", err)
+		}
+		if len(queryResult.Rows) == 0 {
+			return fmt.Errorf("batch query returned no results")
+		}
+		row = queryResult.Rows[0]
+		return nil
+	})
+	return row, attempts, timeoutError(ctx, err)
+}
+
+// batchPrefix returns the alias prefix of the i-th check in a batch
+func batchPrefix(i int) string {
+	return fmt.Sprintf("c%d_", i)
+}
+
+// unprefixRow returns the columns of a batch row that carry prefix, without it
+func unprefixRow(row map[string]interface{}, prefix string) map[string]interface{} {
+	out := make(map[string]interface{})
+	for col, value := range row {
+		if strings.HasPrefix(col, prefix) {
+			out[strings.TrimPrefix(col, prefix)] = value
+		}
+	}
+	return out
+}
@@ -0,0 +1,132 @@
+package check
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+	"github.com/vinod901/opendq-go/internal/view"
+)
+
+func TestPlanBatches(t *testing.T) {
+	active := []view.FilterDef{{Column: "status", Operator: "eq", Value: "active"}}
+	checks := []*Check{
+		{ID: "a", Type: TypeNullCheck, Table: "orders", Column: "email"},
+		{ID: "b", Type: TypeMaxValue, Table: "orders", Column: "amount"},
+		{ID: "c", Type: TypeSetMembership, Table: "orders", Column: "status", Parameters: CheckParameters{AllowedValues: []string{"open"}}},
+		{ID: "d", Type: TypeNullCheck, Table: "orders", Column: "email", Filters: active},
+		{ID: "e", Type: TypeUniqueness, Table: "orders", Column: "id"},
+		{ID: "f", Type: TypeRange, Table: "customers", Column: "age", SegmentBy: []string{"country"}},
+		{ID: "g", Type: TypeRegex, Table: "orders", Column: "email"}, // Missing pattern
+	}
+	m := NewManager(datasource.NewManager())
+
+	batches, singles := m.planBatches(context.Background(), checks, &fakeConnector{})
+
+	if len(batches) != 1 || len(batches[0].checks) != 3 || batches[0].from != "orders" {
+		t.Fatalf("expected one batch of three orders checks, got %+v", batches)
+	}
+	var ids []string
+	for _, check := range singles {
+		ids = append(ids, check.ID)
+	}
+	if strings.Join(ids, ",") != "e,f,g,d" {
+		t.Errorf("expected e, f, g and the lone filtered check to run alone, got %v", ids)
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	connector := &fakeConnector{rows: []map[string]interface{}{{
+		"c0_total_count": int64(100), "c0_null_count": int64(10),
+		"c1_value": 250.0,
+	}}}
+	checks := []*Check{
+		{ID: "nulls", Type: TypeNullCheck, Table: "orders", Column: "email", Parameters: CheckParameters{MaxNullPercentage: 5}},
+		{ID: "max", Type: TypeMaxValue, Table: "orders", Column: "amount", Parameters: CheckParameters{ExpectedMax: 250}},
+	}
+	m := NewManager(datasource.NewManager())
+
+	batches, _ := m.planBatches(context.Background(), checks, connector)
+	if len(batches) != 1 {
+		t.Fatalf("expected one batch, got %d", len(batches))
+	}
+	results := m.runBatch(context.Background(), batches[0], connector)
+
+	if len(connector.queries) != 1 {
+		t.Fatalf("expected a single scan, got %d queries", len(connector.queries))
+	}
+	query := connector.queries[0]
+	for _, want := range []string{"SUM(CASE WHEN email IS NULL THEN 1 ELSE 0 END) as c0_null_count", "MAX(amount) as c1_value", "FROM orders"} {
+		if !strings.Contains(query, want) {
+			t.Errorf("expected %q in query, got %s", want, query)
+		}
+	}
+
+	if results[0].CheckID != "nulls" || results[0].Status != StatusFailed || results[0].ActualValue != 10.0 {
+		t.Errorf("unexpected null check result: %+v", results[0])
+	}
+	if results[1].CheckID != "max" || results[1].Status != StatusPassed {
+		t.Errorf("unexpected max value result: %+v", results[1])
+	}
+	if results[0].Details["batch_size"] != 2 || checks[0].LastStatus != StatusFailed {
+		t.Errorf("expected batched results to be recorded, got %v and status %s", results[0].Details, checks[0].LastStatus)
+	}
+}
+
+func TestRunBatch_QueryError(t *testing.T) {
+	connector := &fakeConnector{err: context.DeadlineExceeded}
+	checks := []*Check{
+		{ID: "a", Type: TypeNullCheck, Table: "orders", Column: "email"},
+		{ID: "b", Type: TypeMinValue, Table: "orders", Column: "amount"},
+	}
+	m := NewManager(datasource.NewManager())
+
+	batches, _ := m.planBatches(context.Background(), checks, connector)
+	for _, result := range m.runBatch(context.Background(), batches[0], connector) {
+		if result.Status != StatusError || !strings.Contains(result.Error, "batch query") {
+			t.Errorf("expected every check in the batch to error, got %s: %s", result.Status, result.Error)
+		}
+	}
+}
+
+func TestRunBatch_Budget(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(datasource.NewManager())
+	m.SetTenantQueryBudget(ctx, "tenant-1", &datasource.QueryBudget{MaxBytesScanned: 1 << 30})
+
+	connector := &estimatingConnector{
+		fakeConnector: fakeConnector{rows: []map[string]interface{}{{
+			"c0_total_count": int64(100), "c0_null_count": int64(0),
+			"c1_value": 250.0,
+		}}},
+		estimate: datasource.CostEstimate{BytesScanned: 1 << 20, Method: "postgres_explain"},
+	}
+	checks := []*Check{
+		{ID: "nulls", TenantID: "tenant-1", Type: TypeNullCheck, Table: "orders", Column: "email"},
+		{ID: "max", TenantID: "tenant-1", Type: TypeMaxValue, Table: "orders", Column: "amount", Parameters: CheckParameters{ExpectedMax: 250}},
+	}
+
+	// Budgeted checks still fuse, and the fused query is estimated once
+	batches, singles := m.planBatches(ctx, checks, connector)
+	if len(batches) != 1 || len(singles) != 0 {
+		t.Fatalf("expected budgeted checks to fuse, got %d batches and %d singles", len(batches), len(singles))
+	}
+	for _, result := range m.runBatch(ctx, batches[0], connector) {
+		info, ok := result.Details["cost_estimate"].(map[string]interface{})
+		if result.Status != StatusPassed || !ok || info["queries"] != 1 {
+			t.Errorf("expected %s to pass with one estimated query, got %s: %v", result.CheckID, result.Status, result.Details)
+		}
+	}
+
+	connector.estimate.BytesScanned = 1 << 40
+	connector.queries = nil
+	for _, result := range m.runBatch(ctx, batches[0], connector) {
+		if result.Status != StatusError || !strings.Contains(result.Error, "query refused") {
+			t.Errorf("expected %s to be refused over budget, got %s: %s", result.CheckID, result.Status, result.Error)
+		}
+	}
+	if len(connector.queries) != 0 {
+		t.Errorf("expected no query over budget, got %v", connector.queries)
+	}
+}
@@ -6,10 +6,14 @@ package check
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/vinod901/opendq-go/internal/datasource"
+	"github.com/vinod901/opendq-go/internal/view"
 )
 
 // Type represents the type of data quality check
@@ -22,6 +26,7 @@ const (
 	TypeUniqueness  Type = "uniqueness"
 	TypeFreshness   Type = "freshness"
 	TypeCustomSQL   Type = "custom_sql"
+	TypeExpression  Type = "expression" // Row-level rule in CEL
 	
 	// Value checks
 	TypeMinValue    Type = "min_value"
@@ -40,6 +45,8 @@ const (
 	TypeReferentialIntegrity Type = "referential_integrity"
 	TypeVolume               Type = "volume"
 	TypeDistribution         Type = "distribution"
+	TypeReconciliation       Type = "reconciliation" // Compares two datasource/table pairs
+	TypeTableDiff            Type = "table_diff"     // Finds the rows that differ between two tables
 	
 	// Schema checks
 	TypeSchemaMatch Type = "schema_match"
@@ -89,6 +96,12 @@ type Check struct {
 	Active          bool                   `json:"active"`
 	ScheduleID      string                 `json:"schedule_id,omitempty"`
 	ViewID          string                 `json:"view_id,omitempty"` // For logical view checks
+	TimeoutSeconds  int                    `json:"timeout_seconds,omitempty"` // Deadline of a run, overriding the manager's check timeout
+	Filters         []view.FilterDef       `json:"filters,omitempty"`      // Evaluate only the rows matching these conditions
+	Where           string                 `json:"where,omitempty"`        // Raw SQL predicate, combined with Filters by AND
+	SegmentBy       []string               `json:"segment_by,omitempty"`   // Evaluate the check per group of these columns
+	MaxSegments     int                    `json:"max_segments,omitempty"` // Segments listed in a result, default 100
+	DependsOn       []string               `json:"depends_on,omitempty"`   // Checks that must pass before this one runs
 	CreatedAt       time.Time              `json:"created_at"`
 	UpdatedAt       time.Time              `json:"updated_at"`
 	LastRunAt       *time.Time             `json:"last_run_at,omitempty"`
@@ -116,14 +129,20 @@ type CheckParameters struct {
 	CustomSQL        string `json:"custom_sql,omitempty"`
 	ExpectedValue    string `json:"expected_value,omitempty"`
 	
+	// Expression rule parameters
+	Expression       string         `json:"expression,omitempty"`      // CEL rule every row must satisfy
+	ExpressionMode   ExpressionMode `json:"expression_mode,omitempty"` // auto (default), sql, go
+	
 	// Value check parameters
 	ExpectedMin      float64  `json:"expected_min,omitempty"`
 	ExpectedMax      float64  `json:"expected_max,omitempty"`
 	ExpectedMean     float64  `json:"expected_mean,omitempty"`
+	ExpectedStdDev   float64  `json:"expected_std_dev,omitempty"`
 	Tolerance        float64  `json:"tolerance,omitempty"`
 	
 	// Pattern check parameters
 	Pattern          string   `json:"pattern,omitempty"`
+	Format           string   `json:"format,omitempty"` // Named format, see Formats
 	AllowedValues    []string `json:"allowed_values,omitempty"`
 	
 	// Referential check parameters
@@ -131,21 +150,55 @@ type CheckParameters struct {
 	ReferenceColumn  string `json:"reference_column,omitempty"`
 	
 	// Volume check parameters
-	ExpectedVolume    int64   `json:"expected_volume,omitempty"`
-	VolumeTolerance   float64 `json:"volume_tolerance,omitempty"`
+	ExpectedVolume    int64        `json:"expected_volume,omitempty"`  // Overrides the learned volume
+	VolumeTolerance   float64      `json:"volume_tolerance,omitempty"` // Percent, default 20
+	VolumeMethod      VolumeMethod `json:"volume_method,omitempty"`    // Defaults to trailing
+	VolumeWindow      int          `json:"volume_window,omitempty"`    // Prior runs considered
+	
+	// Distribution check parameters
+	DistributionMethod  DistributionMethod `json:"distribution_method,omitempty"`  // psi (default), ks, chi_square
+	DistributionBuckets int                `json:"distribution_buckets,omitempty"` // Numeric buckets, default 10
+	Baseline            *Distribution      `json:"baseline,omitempty"`             // Captured on the first run
 	
 	// Schema check parameters
 	ExpectedSchema   []datasource.ColumnInfo `json:"expected_schema,omitempty"`
 	ExpectedColumns  int                     `json:"expected_columns,omitempty"`
+	
+	// Reconciliation check parameters
+	Reconciliation   *ReconciliationConfig   `json:"reconciliation,omitempty"`
+	
+	// Table diff check parameters
+	TableDiff        *TableDiffConfig        `json:"table_diff,omitempty"`
+	
+	// Incremental mode: evaluate only rows past the last watermark
+	Incremental      *IncrementalConfig      `json:"incremental,omitempty"`
+	
+	// Failing row capture for row-level checks
+	FailingRows      *FailingRowsConfig      `json:"failing_rows,omitempty"`
+	
+	// Sampling parameters for checks on very large tables
+	Sample           *datasource.Sample      `json:"sample,omitempty"`
+	
+	// Cost estimation: estimate check queries even when no budget applies
+	EstimateCost     bool                    `json:"estimate_cost,omitempty"`
 }
 
-// Threshold defines pass/fail criteria for a check
+// Threshold defines pass/fail criteria for a check. Operator, Value, MinValue
+// and MaxValue describe the values a healthy metric satisfies; when Operator is
+// empty the executor derives its criteria from the check parameters.
 type Threshold struct {
 	Type        ThresholdType `json:"type"`
 	Value       float64       `json:"value"`
 	MinValue    float64       `json:"min_value,omitempty"`
 	MaxValue    float64       `json:"max_value,omitempty"`
-	Operator    string        `json:"operator,omitempty"` // eq, ne, lt, lte, gt, gte, between
+	Operator    Operator      `json:"operator,omitempty"` // eq, ne, lt, lte, gt, gte, between
+	Warn        *Condition    `json:"warn,omitempty"`     // Warning level, in the same semantics as the fail level above
+	
+	// Anomaly threshold parameters
+	AnomalyMethod AnomalyMethod `json:"anomaly_method,omitempty"` // zscore (default), mad, seasonal
+	Sensitivity   float64       `json:"sensitivity,omitempty"`    // Width of the bounds in deviations, default 3
+	WarmupRuns    int           `json:"warmup_runs,omitempty"`    // Runs before bounds are enforced, default 7
+	Seasonality   Seasonality   `json:"seasonality,omitempty"`    // daily or weekly (default), for seasonal
 }
 
 // ThresholdType represents the type of threshold
@@ -155,6 +208,7 @@ const (
 	ThresholdAbsolute   ThresholdType = "absolute"
 	ThresholdPercentage ThresholdType = "percentage"
 	ThresholdRange      ThresholdType = "range"
+	ThresholdAnomaly    ThresholdType = "anomaly" // Bounds learned from the check's own history
 )
 
 // CheckResult represents the result of a check execution
@@ -167,6 +221,9 @@ type CheckResult struct {
 	ExpectedValue interface{}           `json:"expected_value,omitempty"`
 	Message      string                 `json:"message"`
 	Details      map[string]interface{} `json:"details"`
+	FailingRows  *FailingRows           `json:"failing_rows,omitempty"` // Sample of the rows that failed
+	Watermark    *Watermark             `json:"watermark,omitempty"`    // Highest watermark evaluated by an incremental check
+	Segments     []SegmentResult        `json:"segments,omitempty"`     // Per-segment outcomes of a segmented check
 	Duration     time.Duration          `json:"duration"`
 	Timestamp    time.Time              `json:"timestamp"`
 	Error        string                 `json:"error,omitempty"`
@@ -177,6 +234,11 @@ type Manager struct {
 	checks           map[string]*Check
 	results          map[string][]*CheckResult
 	datasourceManager *datasource.Manager
+	tenantBudgets    map[string]*datasource.QueryBudget
+	pool             *workerPool
+	retry            RetryPolicy
+	// mu guards the maps above and the fields of stored checks
+	mu               sync.RWMutex
 }
 
 // NewManager creates a new check manager
@@ -185,11 +247,82 @@ func NewManager(dsManager *datasource.Manager) *Manager {
 		checks:           make(map[string]*Check),
 		results:          make(map[string][]*CheckResult),
 		datasourceManager: dsManager,
+		tenantBudgets:    make(map[string]*datasource.QueryBudget),
+		pool:             newWorkerPool(DefaultConcurrencyLimits),
+		retry:            DefaultRetryPolicy,
 	}
 }
 
 // CreateCheck creates a new data quality check
 func (m *Manager) CreateCheck(ctx context.Context, check *Check) error {
+	if err := m.validateStored(ctx, check); err != nil {
+		return err
+	}
+	initCheck(check)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.validateDependencies(check); err != nil {
+		return fmt.Errorf("invalid check: %w", err)
+	}
+	m.checks[check.ID] = check
+	return nil
+}
+
+// CreateChecks creates several checks at once, such as accepted recommendations.
+// A check may depend on the checks before it. Either every check is created
+// or, when one is invalid, none is.
+func (m *Manager) CreateChecks(ctx context.Context, checks []*Check) error {
+	// Report the invalid fields of every check, prefixed with its index
+	invalid := &ValidationError{}
+	for i, check := range checks {
+		if check == nil {
+			invalid.add(fmt.Sprintf("[%d]", i), "check definition is required")
+			continue
+		}
+		if err, ok := m.validateStored(ctx, check).(*ValidationError); ok {
+			for _, fe := range err.Errors {
+				// Dependencies may be on checks of the batch; they are
+				// validated as the checks are stored
+				if fe.Field == "depends_on" {
+					continue
+				}
+				invalid.add(fmt.Sprintf("[%d].%s", i, fe.Field), "%s", fe.Message)
+			}
+		}
+	}
+	if len(invalid.Errors) > 0 {
+		return invalid
+	}
+
+	// Store the checks in order, so that they may depend on earlier ones,
+	// and restore the previous state if any is refused
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	previous := make(map[string]*Check, len(checks))
+	for i, check := range checks {
+		initCheck(check)
+		if err := m.validateDependencies(check); err != nil {
+			for id, prev := range previous {
+				if prev == nil {
+					delete(m.checks, id)
+				} else {
+					m.checks[id] = prev
+				}
+			}
+			invalid.add(fmt.Sprintf("[%d].depends_on", i), "%v", err)
+			return invalid
+		}
+		if _, seen := previous[check.ID]; !seen {
+			previous[check.ID] = m.checks[check.ID]
+		}
+		m.checks[check.ID] = check
+	}
+	return nil
+}
+
+// initCheck sets the ID, timestamps and initial state of a new check
+func initCheck(check *Check) {
 	if check.ID == "" {
 		check.ID = uuid.New().String()
 	}
@@ -197,13 +330,13 @@ func (m *Manager) CreateCheck(ctx context.Context, check *Check) error {
 	check.UpdatedAt = time.Now()
 	check.Active = true
 	check.LastStatus = StatusPending
-
-	m.checks[check.ID] = check
-	return nil
 }
 
 // GetCheck retrieves a check by ID
 func (m *Manager) GetCheck(ctx context.Context, id string) (*Check, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	check, exists := m.checks[id]
 	if !exists {
 		return nil, fmt.Errorf("check not found: %s", id)
@@ -211,13 +344,75 @@ func (m *Manager) GetCheck(ctx context.Context, id string) (*Check, error) {
 	return check, nil
 }
 
-// UpdateCheck updates a check
+// snapshot copies a stored check for a run
+func (m *Manager) snapshot(check *Check) *Check {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c := *check
+	return &c
+}
+
+// UpdateCheck updates a check. Changes to its definition are validated
+// before any update applies.
 func (m *Manager) UpdateCheck(ctx context.Context, id string, updates map[string]interface{}) error {
+	// Validate the updated definition before locking, as it reads the table's columns
+	if changesDefinition(updates) {
+		existing, err := m.GetCheck(ctx, id)
+		if err != nil {
+			return err
+		}
+		updated := m.snapshot(existing)
+		applyUpdates(updated, updates)
+		if err := m.validateStored(ctx, updated); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	check, exists := m.checks[id]
 	if !exists {
 		return fmt.Errorf("check not found: %s", id)
 	}
+	if dependsOn, ok := updates["depends_on"].([]string); ok {
+		// Checked again under the lock, as other checks may have changed since
+		updated := *check
+		updated.DependsOn = dependsOn
+		if err := m.validateDependencies(&updated); err != nil {
+			return fmt.Errorf("invalid check: %w", err)
+		}
+	}
 
+	applyUpdates(check, updates)
+	check.UpdatedAt = time.Now()
+	return nil
+}
+
+// changesDefinition reports whether updates change what a check evaluates,
+// rather than only how it is named, labelled or activated
+func changesDefinition(updates map[string]interface{}) bool {
+	for key := range updates {
+		switch key {
+		case "name", "description", "active", "severity", "tags":
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// applyUpdates sets the fields of a check named by updates
+func applyUpdates(check *Check, updates map[string]interface{}) {
+	if filters, ok := updates["filters"].([]view.FilterDef); ok {
+		check.Filters = filters
+	}
+	if where, ok := updates["where"].(string); ok {
+		check.Where = where
+	}
+	if dependsOn, ok := updates["depends_on"].([]string); ok {
+		check.DependsOn = dependsOn
+	}
 	if name, ok := updates["name"].(string); ok {
 		check.Name = name
 	}
@@ -239,16 +434,28 @@ func (m *Manager) UpdateCheck(ctx context.Context, id string, updates map[string
 	if tags, ok := updates["tags"].([]string); ok {
 		check.Tags = tags
 	}
-
-	check.UpdatedAt = time.Now()
-	return nil
+	if segmentBy, ok := updates["segment_by"].([]string); ok {
+		check.SegmentBy = segmentBy
+	}
+	if maxSegments, ok := updates["max_segments"].(int); ok {
+		check.MaxSegments = maxSegments
+	}
+	if timeoutSeconds, ok := updates["timeout_seconds"].(int); ok {
+		check.TimeoutSeconds = timeoutSeconds
+	}
 }
 
 // DeleteCheck deletes a check
 func (m *Manager) DeleteCheck(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	if _, exists := m.checks[id]; !exists {
 		return fmt.Errorf("check not found: %s", id)
 	}
+	if dependents := m.dependents(id); len(dependents) > 0 {
+		return fmt.Errorf("check %s is a dependency of %s", id, strings.Join(dependents, ", "))
+	}
 
 	delete(m.checks, id)
 	delete(m.results, id)
@@ -257,6 +464,9 @@ func (m *Manager) DeleteCheck(ctx context.Context, id string) error {
 
 // ListChecks lists checks with optional filters
 func (m *Manager) ListChecks(ctx context.Context, tenantID, datasourceID string) ([]*Check, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	var result []*Check
 	for _, check := range m.checks {
 		if tenantID != "" && check.TenantID != tenantID {
@@ -287,16 +497,64 @@ func (m *Manager) RunCheck(ctx context.Context, id string) (*CheckResult, error)
 		}, nil
 	}
 
+	// Run against a snapshot, so that concurrent updates do not change the
+	// configuration mid-run
+	check = m.snapshot(check)
+
+	// Wait for a run slot and apply the check's timeout
+	ctx, done, err := m.startRun(ctx, check)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	// Attribute the queries this run sends to the check
+	ctx = datasource.WithQueryOrigin(ctx, datasource.QueryOrigin{Type: datasource.OriginCheck, ID: check.ID})
+
 	// Get datasource connector
 	connector, err := m.datasourceManager.GetConnector(ctx, check.DatasourceID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get datasource connector: %w", err)
 	}
 
+	// Estimate query costs and enforce datasource and tenant budgets
+	costGuard := m.withCostEstimation(ctx, check, connector)
+	if costGuard != nil {
+		connector = costGuard
+	}
+
 	startTime := time.Now()
 
-	// Execute check based on type
-	result, err := m.executeCheck(ctx, check, connector)
+	// Execute check based on type, retrying transient failures
+	var result *CheckResult
+	attempts, err := m.withRetries(ctx, func() error {
+		var runErr error
+		result, runErr = m.executeCheck(ctx, check, connector)
+		return runErr
+	})
+	return m.completeRun(ctx, check, connector, costGuard, result, attempts, timeoutError(ctx, err), startTime), nil
+}
+
+// completeRun finishes a check run and stores its result, updating the
+// check's status
+func (m *Manager) completeRun(ctx context.Context, check *Check, connector datasource.Connector, costGuard *budgetedConnector, result *CheckResult, attempts []Attempt, err error, startTime time.Time) *CheckResult {
+	result = m.finishRun(ctx, check, connector, costGuard, result, attempts, err, startTime)
+	m.storeResult(check, result)
+	return result
+}
+
+// finishRun builds a run's result from the outcome of its executor: it
+// applies anomaly thresholds, captures failing rows and records the failed
+// attempts. An executor error becomes an error result.
+func (m *Manager) finishRun(ctx context.Context, check *Check, connector datasource.Connector, costGuard *budgetedConnector, result *CheckResult, attempts []Attempt, err error, startTime time.Time) *CheckResult {
+	id := check.ID
+	executed := err == nil
+	if err == nil && check.Threshold.Type == ThresholdAnomaly {
+		err = m.applyAnomalyThreshold(check, result, time.Now())
+	}
+	if err == nil {
+		m.captureFailingRows(ctx, check, connector, result)
+	}
 	if err != nil {
 		result = &CheckResult{
 			ID:        uuid.New().String(),
@@ -304,6 +562,7 @@ func (m *Manager) RunCheck(ctx context.Context, id string) (*CheckResult, error)
 			DatasourceID: check.DatasourceID,
 			Status:    StatusError,
 			Message:   fmt.Sprintf("check execution failed: %v", err),
+			Details:   make(map[string]interface{}),
 			Error:     err.Error(),
 			Timestamp: time.Now(),
 			Duration:  time.Since(startTime),
@@ -315,21 +574,51 @@ func (m *Manager) RunCheck(ctx context.Context, id string) (*CheckResult, error)
 		result.Duration = time.Since(startTime)
 		result.Timestamp = time.Now()
 	}
+	recordAttempts(result, attempts, executed)
+	if costGuard != nil {
+		costGuard.record(result)
+	}
+	return result
+}
 
-	// Store result
-	m.results[id] = append(m.results[id], result)
-
-	// Update check status
+// storeResult appends a result to a check's history and updates the status
+// of the check and of the stored check it is a snapshot of
+func (m *Manager) storeResult(check *Check, result *CheckResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results[check.ID] = append(m.results[check.ID], result)
 	now := time.Now()
-	check.LastRunAt = &now
-	check.LastStatus = result.Status
-	check.UpdatedAt = now
-
-	return result, nil
+	for _, c := range []*Check{check, m.checks[check.ID]} {
+		if c != nil {
+			c.LastRunAt = &now
+			c.LastStatus = result.Status
+			c.UpdatedAt = now
+		}
+	}
 }
 
 // executeCheck executes the appropriate check based on type
 func (m *Manager) executeCheck(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
+	if check.Parameters.Sample != nil && !supportsSampling(check.Type) {
+		return nil, fmt.Errorf("sampling is not supported for %s checks", check.Type)
+	}
+	if len(check.SegmentBy) > 0 {
+		if !supportsSegments(check.Type) {
+			return nil, fmt.Errorf("segmentation is not supported for %s checks", check.Type)
+		}
+		if check.Threshold.Type == ThresholdAnomaly {
+			return nil, fmt.Errorf("anomaly thresholds are not supported for segmented checks")
+		}
+	}
+	if check.Parameters.Incremental != nil {
+		return m.executeIncremental(ctx, check, connector)
+	}
+
+	return m.runCheckType(ctx, check, connector)
+}
+
+// runCheckType dispatches a check to the executor for its type
+func (m *Manager) runCheckType(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
 	switch check.Type {
 	case TypeRowCount:
 		return m.runRowCountCheck(ctx, check, connector)
@@ -341,18 +630,36 @@ func (m *Manager) executeCheck(ctx context.Context, check *Check, connector data
 		return m.runFreshnessCheck(ctx, check, connector)
 	case TypeCustomSQL:
 		return m.runCustomSQLCheck(ctx, check, connector)
+	case TypeExpression:
+		return m.runExpressionCheck(ctx, check, connector)
 	case TypeMinValue, TypeMaxValue, TypeMeanValue, TypeSumValue:
 		return m.runValueCheck(ctx, check, connector)
+	case TypeStdDev:
+		return m.runStdDevCheck(ctx, check, connector)
 	case TypeRegex:
 		return m.runRegexCheck(ctx, check, connector)
+	case TypeFormat:
+		return m.runFormatCheck(ctx, check, connector)
 	case TypeRange:
 		return m.runRangeCheck(ctx, check, connector)
 	case TypeSetMembership:
 		return m.runSetMembershipCheck(ctx, check, connector)
 	case TypeReferentialIntegrity:
 		return m.runReferentialCheck(ctx, check, connector)
+	case TypeVolume:
+		return m.runVolumeCheck(ctx, check, connector)
+	case TypeDistribution:
+		return m.runDistributionCheck(ctx, check, connector)
+	case TypeReconciliation:
+		return m.runReconciliationCheck(ctx, check, connector)
+	case TypeTableDiff:
+		return m.runTableDiffCheck(ctx, check, connector)
 	case TypeSchemaMatch:
 		return m.runSchemaCheck(ctx, check, connector)
+	case TypeColumnCount:
+		return m.runColumnCountCheck(ctx, check, connector)
+	case TypeColumnType:
+		return m.runColumnTypeCheck(ctx, check, connector)
 	default:
 		return nil, fmt.Errorf("unsupported check type: %s", check.Type)
 	}
@@ -360,35 +667,84 @@ func (m *Manager) executeCheck(ctx context.Context, check *Check, connector data
 
 // GetCheckResults returns results for a check
 func (m *Manager) GetCheckResults(ctx context.Context, checkID string, limit int) ([]*CheckResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	results, exists := m.results[checkID]
 	if !exists {
 		return []*CheckResult{}, nil
 	}
 	
 	if limit > 0 && len(results) > limit {
-		return results[len(results)-limit:], nil
+		results = results[len(results)-limit:]
 	}
-	return results, nil
+	return append([]*CheckResult(nil), results...), nil
 }
 
-// RunChecksForDatasource runs all active checks for a datasource
+// RunChecksForDatasource runs all active checks for a datasource in parallel,
+// within the concurrency limits. Compatible checks reading the same rows are
+// computed together by a single query. A failing check does not stop the
+// others; it gets an error result.
 func (m *Manager) RunChecksForDatasource(ctx context.Context, datasourceID string) ([]*CheckResult, error) {
 	checks, err := m.ListChecks(ctx, "", datasourceID)
 	if err != nil {
 		return nil, err
 	}
 
-	var results []*CheckResult
+	var active []*Check
 	for _, check := range checks {
-		if !check.Active {
-			continue
+		if check = m.snapshot(check); check.Active {
+			active = append(active, check)
 		}
-		result, err := m.RunCheck(ctx, check.ID)
-		if err != nil {
-			return nil, err
+	}
+	if len(active) == 0 {
+		return nil, nil
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].ID < active[j].ID })
+
+	connector, err := m.datasourceManager.GetConnector(ctx, datasourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get datasource connector: %w", err)
+	}
+
+	ids := make([]string, len(active))
+	var independent, dependent []*Check
+	for i, check := range active {
+		ids[i] = check.ID
+		if len(check.DependsOn) > 0 {
+			dependent = append(dependent, check)
+		} else {
+			independent = append(independent, check)
 		}
-		results = append(results, result)
 	}
+	run := newCheckRun(ids)
+
+	// Fuse compatible checks on the same rows into one scan, and run the
+	// batches and the remaining checks in parallel. Checks with dependencies
+	// wait for them, so they are never batched.
+	batches, singles := m.planBatches(ctx, independent, connector)
+	singles = append(singles, dependent...)
+
+	var wg sync.WaitGroup
+	for _, b := range batches {
+		wg.Add(1)
+		go func(b *batch) {
+			defer wg.Done()
+			run.record(m.runBatch(ctx, b, connector)...)
+		}(b)
+	}
+	for _, check := range singles {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			m.runInOrder(ctx, run, id)
+		}(check.ID)
+	}
+	wg.Wait()
 
+	results := make([]*CheckResult, 0, len(active))
+	for _, id := range ids {
+		results = append(results, run.results[id])
+	}
 	return results, nil
 }
@@ -212,6 +212,7 @@ func TestManager_ListChecks(t *testing.T) {
 		Name:         "Check 2",
 		Type:         TypeNullCheck,
 		Table:        "orders",
+		Column:       "email",
 	})
 
 	// List all
@@ -246,3 +247,54 @@ func TestThresholdType_Values(t *testing.T) {
 		}
 	}
 }
+
+// fakeConnector records queries and returns canned results
+type fakeConnector struct {
+	dsType  datasource.Type
+	rows    []map[string]interface{}
+	columns []datasource.ColumnInfo
+	tables  map[string][]datasource.ColumnInfo // Columns by table name, when set
+	err     error
+	queries []string
+}
+
+func (c *fakeConnector) Connect(ctx context.Context) error { return nil }
+func (c *fakeConnector) Close() error                      { return nil }
+func (c *fakeConnector) Ping(ctx context.Context) error    { return nil }
+
+func (c *fakeConnector) Query(ctx context.Context, query string, args ...interface{}) (*datasource.QueryResult, error) {
+	c.queries = append(c.queries, query)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &datasource.QueryResult{Rows: c.rows, RowCount: int64(len(c.rows))}, nil
+}
+
+func (c *fakeConnector) GetTables(ctx context.Context) ([]datasource.TableInfo, error) {
+	return nil, c.err
+}
+
+func (c *fakeConnector) GetColumns(ctx context.Context, table string) ([]datasource.ColumnInfo, error) {
+	if c.tables != nil {
+		return c.tables[table], c.err
+	}
+	return c.columns, c.err
+}
+
+func (c *fakeConnector) GetRowCount(ctx context.Context, table string) (int64, error) {
+	if len(c.rows) > 0 {
+		return toInt64(c.rows[0]["count"]), c.err
+	}
+	return 0, c.err
+}
+
+func (c *fakeConnector) GetPartitions(ctx context.Context, table string) ([]datasource.PartitionInfo, error) {
+	return []datasource.PartitionInfo{}, c.err
+}
+
+func (c *fakeConnector) Type() datasource.Type {
+	if c.dsType == "" {
+		return datasource.TypePostgres
+	}
+	return c.dsType
+}
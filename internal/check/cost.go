@@ -0,0 +1,148 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// budgetedConnector estimates the cost of every query a check run sends and
+// refuses queries once the run's cumulative estimate exceeds its budget
+type budgetedConnector struct {
+	datasource.Connector
+	estimator datasource.CostEstimator
+	budget    datasource.QueryBudget
+	total     datasource.CostEstimate
+	queries   int
+	estimated bool
+}
+
+func newBudgetedConnector(connector datasource.Connector, estimator datasource.CostEstimator, budget datasource.QueryBudget) *budgetedConnector {
+	return &budgetedConnector{Connector: connector, estimator: estimator, budget: budget}
+}
+
+// Query estimates the query's cost and executes it if the run stays within budget
+func (c *budgetedConnector) Query(ctx context.Context, query string, args ...interface{}) (*datasource.QueryResult, error) {
+	estimate, err := c.estimator.EstimateCost(ctx, query, args...)
+	switch {
+	case errors.Is(err, datasource.ErrCostEstimationUnsupported):
+		// Budgets cannot be enforced on engines without an estimator
+	case err != nil:
+		return nil, fmt.Errorf("failed to estimate query cost: %w", err)
+	default:
+		c.estimated = true
+		c.queries++
+		c.total.Method = estimate.Method
+		c.total.BytesScanned += estimate.BytesScanned
+		c.total.CostUSD += estimate.CostUSD
+		c.total.PlannerCost += estimate.PlannerCost
+		if err := c.budget.Check(c.total); err != nil {
+			return nil, fmt.Errorf("query refused: %w", err)
+		}
+	}
+	return c.Connector.Query(ctx, query, args...)
+}
+
+// record records the run's cumulative cost estimate and budget in result details
+func (c *budgetedConnector) record(result *CheckResult) {
+	if !c.estimated {
+		return
+	}
+	info := map[string]interface{}{
+		"method":        c.total.Method,
+		"queries":       c.queries,
+		"bytes_scanned": c.total.BytesScanned,
+	}
+	if c.total.CostUSD > 0 {
+		info["cost_usd"] = c.total.CostUSD
+	}
+	if c.total.PlannerCost > 0 {
+		info["planner_cost"] = c.total.PlannerCost
+	}
+	if !c.budget.IsZero() {
+		info["budget"] = c.budget
+	}
+	result.Details["cost_estimate"] = info
+}
+
+// SetTenantQueryBudget sets the query budget applied to every check run of a
+// tenant. A nil budget removes it.
+func (m *Manager) SetTenantQueryBudget(ctx context.Context, tenantID string, budget *datasource.QueryBudget) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if budget == nil {
+		delete(m.tenantBudgets, tenantID)
+		return nil
+	}
+	m.tenantBudgets[tenantID] = budget
+	return nil
+}
+
+// GetTenantQueryBudget returns the query budget of a tenant, or nil if unset
+func (m *Manager) GetTenantQueryBudget(ctx context.Context, tenantID string) (*datasource.QueryBudget, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.tenantBudgets[tenantID], nil
+}
+
+// queryBudget returns the tighter of the datasource and tenant budgets for a check
+func (m *Manager) queryBudget(ctx context.Context, check *Check) datasource.QueryBudget {
+	return m.budgetFor(ctx, check.DatasourceID, check.TenantID)
+}
+
+// budgetFor returns the tighter of a datasource's budget and a tenant's
+func (m *Manager) budgetFor(ctx context.Context, datasourceID, tenantID string) datasource.QueryBudget {
+	var budget datasource.QueryBudget
+	if ds, err := m.datasourceManager.GetDatasource(ctx, datasourceID); err == nil && ds.QueryBudget != nil {
+		budget = budget.Merge(*ds.QueryBudget)
+	}
+	tenantBudget, _ := m.GetTenantQueryBudget(ctx, tenantID)
+	if tenantBudget != nil {
+		budget = budget.Merge(*tenantBudget)
+	}
+	return budget
+}
+
+// BudgetConnector wraps a datasource's connector to refuse queries once their
+// cumulative estimated cost exceeds the budgets of the datasource and its
+// tenant, as check runs do. It returns connector itself when no budget applies
+// or the connector cannot estimate. Table diffs budget each side this way.
+func (m *Manager) BudgetConnector(ctx context.Context, datasourceID string, connector datasource.Connector) datasource.Connector {
+	var tenantID string
+	if ds, err := m.datasourceManager.GetDatasource(ctx, datasourceID); err == nil {
+		tenantID = ds.TenantID
+	}
+	return withBudget(connector, m.budgetFor(ctx, datasourceID, tenantID))
+}
+
+// withBudget wraps a connector to enforce budget, or returns it as is when
+// the budget is zero or the connector cannot estimate
+func withBudget(connector datasource.Connector, budget datasource.QueryBudget) datasource.Connector {
+	if budget.IsZero() {
+		return connector
+	}
+	estimator, ok := connector.(datasource.CostEstimator)
+	if !ok {
+		return connector
+	}
+	return newBudgetedConnector(connector, estimator, budget)
+}
+
+// withCostEstimation wraps a connector to estimate query costs when a budget
+// applies to the check or the check asks for estimates. It returns nil when
+// estimation is disabled or the connector cannot estimate.
+func (m *Manager) withCostEstimation(ctx context.Context, check *Check, connector datasource.Connector) *budgetedConnector {
+	budget := m.queryBudget(ctx, check)
+	if budget.IsZero() && !check.Parameters.EstimateCost {
+		return nil
+	}
+	estimator, ok := connector.(datasource.CostEstimator)
+	if !ok {
+		return nil
+	}
+	return newBudgetedConnector(connector, estimator, budget)
+}
@@ -0,0 +1,96 @@
+package check
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// estimatingConnector is a fakeConnector that also estimates query costs
+type estimatingConnector struct {
+	fakeConnector
+	estimate datasource.CostEstimate
+}
+
+func (c *estimatingConnector) EstimateCost(ctx context.Context, query string, args ...interface{}) (*datasource.CostEstimate, error) {
+	estimate := c.estimate
+	return &estimate, nil
+}
+
+func TestCostEstimation_WithinBudget(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	ctx := context.Background()
+	m.SetTenantQueryBudget(ctx, "tenant-1", &datasource.QueryBudget{MaxBytesScanned: 1 << 30})
+
+	connector := &estimatingConnector{
+		fakeConnector: fakeConnector{rows: []map[string]interface{}{{"count": int64(10)}}},
+		estimate:      datasource.CostEstimate{BytesScanned: 1 << 20, Method: "postgres_explain"},
+	}
+	check := &Check{TenantID: "tenant-1", Type: TypeCustomSQL, Parameters: CheckParameters{CustomSQL: "SELECT COUNT(*) as count FROM t"}}
+
+	guard := m.withCostEstimation(ctx, check, connector)
+	if guard == nil {
+		t.Fatal("expected cost estimation to be enabled by the tenant budget")
+	}
+	result, err := m.executeCheck(ctx, check, guard)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	guard.record(result)
+
+	info, ok := result.Details["cost_estimate"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected cost estimate to be recorded")
+	}
+	if info["bytes_scanned"] != int64(1<<20) {
+		t.Errorf("expected 1 MiB estimate, got %v", info["bytes_scanned"])
+	}
+}
+
+func TestCostEstimation_OverBudget(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	ctx := context.Background()
+	m.SetTenantQueryBudget(ctx, "tenant-1", &datasource.QueryBudget{MaxCostUSD: 0.5})
+
+	connector := &estimatingConnector{
+		estimate: datasource.CostEstimate{BytesScanned: 1 << 40, CostUSD: 6.25, Method: "bigquery_dry_run"},
+	}
+	check := &Check{TenantID: "tenant-1", Type: TypeCustomSQL, Parameters: CheckParameters{CustomSQL: "SELECT * FROM huge"}}
+
+	guard := m.withCostEstimation(ctx, check, connector)
+	if _, err := m.executeCheck(ctx, check, guard); err == nil {
+		t.Fatal("expected over-budget query to be refused")
+	}
+	if len(connector.queries) != 0 {
+		t.Errorf("expected refused query not to be executed, got %v", connector.queries)
+	}
+}
+
+func TestCostEstimation_Disabled(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	check := &Check{TenantID: "tenant-1", Type: TypeRowCount}
+
+	if guard := m.withCostEstimation(context.Background(), check, &estimatingConnector{}); guard != nil {
+		t.Error("expected no estimation without a budget or estimate_cost")
+	}
+}
+
+func TestWithBudget(t *testing.T) {
+	connector := &estimatingConnector{estimate: datasource.CostEstimate{BytesScanned: 1 << 30, Method: "postgres_explain"}}
+
+	if got := withBudget(connector, datasource.QueryBudget{}); got != datasource.Connector(connector) {
+		t.Error("expected a connector without a budget to be returned as is")
+	}
+	if got := withBudget(&fakeConnector{}, datasource.QueryBudget{MaxBytesScanned: 1}); got == nil {
+		t.Error("expected a connector that cannot estimate to be returned")
+	}
+
+	guarded := withBudget(connector, datasource.QueryBudget{MaxBytesScanned: 1 << 20})
+	if _, err := guarded.Query(context.Background(), "SELECT * FROM huge"); err == nil {
+		t.Error("expected the query to be refused over budget")
+	}
+	if len(connector.queries) != 0 {
+		t.Errorf("expected no query to run, got %v", connector.queries)
+	}
+}
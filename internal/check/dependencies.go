@@ -0,0 +1,264 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// validateDependencies checks that a check's dependencies exist and that
+// adding its edges to the stored checks forms no cycle. Callers hold m.mu.
+func (m *Manager) validateDependencies(check *Check) error {
+	for _, dep := range check.DependsOn {
+		if dep == check.ID {
+			return fmt.Errorf("check cannot depend on itself")
+		}
+		if _, ok := m.checks[dep]; !ok {
+			return fmt.Errorf("dependency not found: %s", dep)
+		}
+	}
+
+	graph := make(map[string][]string, len(m.checks)+1)
+	for id, c := range m.checks {
+		graph[id] = c.DependsOn
+	}
+	graph[check.ID] = check.DependsOn
+	if cycle := findCycle(graph); cycle != nil {
+		return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// findCycle returns a cycle of a dependency graph as the path of check IDs
+// leading back to its first, or nil if the graph is acyclic
+func findCycle(graph map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(graph))
+	var path []string
+
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		state[id] = visiting
+		path = append(path, id)
+		for _, dep := range graph[id] {
+			switch state[dep] {
+			case visiting:
+				for i, p := range path {
+					if p == dep {
+						return append(append([]string{}, path[i:]...), dep)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = visited
+		return nil
+	}
+
+	ids := make([]string, 0, len(graph))
+	for id := range graph {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if state[id] == unvisited {
+			if cycle := visit(id); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// dependents returns the IDs of the stored checks depending on a check.
+// Callers hold m.mu.
+func (m *Manager) dependents(id string) []string {
+	var ids []string
+	for _, c := range m.checks {
+		for _, dep := range c.DependsOn {
+			if dep == id {
+				ids = append(ids, c.ID)
+				break
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// checkRun collects the results of a run of several checks, so that each
+// check can wait for the checks it depends on
+type checkRun struct {
+	mu      sync.Mutex
+	results map[string]*CheckResult
+	done    map[string]chan struct{}
+}
+
+func newCheckRun(ids []string) *checkRun {
+	run := &checkRun{
+		results: make(map[string]*CheckResult, len(ids)),
+		done:    make(map[string]chan struct{}, len(ids)),
+	}
+	for _, id := range ids {
+		run.done[id] = make(chan struct{})
+	}
+	return run
+}
+
+// record stores results and releases the checks waiting for them. Only the
+// first result of a check counts.
+func (r *checkRun) record(results ...*CheckResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, result := range results {
+		if _, ok := r.results[result.CheckID]; ok {
+			continue
+		}
+		r.results[result.CheckID] = result
+		if done, ok := r.done[result.CheckID]; ok {
+			close(done)
+		}
+	}
+}
+
+// result returns the result of a check of the run; ok is false for checks
+// outside the run
+func (r *checkRun) result(id string) (result *CheckResult, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok = r.done[id]; ok {
+		result = r.results[id]
+	}
+	return result, ok
+}
+
+// wait waits until the checks of deps that are part of the run have results
+func (r *checkRun) wait(ctx context.Context, deps []string) error {
+	for _, dep := range deps {
+		done, ok := r.done[dep]
+		if !ok {
+			continue
+		}
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// runInOrder runs a check of a run once the checks it depends on have run,
+// and skips it if one of them did not pass
+func (m *Manager) runInOrder(ctx context.Context, run *checkRun, id string) {
+	check, err := m.GetCheck(ctx, id)
+	if err != nil {
+		run.record(errorResult(id, err))
+		return
+	}
+	check = m.snapshot(check)
+
+	if check.Active && len(check.DependsOn) > 0 {
+		if err := run.wait(ctx, check.DependsOn); err != nil {
+			run.record(errorResult(id, fmt.Errorf("waiting for dependencies: %w", err)))
+			return
+		}
+		if blocker, status := m.blockingDependency(run, check); blocker != "" {
+			run.record(m.skipRun(check, blocker, status))
+			return
+		}
+	}
+
+	result, err := m.RunCheck(ctx, id)
+	if err != nil {
+		result = errorResult(id, err)
+	}
+	run.record(result)
+}
+
+// blockingDependency returns the first dependency of a check that failed,
+// errored or was itself skipped for a dependency, with its status. Checks in
+// the run are judged by their result in it, others by their last status;
+// inactive dependencies never block.
+func (m *Manager) blockingDependency(run *checkRun, check *Check) (string, Status) {
+	for _, dep := range check.DependsOn {
+		if result, ok := run.result(dep); ok {
+			if blocks(result) {
+				return dep, result.Status
+			}
+			continue
+		}
+
+		m.mu.RLock()
+		stored, ok := m.checks[dep]
+		var active bool
+		var status Status
+		if ok {
+			active, status = stored.Active, stored.LastStatus
+		}
+		m.mu.RUnlock()
+		if active && (status == StatusFailed || status == StatusError || status == StatusSkipped) {
+			return dep, status
+		}
+	}
+	return "", ""
+}
+
+// blocks reports whether a dependency's result keeps its dependents from running
+func blocks(result *CheckResult) bool {
+	switch result.Status {
+	case StatusFailed, StatusError:
+		return true
+	case StatusSkipped:
+		_, blocked := result.Details["blocked_by"]
+		return blocked
+	default:
+		return false
+	}
+}
+
+// skipRun records that a check was skipped because a dependency did not pass
+func (m *Manager) skipRun(check *Check, blocker string, status Status) *CheckResult {
+	m.mu.RLock()
+	name := blocker
+	if dep, ok := m.checks[blocker]; ok && dep.Name != "" {
+		name = fmt.Sprintf("%q (%s)", dep.Name, blocker)
+	}
+	m.mu.RUnlock()
+
+	outcome := string(status)
+	switch status {
+	case StatusError:
+		outcome = "errored"
+	case StatusSkipped:
+		outcome = "was skipped"
+	}
+
+	result := &CheckResult{
+		ID:           uuid.New().String(),
+		CheckID:      check.ID,
+		DatasourceID: check.DatasourceID,
+		Status:       StatusSkipped,
+		Message:      fmt.Sprintf("skipped: dependency %s %s", name, outcome),
+		Details: map[string]interface{}{
+			"blocked_by":        blocker,
+			"blocked_by_status": status,
+		},
+		Timestamp: time.Now(),
+	}
+	m.storeResult(check, result)
+	return result
+}
@@ -0,0 +1,133 @@
+package check
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+func createChecks(t *testing.T, m *Manager, checks ...*Check) {
+	t.Helper()
+	for _, check := range checks {
+		if err := m.CreateCheck(context.Background(), check); err != nil {
+			t.Fatalf("unexpected error creating %s: %v", check.ID, err)
+		}
+	}
+}
+
+func TestCreateCheck_ValidatesDependencies(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(datasource.NewManager())
+	createChecks(t, m,
+		&Check{ID: "rows", Type: TypeRowCount, Table: "orders"},
+		&Check{ID: "nulls", Type: TypeNullCheck, Table: "orders", Column: "email", DependsOn: []string{"rows"}},
+	)
+
+	if err := m.CreateCheck(ctx, &Check{ID: "self", Type: TypeRowCount, Table: "orders", DependsOn: []string{"self"}}); err == nil {
+		t.Error("expected a self-dependency to be rejected")
+	}
+	if err := m.CreateCheck(ctx, &Check{ID: "orphan", Type: TypeRowCount, Table: "orders", DependsOn: []string{"missing"}}); err == nil {
+		t.Error("expected an unknown dependency to be rejected")
+	}
+
+	err := m.UpdateCheck(ctx, "rows", map[string]interface{}{"depends_on": []string{"nulls"}})
+	if err == nil || !strings.Contains(err.Error(), "dependency cycle: nulls -> rows -> nulls") {
+		t.Errorf("expected a cycle to be rejected, got %v", err)
+	}
+	if check, _ := m.GetCheck(ctx, "rows"); len(check.DependsOn) != 0 {
+		t.Errorf("expected a rejected update not to apply, got %v", check.DependsOn)
+	}
+
+	if err := m.DeleteCheck(ctx, "rows"); err == nil || !strings.Contains(err.Error(), "dependency of nulls") {
+		t.Errorf("expected deleting a dependency to be refused, got %v", err)
+	}
+}
+
+func TestCreateChecks_Atomic(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(datasource.NewManager())
+	createChecks(t, m, &Check{ID: "rows", Type: TypeRowCount, Table: "orders", Description: "original"})
+
+	err := m.CreateChecks(ctx, []*Check{
+		{ID: "rows", Type: TypeRowCount, Table: "orders", Description: "replacement"},
+		{ID: "nulls", Type: TypeNullCheck, Table: "orders", Column: "email", DependsOn: []string{"rows"}},
+		{ID: "orphan", Type: TypeRowCount, Table: "orders", DependsOn: []string{"missing"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "[2].depends_on: dependency not found: missing") {
+		t.Fatalf("expected the unknown dependency to be rejected, got %v", err)
+	}
+
+	checks, _ := m.ListChecks(ctx, "", "")
+	if len(checks) != 1 {
+		t.Fatalf("expected no check to be created, got %d checks", len(checks))
+	}
+	if check, _ := m.GetCheck(ctx, "rows"); check.Description != "original" {
+		t.Errorf("expected the replaced check to be restored, got %q", check.Description)
+	}
+
+	// Later checks may depend on earlier ones
+	if err := m.CreateChecks(ctx, []*Check{
+		{ID: "amounts", Type: TypeRowCount, Table: "payments"},
+		{ID: "amount_nulls", Type: TypeNullCheck, Table: "payments", Column: "amount", DependsOn: []string{"amounts"}},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFindCycle(t *testing.T) {
+	acyclic := map[string][]string{"a": nil, "b": {"a"}, "c": {"a", "b"}}
+	if cycle := findCycle(acyclic); cycle != nil {
+		t.Errorf("expected no cycle, got %v", cycle)
+	}
+
+	cyclic := map[string][]string{"a": {"c"}, "b": {"a"}, "c": {"b"}, "d": {"a"}}
+	if cycle := findCycle(cyclic); strings.Join(cycle, ",") != "a,c,b,a" {
+		t.Errorf("expected the cycle a, c, b, got %v", cycle)
+	}
+}
+
+func TestRunChecks_SkipsDependentsOfFailedChecks(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(datasource.NewManager())
+	// No datasource is registered, so every check that runs errors
+	createChecks(t, m,
+		&Check{ID: "rows", Name: "orders row count", Type: TypeRowCount, Table: "orders"},
+		&Check{ID: "nulls", Type: TypeNullCheck, Table: "orders", Column: "email", DependsOn: []string{"rows"}},
+		&Check{ID: "max", Type: TypeMaxValue, Table: "orders", Column: "amount", DependsOn: []string{"nulls"}},
+		&Check{ID: "paused", Type: TypeRowCount, Table: "customers"},
+		&Check{ID: "emails", Type: TypeNullCheck, Table: "customers", Column: "email", DependsOn: []string{"paused"}},
+	)
+	if err := m.UpdateCheck(ctx, "paused", map[string]interface{}{"active": false}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results := m.RunChecks(ctx, []string{"max", "nulls", "rows", "emails", "paused"})
+
+	byID := make(map[string]*CheckResult)
+	for _, result := range results {
+		byID[result.CheckID] = result
+	}
+	if byID["rows"].Status != StatusError {
+		t.Fatalf("expected rows to error, got %s", byID["rows"].Status)
+	}
+	nulls := byID["nulls"]
+	if nulls.Status != StatusSkipped || nulls.Details["blocked_by"] != "rows" || nulls.Message != `skipped: dependency "orders row count" (rows) errored` {
+		t.Errorf("expected nulls to be skipped naming rows, got %s: %s", nulls.Status, nulls.Message)
+	}
+	if max := byID["max"]; max.Status != StatusSkipped || max.Message != "skipped: dependency nulls was skipped" {
+		t.Errorf("expected max to be skipped transitively, got %s: %s", max.Status, max.Message)
+	}
+	if emails := byID["emails"]; emails.Status != StatusError {
+		t.Errorf("expected an inactive dependency not to block, got %s: %s", emails.Status, emails.Message)
+	}
+
+	// Skips are recorded, so a later run of max alone is blocked by nulls' last status
+	if history, _ := m.GetCheckResults(ctx, "nulls", 0); len(history) != 1 || history[0].Status != StatusSkipped {
+		t.Errorf("expected the skip in the history, got %v", history)
+	}
+	if result := m.RunChecks(ctx, []string{"max"})[0]; result.Status != StatusSkipped {
+		t.Errorf("expected max to be blocked by the last status of nulls, got %s", result.Status)
+	}
+}
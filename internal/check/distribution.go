@@ -0,0 +1,525 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// DistributionMethod is the statistic used to compare a distribution to its baseline
+type DistributionMethod string
+
+const (
+	DistributionPSI       DistributionMethod = "psi"        // Population stability index
+	DistributionKS        DistributionMethod = "ks"         // Kolmogorov-Smirnov distance, numeric columns only
+	DistributionChiSquare DistributionMethod = "chi_square" // Chi-square goodness of fit p-value
+)
+
+const (
+	defaultDistributionBuckets = 10
+	// maxCategories is the number of baseline categories kept; the rest share one bucket
+	maxCategories = 50
+	// otherCategory collects values outside the baseline categories
+	otherCategory = "__other__"
+	// psiEpsilon replaces empty bucket proportions so PSI stays finite
+	psiEpsilon = 0.0001
+)
+
+// defaultDistributionThresholds are the fail thresholds used when the check
+// sets none: PSI above 0.25, KS distance above 0.1, chi-square p-value below 0.05
+var defaultDistributionThresholds = map[DistributionMethod]float64{
+	DistributionPSI:       0.25,
+	DistributionKS:        0.1,
+	DistributionChiSquare: 0.05,
+}
+
+// Distribution is a column's value distribution: equal-width numeric buckets
+// or categorical frequencies
+type Distribution struct {
+	Categorical bool                 `json:"categorical"`
+	Buckets     []DistributionBucket `json:"buckets"`
+	Total       int64                `json:"total"`
+	CapturedAt  time.Time            `json:"captured_at"`
+}
+
+// DistributionBucket is a numeric bucket [Lower, Upper) or a category
+type DistributionBucket struct {
+	Label string   `json:"label"`
+	Lower *float64 `json:"lower,omitempty"`
+	Upper *float64 `json:"upper,omitempty"`
+	Count int64    `json:"count"`
+}
+
+// BucketComparison compares a bucket's baseline and current share of values
+type BucketComparison struct {
+	Label              string  `json:"label"`
+	BaselineCount      int64   `json:"baseline_count"`
+	CurrentCount       int64   `json:"current_count"`
+	BaselinePercentage float64 `json:"baseline_percentage"`
+	CurrentPercentage  float64 `json:"current_percentage"`
+	Contribution       float64 `json:"contribution,omitempty"` // Bucket's term of the PSI or chi-square statistic
+}
+
+// runDistributionCheck compares a column's distribution to a stored baseline.
+// The first run captures the baseline into the check's parameters and passes.
+func (m *Manager) runDistributionCheck(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
+	params := &check.Parameters
+	method := params.DistributionMethod
+	if method == "" {
+		method = DistributionPSI
+	}
+	limit, ok := defaultDistributionThresholds[method]
+	if !ok {
+		return nil, fmt.Errorf("unsupported distribution method: %s", method)
+	}
+	if check.Threshold.Value > 0 {
+		limit = check.Threshold.Value
+	}
+
+	from, err := tableRef(ctx, check, connector, "")
+	if err != nil {
+		return nil, err
+	}
+
+	baseline := params.Baseline
+	if baseline == nil {
+		baseline, err := captureDistribution(ctx, check, connector, from)
+		if err != nil {
+			return nil, err
+		}
+		params.Baseline = baseline
+		m.storeBaseline(check.ID, baseline)
+
+		result := &CheckResult{
+			Status:  StatusPassed,
+			Message: fmt.Sprintf("captured baseline distribution of %d values in %d buckets", baseline.Total, len(baseline.Buckets)),
+			Details: map[string]interface{}{
+				"baseline": baseline,
+			},
+		}
+		recordSample(result, check, baseline.Total)
+		return result, nil
+	}
+
+	if method == DistributionKS && baseline.Categorical {
+		return nil, fmt.Errorf("ks distribution method requires a numeric column")
+	}
+
+	current, err := measureDistribution(ctx, check, connector, from, baseline)
+	if err != nil {
+		return nil, err
+	}
+	if current.Total == 0 {
+		return nil, fmt.Errorf("no non-null values to compare with the baseline")
+	}
+
+	comparisons := compareBuckets(baseline, current)
+	details := map[string]interface{}{
+		"method":               method,
+		"threshold":            limit,
+		"buckets":              comparisons,
+		"baseline_total":       baseline.Total,
+		"current_total":        current.Total,
+		"baseline_captured_at": baseline.CapturedAt,
+	}
+
+	var statistic float64
+	var name string
+	operator := OperatorLte
+	switch method {
+	case DistributionPSI:
+		statistic, name = populationStabilityIndex(comparisons), "population stability index"
+	case DistributionKS:
+		statistic, name = ksDistance(comparisons), "ks distance"
+	case DistributionChiSquare:
+		// The p-value is compared so the threshold does not depend on row counts
+		var chiSquare float64
+		chiSquare, statistic = chiSquareTest(comparisons, current.Total)
+		name, operator = "chi-square p-value", OperatorGte
+		details["chi_square"] = chiSquare
+	}
+	details["statistic"] = statistic
+
+	result := &CheckResult{
+		ActualValue: statistic,
+		Details:     details,
+	}
+	recordSample(result, check, current.Total)
+
+	if err := evaluateThreshold(result, check.Threshold, metric{name: name, value: statistic}, Threshold{Type: ThresholdAbsolute, Operator: operator, Value: limit}); err != nil {
+		return nil, err
+	}
+	switch result.Status {
+	case StatusFailed:
+		result.Message = "distribution drifted from baseline: " + result.Message
+	case StatusWarning:
+		result.Message = "distribution is drifting from baseline: " + result.Message
+	default:
+		result.Message = "distribution is stable: " + result.Message
+	}
+
+	return result, nil
+}
+
+// ResetDistributionBaseline clears a distribution check's baseline so the next
+// run captures a new one
+func (m *Manager) ResetDistributionBaseline(ctx context.Context, id string) error {
+	check, err := m.GetCheck(ctx, id)
+	if err != nil {
+		return err
+	}
+	if check.Type != TypeDistribution {
+		return fmt.Errorf("check %s is not a distribution check", id)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	check.Parameters.Baseline = nil
+	check.UpdatedAt = time.Now()
+	return nil
+}
+
+// storeBaseline saves a captured baseline on the stored check, which a run
+// only reads a snapshot of
+func (m *Manager) storeBaseline(id string, baseline *Distribution) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if stored, ok := m.checks[id]; ok {
+		stored.Parameters.Baseline = baseline
+	}
+}
+
+// captureDistribution measures a new baseline: equal-width buckets between the
+// column's current bounds for numeric columns, the most frequent values otherwise
+func captureDistribution(ctx context.Context, check *Check, connector datasource.Connector, from string) (*Distribution, error) {
+	categorical, err := isCategorical(ctx, check, connector)
+	if err != nil {
+		return nil, err
+	}
+
+	var baseline *Distribution
+	if categorical {
+		frequencies, err := categoryFrequencies(ctx, check, connector, from)
+		if err != nil {
+			return nil, err
+		}
+		baseline = categoricalBaseline(frequencies)
+	} else {
+		query := fmt.Sprintf("SELECT MIN(%s) as min_value, MAX(%s) as max_value FROM %s", check.Column, check.Column, from)
+		queryResult, err := connector.Query(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute distribution bounds query: %w", err)
+		}
+		if len(queryResult.Rows) == 0 || queryResult.Rows[0]["min_value"] == nil {
+			return nil, fmt.Errorf("no non-null values to capture a baseline from")
+		}
+
+		buckets := check.Parameters.DistributionBuckets
+		if buckets <= 0 {
+			buckets = defaultDistributionBuckets
+		}
+		baseline = numericBuckets(toFloat64(queryResult.Rows[0]["min_value"]), toFloat64(queryResult.Rows[0]["max_value"]), buckets)
+		if err := countNumericBuckets(ctx, check, connector, from, baseline); err != nil {
+			return nil, err
+		}
+	}
+
+	baseline.CapturedAt = time.Now()
+	return baseline, nil
+}
+
+// measureDistribution counts the column's current values into the baseline's buckets
+func measureDistribution(ctx context.Context, check *Check, connector datasource.Connector, from string, baseline *Distribution) (*Distribution, error) {
+	current := &Distribution{Categorical: baseline.Categorical, CapturedAt: time.Now()}
+	for _, b := range baseline.Buckets {
+		current.Buckets = append(current.Buckets, DistributionBucket{Label: b.Label, Lower: b.Lower, Upper: b.Upper})
+	}
+
+	if !baseline.Categorical {
+		if err := countNumericBuckets(ctx, check, connector, from, current); err != nil {
+			return nil, err
+		}
+		return current, nil
+	}
+
+	frequencies, err := categoryFrequencies(ctx, check, connector, from)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]int, len(current.Buckets))
+	for i, b := range current.Buckets {
+		index[b.Label] = i
+	}
+	other, hasOther := index[otherCategory]
+	for value, count := range frequencies {
+		i, known := index[value]
+		if !known {
+			if !hasOther {
+				// New categories need a bucket of their own to register as drift
+				current.Buckets = append(current.Buckets, DistributionBucket{Label: otherCategory})
+				other, hasOther = len(current.Buckets)-1, true
+				index[otherCategory] = other
+			}
+			i = other
+		}
+		current.Buckets[i].Count += count
+		current.Total += count
+	}
+	return current, nil
+}
+
+// isCategorical reports whether the check column should be compared by
+// category rather than numeric buckets. The table is passed as configured;
+// connectors resolve schema-qualified names such as sales.orders.
+func isCategorical(ctx context.Context, check *Check, connector datasource.Connector) (bool, error) {
+	columns, err := connector.GetColumns(ctx, check.Table)
+	if err != nil {
+		return false, fmt.Errorf("failed to get table columns: %w", err)
+	}
+	for _, col := range columns {
+		if col.Name == check.Column {
+			return datasource.ClassifyType(col.DataType) != datasource.KindNumeric, nil
+		}
+	}
+	return false, fmt.Errorf("column not found: %s", check.Column)
+}
+
+// categoryFrequencies returns the count of every non-null value of the check column
+func categoryFrequencies(ctx context.Context, check *Check, connector datasource.Connector, from string) (map[string]int64, error) {
+	dialect := datasource.DialectFor(connector.Type())
+	query := fmt.Sprintf("SELECT %s as value, COUNT(*) as frequency FROM %s WHERE %s IS NOT NULL GROUP BY %s",
+		dialect.CastText(check.Column), from, check.Column, check.Column)
+
+	queryResult, err := connector.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute distribution query: %w", err)
+	}
+
+	frequencies := make(map[string]int64, len(queryResult.Rows))
+	for _, row := range queryResult.Rows {
+		frequencies[fmt.Sprintf("%v", row["value"])] += toInt64(row["frequency"])
+	}
+	return frequencies, nil
+}
+
+// categoricalBaseline keeps the most frequent categories and folds the rest
+// into a single bucket
+func categoricalBaseline(frequencies map[string]int64) *Distribution {
+	values := make([]string, 0, len(frequencies))
+	for value := range frequencies {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if frequencies[values[i]] != frequencies[values[j]] {
+			return frequencies[values[i]] > frequencies[values[j]]
+		}
+		return values[i] < values[j]
+	})
+
+	baseline := &Distribution{Categorical: true}
+	var other int64
+	for i, value := range values {
+		baseline.Total += frequencies[value]
+		if i < maxCategories {
+			baseline.Buckets = append(baseline.Buckets, DistributionBucket{Label: value, Count: frequencies[value]})
+		} else {
+			other += frequencies[value]
+		}
+	}
+	if other > 0 {
+		baseline.Buckets = append(baseline.Buckets, DistributionBucket{Label: otherCategory, Count: other})
+	}
+	return baseline
+}
+
+// numericBuckets builds equal-width buckets over [min, max]. The first and
+// last buckets are open-ended so later values outside the bounds still count.
+func numericBuckets(min, max float64, buckets int) *Distribution {
+	if max <= min {
+		buckets = 1
+	}
+	width := (max - min) / float64(buckets)
+	distribution := &Distribution{}
+	for i := 0; i < buckets; i++ {
+		var lower, upper *float64
+		if i > 0 {
+			l := min + float64(i)*width
+			lower = &l
+		}
+		if i < buckets-1 {
+			u := min + float64(i+1)*width
+			upper = &u
+		}
+		distribution.Buckets = append(distribution.Buckets, DistributionBucket{
+			Label: bucketLabel(lower, upper),
+			Lower: lower,
+			Upper: upper,
+		})
+	}
+	return distribution
+}
+
+func bucketLabel(lower, upper *float64) string {
+	lo, hi := "-inf", "+inf"
+	if lower != nil {
+		lo = fmt.Sprintf("%g", *lower)
+	}
+	if upper != nil {
+		hi = fmt.Sprintf("%g", *upper)
+	}
+	return fmt.Sprintf("[%s, %s)", lo, hi)
+}
+
+// countNumericBuckets fills a numeric distribution's bucket counts with one grouped query
+func countNumericBuckets(ctx context.Context, check *Check, connector datasource.Connector, from string, distribution *Distribution) error {
+	bucketExpr := fmt.Sprintf("%d", len(distribution.Buckets)-1)
+	if len(distribution.Buckets) > 1 {
+		cases := make([]string, 0, len(distribution.Buckets)-1)
+		for i, b := range distribution.Buckets[:len(distribution.Buckets)-1] {
+			cases = append(cases, fmt.Sprintf("WHEN %s < %g THEN %d", check.Column, *b.Upper, i))
+		}
+		bucketExpr = fmt.Sprintf("CASE %s ELSE %d END", strings.Join(cases, " "), len(distribution.Buckets)-1)
+	}
+
+	query := fmt.Sprintf(
+		"SELECT bucket, COUNT(*) as frequency FROM (SELECT %s as bucket FROM %s WHERE %s IS NOT NULL) _buckets GROUP BY bucket",
+		bucketExpr, from, check.Column)
+
+	queryResult, err := connector.Query(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to execute distribution query: %w", err)
+	}
+
+	for _, row := range queryResult.Rows {
+		i := toInt64(row["bucket"])
+		if i < 0 || int(i) >= len(distribution.Buckets) {
+			continue
+		}
+		count := toInt64(row["frequency"])
+		distribution.Buckets[i].Count += count
+		distribution.Total += count
+	}
+	return nil
+}
+
+// compareBuckets pairs baseline and current buckets by label
+func compareBuckets(baseline, current *Distribution) []BucketComparison {
+	baselineCounts := make(map[string]int64, len(baseline.Buckets))
+	for _, b := range baseline.Buckets {
+		baselineCounts[b.Label] = b.Count
+	}
+
+	comparisons := make([]BucketComparison, 0, len(current.Buckets))
+	for _, b := range current.Buckets {
+		c := BucketComparison{
+			Label:         b.Label,
+			BaselineCount: baselineCounts[b.Label],
+			CurrentCount:  b.Count,
+		}
+		if baseline.Total > 0 {
+			c.BaselinePercentage = float64(c.BaselineCount) / float64(baseline.Total) * 100
+		}
+		if current.Total > 0 {
+			c.CurrentPercentage = float64(c.CurrentCount) / float64(current.Total) * 100
+		}
+		comparisons = append(comparisons, c)
+	}
+	return comparisons
+}
+
+// populationStabilityIndex returns sum((current - baseline) * ln(current / baseline))
+// over bucket proportions, recording each bucket's contribution
+func populationStabilityIndex(comparisons []BucketComparison) float64 {
+	var psi float64
+	for i := range comparisons {
+		expected := math.Max(comparisons[i].BaselinePercentage/100, psiEpsilon)
+		actual := math.Max(comparisons[i].CurrentPercentage/100, psiEpsilon)
+		term := (actual - expected) * math.Log(actual/expected)
+		comparisons[i].Contribution = term
+		psi += term
+	}
+	return psi
+}
+
+// ksDistance returns the largest gap between the baseline and current
+// cumulative distributions, evaluated at bucket boundaries
+func ksDistance(comparisons []BucketComparison) float64 {
+	var baselineCDF, currentCDF, distance float64
+	for _, c := range comparisons {
+		baselineCDF += c.BaselinePercentage / 100
+		currentCDF += c.CurrentPercentage / 100
+		distance = math.Max(distance, math.Abs(baselineCDF-currentCDF))
+	}
+	return distance
+}
+
+// chiSquareTest tests the current counts against the baseline proportions and
+// returns the statistic and its p-value. Buckets empty in the baseline are
+// given a minimal expected share so new categories still count.
+func chiSquareTest(comparisons []BucketComparison, total int64) (float64, float64) {
+	var statistic float64
+	for i := range comparisons {
+		expected := math.Max(comparisons[i].BaselinePercentage/100, psiEpsilon) * float64(total)
+		diff := float64(comparisons[i].CurrentCount) - expected
+		term := diff * diff / expected
+		comparisons[i].Contribution = term
+		statistic += term
+	}
+	degrees := len(comparisons) - 1
+	if degrees < 1 {
+		return statistic, 1
+	}
+	return statistic, upperIncompleteGamma(float64(degrees)/2, statistic/2)
+}
+
+// upperIncompleteGamma returns the regularized upper incomplete gamma function
+// Q(a, x), the chi-square survival function for a = k/2 and x = statistic/2
+func upperIncompleteGamma(a, x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	lgamma, _ := math.Lgamma(a)
+	if x < a+1 {
+		// Series expansion of P(a, x)
+		sum, term := 1/a, 1/a
+		for n := 1; n < 500; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+		return 1 - sum*math.Exp(-x+a*math.Log(x)-lgamma)
+	}
+
+	// Continued fraction for Q(a, x) (modified Lentz)
+	const tiny = 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < 500; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lgamma) * h
+}
@@ -0,0 +1,221 @@
+package check
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// scriptedConnector answers queries with canned rows keyed by a substring of the query
+type scriptedConnector struct {
+	fakeConnector
+	responses map[string][]map[string]interface{}
+}
+
+func (c *scriptedConnector) Query(ctx context.Context, query string, args ...interface{}) (*datasource.QueryResult, error) {
+	c.queries = append(c.queries, query)
+	for match, rows := range c.responses {
+		if strings.Contains(query, match) {
+			return &datasource.QueryResult{Rows: rows, RowCount: int64(len(rows))}, nil
+		}
+	}
+	return &datasource.QueryResult{}, nil
+}
+
+func bucketRows(counts ...int64) []map[string]interface{} {
+	rows := make([]map[string]interface{}, 0, len(counts))
+	for i, count := range counts {
+		rows = append(rows, map[string]interface{}{"bucket": int64(i), "frequency": count})
+	}
+	return rows
+}
+
+func TestRunDistributionCheck_Numeric(t *testing.T) {
+	connector := &scriptedConnector{
+		fakeConnector: fakeConnector{columns: []datasource.ColumnInfo{{Name: "score", DataType: "double precision"}}},
+		responses: map[string][]map[string]interface{}{
+			"min_value": {{"min_value": 0.0, "max_value": 100.0}},
+			"_buckets":  bucketRows(25, 25, 25, 25),
+		},
+	}
+	check := &Check{
+		Type:       TypeDistribution,
+		Table:      "features",
+		Column:     "score",
+		Parameters: CheckParameters{DistributionBuckets: 4},
+	}
+	m := NewManager(datasource.NewManager())
+
+	result, err := m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusPassed || check.Parameters.Baseline == nil {
+		t.Fatalf("expected first run to capture a baseline, got %s: %s", result.Status, result.Message)
+	}
+	if baseline := check.Parameters.Baseline; baseline.Total != 100 || len(baseline.Buckets) != 4 || baseline.Buckets[0].Lower != nil {
+		t.Errorf("unexpected baseline: %+v", baseline)
+	}
+	if !strings.Contains(connector.queries[len(connector.queries)-1], "WHEN score < 25 THEN 0") {
+		t.Errorf("expected bucket boundaries in query, got %s", connector.queries[len(connector.queries)-1])
+	}
+
+	// Same shape: stable
+	result, err = m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusPassed || result.ActualValue.(float64) != 0 {
+		t.Errorf("expected stable distribution, got %s: %v", result.Status, result.ActualValue)
+	}
+
+	// Mass shifted to the top bucket: drifted
+	connector.responses["_buckets"] = bucketRows(5, 5, 10, 80)
+	result, err = m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusFailed {
+		t.Errorf("expected drift to fail, got %s: %s", result.Status, result.Message)
+	}
+	buckets := result.Details["buckets"].([]BucketComparison)
+	if len(buckets) != 4 || buckets[3].CurrentPercentage != 80 || buckets[3].BaselinePercentage != 25 {
+		t.Errorf("unexpected bucket comparison: %+v", buckets)
+	}
+
+	// KS distance: cumulative gap peaks at 0.55 after the third bucket
+	check.Parameters.DistributionMethod = DistributionKS
+	result, err = m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := result.ActualValue.(float64); math.Abs(d-0.55) > 1e-9 || result.Status != StatusFailed {
+		t.Errorf("expected KS distance 0.55 to fail, got %v (%s)", d, result.Status)
+	}
+}
+
+func TestRunDistributionCheck_CategoricalChiSquare(t *testing.T) {
+	connector := &scriptedConnector{
+		fakeConnector: fakeConnector{columns: []datasource.ColumnInfo{{Name: "country", DataType: "text"}}},
+		responses: map[string][]map[string]interface{}{
+			"GROUP BY country": {
+				{"value": "US", "frequency": int64(500)},
+				{"value": "DE", "frequency": int64(300)},
+				{"value": "FR", "frequency": int64(200)},
+			},
+		},
+	}
+	check := &Check{
+		Type:       TypeDistribution,
+		Table:      "users",
+		Column:     "country",
+		Parameters: CheckParameters{DistributionMethod: DistributionChiSquare},
+	}
+	m := NewManager(datasource.NewManager())
+
+	if _, err := m.executeCheck(context.Background(), check, connector); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !check.Parameters.Baseline.Categorical || len(check.Parameters.Baseline.Buckets) != 3 {
+		t.Fatalf("expected a categorical baseline, got %+v", check.Parameters.Baseline)
+	}
+
+	// A new category appears
+	connector.responses["GROUP BY country"] = []map[string]interface{}{
+		{"value": "US", "frequency": int64(400)},
+		{"value": "DE", "frequency": int64(300)},
+		{"value": "FR", "frequency": int64(200)},
+		{"value": "BR", "frequency": int64(100)},
+	}
+	result, err := m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusFailed {
+		t.Errorf("expected new category to fail, got %s: %s", result.Status, result.Message)
+	}
+	buckets := result.Details["buckets"].([]BucketComparison)
+	if last := buckets[len(buckets)-1]; last.Label != otherCategory || last.CurrentCount != 100 {
+		t.Errorf("expected new values in the other bucket, got %+v", last)
+	}
+
+	check.Parameters.DistributionMethod = DistributionKS
+	if _, err := m.executeCheck(context.Background(), check, connector); err == nil {
+		t.Error("expected KS to be rejected for categorical columns")
+	}
+}
+
+func TestUpperIncompleteGamma(t *testing.T) {
+	// Chi-square critical values at the 5% level
+	testCases := []struct {
+		degrees   float64
+		statistic float64
+	}{
+		{1, 3.841},
+		{4, 9.488},
+		{10, 18.307},
+	}
+
+	for _, tc := range testCases {
+		if p := upperIncompleteGamma(tc.degrees/2, tc.statistic/2); math.Abs(p-0.05) > 0.001 {
+			t.Errorf("df=%v x=%v: expected p-value 0.05, got %v", tc.degrees, tc.statistic, p)
+		}
+	}
+	if p := upperIncompleteGamma(2, 0); p != 1 {
+		t.Errorf("expected p-value 1 at 0, got %v", p)
+	}
+}
+
+func TestManager_ResetDistributionBaseline(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	ctx := context.Background()
+
+	check := &Check{Type: TypeDistribution, Table: "t", Column: "c", Parameters: CheckParameters{Baseline: &Distribution{Total: 1}}}
+	m.CreateCheck(ctx, check)
+	if err := m.ResetDistributionBaseline(ctx, check.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if check.Parameters.Baseline != nil {
+		t.Error("expected baseline to be cleared")
+	}
+
+	other := &Check{Type: TypeRowCount, Table: "t"}
+	m.CreateCheck(ctx, other)
+	if err := m.ResetDistributionBaseline(ctx, other.ID); err == nil {
+		t.Error("expected error for non-distribution check")
+	}
+}
+
+func TestRunDistributionCheck_QualifiedTable(t *testing.T) {
+	connector := &scriptedConnector{
+		fakeConnector: fakeConnector{tables: map[string][]datasource.ColumnInfo{
+			"sales.orders": {{Name: "status", DataType: "text"}},
+		}},
+		responses: map[string][]map[string]interface{}{
+			"GROUP BY status": {
+				{"value": "open", "frequency": int64(60)},
+				{"value": "closed", "frequency": int64(40)},
+			},
+		},
+	}
+	check := &Check{
+		Type:       TypeDistribution,
+		Table:      "sales.orders",
+		Column:     "status",
+		Parameters: CheckParameters{DistributionMethod: DistributionChiSquare},
+	}
+	m := NewManager(datasource.NewManager())
+
+	if _, err := m.executeCheck(context.Background(), check, connector); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !check.Parameters.Baseline.Categorical {
+		t.Errorf("expected the column type to be read from the qualified table, got %+v", check.Parameters.Baseline)
+	}
+	if query := connector.queries[len(connector.queries)-1]; !strings.Contains(query, "FROM sales.orders") {
+		t.Errorf("expected the qualified table in the query, got %s", query)
+	}
+}
@@ -0,0 +1,154 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// CompiledCheck is the SQL a check definition sends to its datasources
+type CompiledCheck struct {
+	Type           Type            `json:"type"`
+	DatasourceType datasource.Type `json:"datasource_type"`
+	Queries        []CompiledQuery `json:"queries"`
+}
+
+// CompiledQuery is a statement a check sends to a datasource
+type CompiledQuery struct {
+	DatasourceID string `json:"datasource_id"`
+	SQL          string `json:"sql"`
+}
+
+// queryRecorder collects the statements sent through recording connectors
+type queryRecorder struct {
+	mu      sync.Mutex
+	queries []CompiledQuery
+}
+
+type recorderKey struct{}
+
+// withRecorder returns ctx with the recorder that connectors of the run's
+// other datasources are wrapped with
+func withRecorder(ctx context.Context, recorder *queryRecorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, recorder)
+}
+
+// recorderFrom returns the query recorder of a compile run, or nil
+func recorderFrom(ctx context.Context) *queryRecorder {
+	recorder, _ := ctx.Value(recorderKey{}).(*queryRecorder)
+	return recorder
+}
+
+func (r *queryRecorder) record(datasourceID, query string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = append(r.queries, CompiledQuery{DatasourceID: datasourceID, SQL: query})
+}
+
+// wrap returns a connector recording the statements sent to a datasource
+// instead of executing them
+func (r *queryRecorder) wrap(datasourceID string, connector datasource.Connector) datasource.Connector {
+	return &recordingConnector{Connector: connector, datasourceID: datasourceID, recorder: r}
+}
+
+// recordingConnector records queries and answers each with a single row
+// without columns, so that executors carry on to their next statement.
+// Metadata such as table columns is still read from the datasource.
+type recordingConnector struct {
+	datasource.Connector
+	datasourceID string
+	recorder     *queryRecorder
+}
+
+// Query records a query without executing it
+func (c *recordingConnector) Query(ctx context.Context, query string, args ...interface{}) (*datasource.QueryResult, error) {
+	c.recorder.record(c.datasourceID, query)
+	return &datasource.QueryResult{Rows: []map[string]interface{}{{}}, RowCount: 1}, nil
+}
+
+// GetRowCount records a table's count query, as most connectors send it,
+// without executing it
+func (c *recordingConnector) GetRowCount(ctx context.Context, table string) (int64, error) {
+	c.recorder.record(c.datasourceID, fmt.Sprintf("SELECT COUNT(*) as count FROM %s", table))
+	return 0, nil
+}
+
+// CompileCheck returns the SQL an unsaved check definition sends to its
+// datasources, without executing it. The executor runs against placeholder
+// results, so statements that depend on the data, such as the segments a
+// table diff bisects into, are not listed; an executor error after the first
+// statement comes from the placeholders and is ignored. Invalid definitions
+// return a *ValidationError.
+func (m *Manager) CompileCheck(ctx context.Context, check *Check) (*CompiledCheck, error) {
+	connector, err := m.validateDefinition(ctx, check)
+	if err != nil {
+		return nil, err
+	}
+	return m.compileCheck(ctx, check, connector)
+}
+
+// compileCheck records the statements a validated check definition sends
+// through connector
+func (m *Manager) compileCheck(ctx context.Context, check *Check, connector datasource.Connector) (*CompiledCheck, error) {
+	run := *check
+	run.ID = ""
+	recorder := &queryRecorder{}
+	ctx = withRecorder(ctx, recorder)
+	_, err := m.executeCheck(ctx, &run, recorder.wrap(check.DatasourceID, connector))
+	if err != nil && len(recorder.queries) == 0 {
+		return nil, fmt.Errorf("failed to compile check: %w", err)
+	}
+
+	return &CompiledCheck{
+		Type:           check.Type,
+		DatasourceType: connector.Type(),
+		Queries:        append([]CompiledQuery{}, recorder.queries...),
+	}, nil
+}
+
+// DryRunCheck executes an unsaved check definition and returns its result
+// without storing it. The run takes a slot and applies timeouts, budgets and
+// retries like a stored check's, but has no history: anomaly thresholds and
+// volume checks are warming up, incremental checks evaluate the whole table
+// and distribution checks capture a baseline that is not kept. Invalid
+// definitions return a *ValidationError.
+func (m *Manager) DryRunCheck(ctx context.Context, check *Check) (*CheckResult, error) {
+	connector, err := m.validateDefinition(ctx, check)
+	if err != nil {
+		return nil, err
+	}
+	return m.dryRunCheck(ctx, check, connector)
+}
+
+// dryRunCheck runs a validated check definition through connector
+func (m *Manager) dryRunCheck(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
+	run := *check
+	run.ID = ""
+	check = &run
+
+	ctx, done, err := m.startRun(ctx, check)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	ctx = datasource.WithQueryOrigin(ctx, datasource.QueryOrigin{Type: datasource.OriginCheck})
+
+	costGuard := m.withCostEstimation(ctx, check, connector)
+	if costGuard != nil {
+		connector = costGuard
+	}
+
+	startTime := time.Now()
+
+	var result *CheckResult
+	attempts, err := m.withRetries(ctx, func() error {
+		var runErr error
+		result, runErr = m.executeCheck(ctx, check, connector)
+		return runErr
+	})
+	return m.finishRun(ctx, check, connector, costGuard, result, attempts, timeoutError(ctx, err), startTime), nil
+}
@@ -0,0 +1,87 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+func TestCompileCheck(t *testing.T) {
+	connector := &fakeConnector{dsType: datasource.TypePostgres, columns: orderColumns}
+	m := NewManager(datasource.NewManager())
+
+	compiled, err := m.compileCheck(context.Background(), &Check{
+		Type:         TypeNullCheck,
+		DatasourceID: "warehouse",
+		Table:        "orders",
+		Column:       "price",
+		Where:        "tier = 'vip'",
+	}, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(connector.queries) != 0 {
+		t.Errorf("expected no query to be executed, got %v", connector.queries)
+	}
+	if compiled.DatasourceType != datasource.TypePostgres || len(compiled.Queries) != 1 || compiled.Queries[0].DatasourceID != "warehouse" {
+		t.Fatalf("expected one query on warehouse, got %+v", compiled)
+	}
+	for _, want := range []string{"SUM(CASE WHEN price IS NULL THEN 1 ELSE 0 END) as null_count", "WHERE tier = 'vip'"} {
+		if !strings.Contains(compiled.Queries[0].SQL, want) {
+			t.Errorf("expected %q in %s", want, compiled.Queries[0].SQL)
+		}
+	}
+
+	// The executor fails on the placeholder result after its query
+	compiled, err = m.compileCheck(context.Background(), &Check{
+		Type:       TypeFreshness,
+		Table:      "orders",
+		Parameters: CheckParameters{TimestampColumn: "created_at", MaxAgeHours: 24},
+	}, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(compiled.Queries) != 1 || compiled.Queries[0].SQL != "SELECT MAX(created_at) as latest_timestamp FROM orders" {
+		t.Errorf("unexpected queries: %+v", compiled.Queries)
+	}
+
+	compiled, err = m.compileCheck(context.Background(), &Check{Type: TypeRowCount, Table: "orders"}, connector)
+	if err != nil || len(compiled.Queries) != 1 || !strings.HasPrefix(compiled.Queries[0].SQL, "SELECT COUNT(*)") {
+		t.Errorf("expected the row count query, got %+v, %v", compiled, err)
+	}
+}
+
+func TestDryRunCheck(t *testing.T) {
+	connector := &fakeConnector{
+		dsType:  datasource.TypePostgres,
+		columns: orderColumns,
+		rows:    []map[string]interface{}{{"total_count": int64(100), "null_count": int64(2)}},
+	}
+	m := NewManager(datasource.NewManager())
+
+	result, err := m.dryRunCheck(context.Background(), &Check{
+		ID:        "draft",
+		Type:      TypeNullCheck,
+		Table:     "orders",
+		Column:    "price",
+		Threshold: Threshold{Type: ThresholdPercentage, Operator: OperatorLte, Value: 1},
+	}, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusFailed || result.ActualValue != 2.0 || result.CheckID != "" || result.ID == "" {
+		t.Errorf("expected an unsaved failed result at 2%%, got %+v", result)
+	}
+	if len(m.results) != 0 || len(m.checks) != 0 {
+		t.Errorf("expected nothing to be stored, got %d checks and %d result histories", len(m.checks), len(m.results))
+	}
+
+	_, err = m.DryRunCheck(context.Background(), &Check{Type: TypeRegex, Table: "orders", Column: "tier", Parameters: CheckParameters{Pattern: "("}})
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) || len(invalid.Errors) != 2 {
+		t.Errorf("expected datasource_id and parameters.pattern errors, got %v", err)
+	}
+}
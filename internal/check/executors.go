@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/vinod901/opendq-go/internal/datasource"
@@ -11,11 +13,30 @@ import (
 
 // runRowCountCheck executes a row count check
 func (m *Manager) runRowCountCheck(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
-	count, err := connector.GetRowCount(ctx, check.Table)
+	if len(check.SegmentBy) > 0 {
+		from, err := tableRef(ctx, check, connector, "")
+		if err != nil {
+			return nil, err
+		}
+		return m.runAggregate(ctx, check, connector, aggregateSpec{
+			description: "row count",
+			aggregates:  []aggregate{{"COUNT(*)", "row_count"}},
+			from:        from,
+			evaluate: func(row map[string]interface{}) (*CheckResult, error) {
+				return evaluateRowCount(check, toInt64(row["row_count"]))
+			},
+		})
+	}
+
+	count, err := rowCount(ctx, check, connector)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get row count: %w", err)
 	}
+	return evaluateRowCount(check, count)
+}
 
+// evaluateRowCount judges a row count against the check's bounds
+func evaluateRowCount(check *Check, count int64) (*CheckResult, error) {
 	result := &CheckResult{
 		ActualValue: count,
 		Details:     make(map[string]interface{}),
@@ -23,17 +44,15 @@ func (m *Manager) runRowCountCheck(ctx context.Context, check *Check, connector
 
 	// Evaluate against thresholds
 	params := check.Parameters
-	if params.MinRows > 0 && count < params.MinRows {
-		result.Status = StatusFailed
-		result.ExpectedValue = params.MinRows
-		result.Message = fmt.Sprintf("row count %d is below minimum %d", count, params.MinRows)
-	} else if params.MaxRows > 0 && count > params.MaxRows {
-		result.Status = StatusFailed
-		result.ExpectedValue = params.MaxRows
-		result.Message = fmt.Sprintf("row count %d exceeds maximum %d", count, params.MaxRows)
-	} else {
-		result.Status = StatusPassed
-		result.Message = fmt.Sprintf("row count %d is within acceptable range", count)
+	var defaults []Threshold
+	if params.MinRows > 0 {
+		defaults = append(defaults, Threshold{Type: ThresholdAbsolute, Operator: OperatorGte, Value: float64(params.MinRows)})
+	}
+	if params.MaxRows > 0 {
+		defaults = append(defaults, Threshold{Type: ThresholdAbsolute, Operator: OperatorLte, Value: float64(params.MaxRows)})
+	}
+	if err := evaluateThreshold(result, check.Threshold, metric{name: "row count", value: float64(count)}, defaults...); err != nil {
+		return nil, err
 	}
 
 	result.Details["row_count"] = count
@@ -45,54 +64,58 @@ func (m *Manager) runRowCountCheck(ctx context.Context, check *Check, connector
 
 // runNullCheck executes a null value check
 func (m *Manager) runNullCheck(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
-	query := fmt.Sprintf(`
-		SELECT 
-			COUNT(*) as total_count,
-			SUM(CASE WHEN %s IS NULL THEN 1 ELSE 0 END) as null_count
-		FROM %s`, check.Column, check.Table)
+	return m.runSpec(ctx, check, connector, nullSpec)
+}
 
-	queryResult, err := connector.Query(ctx, query)
+// nullSpec builds the aggregate of a null value check
+func nullSpec(ctx context.Context, check *Check, connector datasource.Connector) (aggregateSpec, error) {
+	from, err := tableRef(ctx, check, connector, "")
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute null check query: %w", err)
+		return aggregateSpec{}, err
 	}
 
-	if len(queryResult.Rows) == 0 {
-		return nil, fmt.Errorf("null check query returned no results")
-	}
+	return aggregateSpec{
+		description: "null check",
+		aggregates: []aggregate{
+			{"COUNT(*)", "total_count"},
+			{fmt.Sprintf("SUM(CASE WHEN %s IS NULL THEN 1 ELSE 0 END)", check.Column), "null_count"},
+		},
+		from: from,
+		evaluate: func(row map[string]interface{}) (*CheckResult, error) {
+			totalCount := toInt64(row["total_count"])
+			nullCount := toInt64(row["null_count"])
+
+			var nullPercentage float64
+			if totalCount > 0 {
+				nullPercentage = float64(nullCount) / float64(totalCount) * 100
+			}
 
-	row := queryResult.Rows[0]
-	totalCount := toInt64(row["total_count"])
-	nullCount := toInt64(row["null_count"])
+			result := &CheckResult{
+				ActualValue: nullPercentage,
+				Details: map[string]interface{}{
+					"total_count":     totalCount,
+					"null_count":      nullCount,
+					"null_percentage": nullPercentage,
+				},
+			}
+			recordSample(result, check, totalCount)
+			recordProportionBounds(result, check, nullCount, totalCount)
 
-	var nullPercentage float64
-	if totalCount > 0 {
-		nullPercentage = float64(nullCount) / float64(totalCount) * 100
-	}
+			params := check.Parameters
+			var defaults []Threshold
+			if params.MaxNullPercentage > 0 {
+				defaults = append(defaults, Threshold{Type: ThresholdPercentage, Operator: OperatorLte, Value: params.MaxNullPercentage})
+			}
+			if params.MaxNullCount > 0 {
+				defaults = append(defaults, Threshold{Type: ThresholdAbsolute, Operator: OperatorLte, Value: float64(params.MaxNullCount)})
+			}
+			if err := evaluateThreshold(result, check.Threshold, rowMetric("null count", nullCount, totalCount, "null percentage"), defaults...); err != nil {
+				return nil, err
+			}
 
-	result := &CheckResult{
-		ActualValue: nullPercentage,
-		Details: map[string]interface{}{
-			"total_count":     totalCount,
-			"null_count":      nullCount,
-			"null_percentage": nullPercentage,
+			return result, nil
 		},
-	}
-
-	params := check.Parameters
-	if params.MaxNullPercentage > 0 && nullPercentage > params.MaxNullPercentage {
-		result.Status = StatusFailed
-		result.ExpectedValue = params.MaxNullPercentage
-		result.Message = fmt.Sprintf("null percentage %.2f%% exceeds maximum %.2f%%", nullPercentage, params.MaxNullPercentage)
-	} else if params.MaxNullCount > 0 && nullCount > params.MaxNullCount {
-		result.Status = StatusFailed
-		result.ExpectedValue = params.MaxNullCount
-		result.Message = fmt.Sprintf("null count %d exceeds maximum %d", nullCount, params.MaxNullCount)
-	} else {
-		result.Status = StatusPassed
-		result.Message = fmt.Sprintf("null percentage %.2f%% is acceptable", nullPercentage)
-	}
-
-	return result, nil
+	}, nil
 }
 
 // runUniquenessCheck executes a uniqueness check
@@ -108,59 +131,56 @@ func (m *Manager) runUniquenessCheck(ctx context.Context, check *Check, connecto
 		}
 	}
 
-	query := fmt.Sprintf(`
-		SELECT 
-			COUNT(*) as total_count,
-			COUNT(DISTINCT %s) as unique_count
-		FROM %s`, columns, check.Table)
-
-	queryResult, err := connector.Query(ctx, query)
+	from, err := tableRef(ctx, check, connector, "")
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute uniqueness check query: %w", err)
+		return nil, err
 	}
 
-	if len(queryResult.Rows) == 0 {
-		return nil, fmt.Errorf("uniqueness check query returned no results")
-	}
-
-	row := queryResult.Rows[0]
-	totalCount := toInt64(row["total_count"])
-	uniqueCount := toInt64(row["unique_count"])
-	duplicateCount := totalCount - uniqueCount
-
-	var uniquenessPercentage float64
-	if totalCount > 0 {
-		uniquenessPercentage = float64(uniqueCount) / float64(totalCount) * 100
-	}
-
-	result := &CheckResult{
-		ActualValue: uniquenessPercentage,
-		Details: map[string]interface{}{
-			"total_count":          totalCount,
-			"unique_count":         uniqueCount,
-			"duplicate_count":      duplicateCount,
-			"uniqueness_percentage": uniquenessPercentage,
-			"columns":              columns,
+	return m.runAggregate(ctx, check, connector, aggregateSpec{
+		description: "uniqueness check",
+		aggregates: []aggregate{
+			{"COUNT(*)", "total_count"},
+			{fmt.Sprintf("COUNT(DISTINCT %s)", columns), "unique_count"},
 		},
-	}
+		from: from,
+		evaluate: func(row map[string]interface{}) (*CheckResult, error) {
+			totalCount := toInt64(row["total_count"])
+			uniqueCount := toInt64(row["unique_count"])
+			duplicateCount := totalCount - uniqueCount
+
+			var uniquenessPercentage float64
+			if totalCount > 0 {
+				uniquenessPercentage = float64(uniqueCount) / float64(totalCount) * 100
+			}
 
-	// By default, expect 100% uniqueness
-	expectedUniqueness := 100.0
-	if check.Threshold.Value > 0 {
-		expectedUniqueness = check.Threshold.Value
-	}
+			result := &CheckResult{
+				ActualValue: uniquenessPercentage,
+				Details: map[string]interface{}{
+					"total_count":          totalCount,
+					"unique_count":         uniqueCount,
+					"duplicate_count":      duplicateCount,
+					"uniqueness_percentage": uniquenessPercentage,
+					"columns":              columns,
+				},
+			}
 
-	if uniquenessPercentage < expectedUniqueness {
-		result.Status = StatusFailed
-		result.ExpectedValue = expectedUniqueness
-		result.Message = fmt.Sprintf("uniqueness %.2f%% is below expected %.2f%% (%d duplicates)", 
-			uniquenessPercentage, expectedUniqueness, duplicateCount)
-	} else {
-		result.Status = StatusPassed
-		result.Message = fmt.Sprintf("uniqueness %.2f%% meets expectation", uniquenessPercentage)
-	}
+			// By default, expect 100% uniqueness
+			expectedUniqueness := 100.0
+			if check.Threshold.Value > 0 {
+				expectedUniqueness = check.Threshold.Value
+			}
 
-	return result, nil
+			uniqueness := rowMetric("unique count", uniqueCount, totalCount, "uniqueness")
+			if err := evaluateThreshold(result, check.Threshold, uniqueness, Threshold{Type: ThresholdPercentage, Operator: OperatorGte, Value: expectedUniqueness}); err != nil {
+				return nil, err
+			}
+			if result.Status != StatusPassed {
+				result.Message += fmt.Sprintf(" (%d duplicates)", duplicateCount)
+			}
+
+			return result, nil
+		},
+	})
 }
 
 // runFreshnessCheck executes a data freshness check
@@ -170,7 +190,11 @@ func (m *Manager) runFreshnessCheck(ctx context.Context, check *Check, connector
 		return nil, fmt.Errorf("timestamp column not specified for freshness check")
 	}
 
-	query := fmt.Sprintf("SELECT MAX(%s) as latest_timestamp FROM %s", timestampCol, check.Table)
+	from, err := tableRef(ctx, check, connector, "")
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("SELECT MAX(%s) as latest_timestamp FROM %s", timestampCol, from)
 
 	queryResult, err := connector.Query(ctx, query)
 	if err != nil {
@@ -200,13 +224,12 @@ func (m *Manager) runFreshnessCheck(ctx context.Context, check *Check, connector
 		},
 	}
 
-	if maxAgeHours > 0 && ageHours > maxAgeHours {
-		result.Status = StatusFailed
-		result.ExpectedValue = maxAgeHours
-		result.Message = fmt.Sprintf("data age %.2f hours exceeds maximum %.2f hours", ageHours, maxAgeHours)
-	} else {
-		result.Status = StatusPassed
-		result.Message = fmt.Sprintf("data age %.2f hours is acceptable", ageHours)
+	var defaults []Threshold
+	if maxAgeHours > 0 {
+		defaults = append(defaults, Threshold{Type: ThresholdAbsolute, Operator: OperatorLte, Value: maxAgeHours})
+	}
+	if err := evaluateThreshold(result, check.Threshold, metric{name: "data age hours", value: ageHours}, defaults...); err != nil {
+		return nil, err
 	}
 
 	return result, nil
@@ -233,8 +256,25 @@ func (m *Manager) runCustomSQLCheck(ctx context.Context, check *Check, connector
 		},
 	}
 
-	// Evaluate result based on expected value or row count
-	if check.Parameters.ExpectedValue != "" {
+	// Evaluate result based on a threshold on the first value, the expected
+	// value or the row count
+	if check.Threshold.explicit() {
+		if len(queryResult.Rows) == 0 || len(queryResult.Columns) == 0 {
+			return nil, fmt.Errorf("custom SQL returned no value to compare with the threshold")
+		}
+		raw := queryResult.Rows[0][queryResult.Columns[0]]
+		value, ok := metricValue(raw)
+		if !ok {
+			if value, ok = parseMetric(raw); !ok {
+				return nil, fmt.Errorf("custom SQL value %v is not numeric", raw)
+			}
+		}
+		result.ActualValue = value
+		result.Details["value"] = value
+		if err := evaluateThreshold(result, check.Threshold, metric{name: "custom SQL value", value: value}); err != nil {
+			return nil, err
+		}
+	} else if check.Parameters.ExpectedValue != "" {
 		// Compare first row's first column to expected value
 		if len(queryResult.Rows) > 0 && len(queryResult.Columns) > 0 {
 			actualValue := fmt.Sprintf("%v", queryResult.Rows[0][queryResult.Columns[0]])
@@ -249,12 +289,9 @@ func (m *Manager) runCustomSQLCheck(ctx context.Context, check *Check, connector
 		}
 	} else {
 		// Check based on whether query returns results
-		if queryResult.RowCount > 0 {
-			result.Status = StatusPassed
-			result.Message = fmt.Sprintf("custom SQL returned %d rows", queryResult.RowCount)
-		} else {
-			result.Status = StatusFailed
-			result.Message = "custom SQL returned no results"
+		rows := metric{name: "custom SQL row count", value: float64(queryResult.RowCount)}
+		if err := evaluateThreshold(result, check.Threshold, rows, Threshold{Type: ThresholdAbsolute, Operator: OperatorGt, Value: 0}); err != nil {
+			return nil, err
 		}
 	}
 
@@ -263,6 +300,11 @@ func (m *Manager) runCustomSQLCheck(ctx context.Context, check *Check, connector
 
 // runValueCheck executes value-based checks (min, max, mean, sum)
 func (m *Manager) runValueCheck(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
+	return m.runSpec(ctx, check, connector, valueSpec)
+}
+
+// valueSpec builds the aggregate of a value check (min, max, mean, sum)
+func valueSpec(ctx context.Context, check *Check, connector datasource.Connector) (aggregateSpec, error) {
 	var aggFunc string
 	switch check.Type {
 	case TypeMinValue:
@@ -274,281 +316,334 @@ func (m *Manager) runValueCheck(ctx context.Context, check *Check, connector dat
 	case TypeSumValue:
 		aggFunc = "SUM"
 	default:
-		return nil, fmt.Errorf("unsupported value check type: %s", check.Type)
+		return aggregateSpec{}, fmt.Errorf("unsupported value check type: %s", check.Type)
 	}
 
-	query := fmt.Sprintf("SELECT %s(%s) as value FROM %s", aggFunc, check.Column, check.Table)
-
-	queryResult, err := connector.Query(ctx, query)
+	from, err := tableRef(ctx, check, connector, "")
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute value check query: %w", err)
-	}
-
-	if len(queryResult.Rows) == 0 {
-		return nil, fmt.Errorf("value check query returned no results")
-	}
-
-	actualValue := toFloat64(queryResult.Rows[0]["value"])
-
-	result := &CheckResult{
-		ActualValue: actualValue,
-		Details: map[string]interface{}{
-			"function": aggFunc,
-			"column":   check.Column,
-			"table":    check.Table,
-		},
-	}
-
-	// Evaluate against expected range
-	params := check.Parameters
-	tolerance := params.Tolerance
-	if tolerance == 0 {
-		tolerance = check.Threshold.Value
-	}
+		return aggregateSpec{}, err
+	}
+
+	return aggregateSpec{
+		description: "value check",
+		aggregates:  []aggregate{{fmt.Sprintf("%s(%s)", aggFunc, check.Column), "value"}},
+		from:        from,
+		evaluate: func(row map[string]interface{}) (*CheckResult, error) {
+			actualValue := toFloat64(row["value"])
+
+			result := &CheckResult{
+				ActualValue: actualValue,
+				Details: map[string]interface{}{
+					"function": aggFunc,
+					"column":   check.Column,
+					"table":    check.Table,
+				},
+			}
+			recordSample(result, check, 0)
 
-	switch check.Type {
-	case TypeMinValue:
-		if params.ExpectedMin > 0 {
-			diff := actualValue - params.ExpectedMin
-			if diff < -tolerance || diff > tolerance {
-				result.Status = StatusFailed
-				result.ExpectedValue = params.ExpectedMin
-				result.Message = fmt.Sprintf("min value %v differs from expected %v", actualValue, params.ExpectedMin)
-			} else {
-				result.Status = StatusPassed
-				result.Message = fmt.Sprintf("min value %v is within tolerance", actualValue)
+			// Evaluate against expected range
+			params := check.Parameters
+			tolerance := params.Tolerance
+			if tolerance == 0 {
+				tolerance = check.Threshold.Value
 			}
-		} else {
-			result.Status = StatusPassed
-			result.Message = fmt.Sprintf("min value is %v", actualValue)
-		}
-	case TypeMaxValue:
-		if params.ExpectedMax > 0 {
-			diff := actualValue - params.ExpectedMax
-			if diff < -tolerance || diff > tolerance {
-				result.Status = StatusFailed
-				result.ExpectedValue = params.ExpectedMax
-				result.Message = fmt.Sprintf("max value %v differs from expected %v", actualValue, params.ExpectedMax)
-			} else {
-				result.Status = StatusPassed
-				result.Message = fmt.Sprintf("max value %v is within tolerance", actualValue)
+
+			var expected float64
+			switch check.Type {
+			case TypeMinValue:
+				expected = params.ExpectedMin
+			case TypeMaxValue:
+				expected = params.ExpectedMax
+			case TypeMeanValue:
+				expected = params.ExpectedMean
 			}
-		} else {
-			result.Status = StatusPassed
-			result.Message = fmt.Sprintf("max value is %v", actualValue)
-		}
-	case TypeMeanValue:
-		if params.ExpectedMean > 0 {
-			diff := actualValue - params.ExpectedMean
-			if diff < -tolerance || diff > tolerance {
-				result.Status = StatusFailed
-				result.ExpectedValue = params.ExpectedMean
-				result.Message = fmt.Sprintf("mean value %v differs from expected %v", actualValue, params.ExpectedMean)
-			} else {
-				result.Status = StatusPassed
-				result.Message = fmt.Sprintf("mean value %v is within tolerance", actualValue)
+
+			if err := evaluateToleranceThreshold(result, check, strings.TrimSuffix(string(check.Type), "_value")+" value", actualValue, expected, tolerance); err != nil {
+				return nil, err
 			}
-		} else {
-			result.Status = StatusPassed
-			result.Message = fmt.Sprintf("mean value is %v", actualValue)
-		}
-	}
 
-	return result, nil
+			return result, nil
+		},
+	}, nil
+}
+
+// evaluateToleranceThreshold evaluates an aggregate that is expected to be
+// within tolerance of a configured value. Percentage thresholds compare the
+// aggregate as a percentage of the expected value.
+func evaluateToleranceThreshold(result *CheckResult, check *Check, name string, actual, expected, tolerance float64) error {
+	m := metric{name: name, value: actual}
+	var defaults []Threshold
+	if expected > 0 {
+		m.reference, m.percentName = expected, name+" percentage of expected"
+		result.ExpectedValue = expected
+		defaults = append(defaults, Threshold{Type: ThresholdAbsolute, Operator: OperatorBetween, MinValue: expected - tolerance, MaxValue: expected + tolerance})
+	}
+	return evaluateThreshold(result, check.Threshold, m, defaults...)
 }
 
 // runRegexCheck executes a regex pattern check
 func (m *Manager) runRegexCheck(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
+	return m.runSpec(ctx, check, connector, regexSpec)
+}
+
+// regexSpec builds the aggregate of a regex pattern check
+func regexSpec(ctx context.Context, check *Check, connector datasource.Connector) (aggregateSpec, error) {
 	pattern := check.Parameters.Pattern
 	if pattern == "" {
-		return nil, fmt.Errorf("regex pattern not specified")
+		return aggregateSpec{}, fmt.Errorf("regex pattern not specified")
 	}
 
 	_, err := regexp.Compile(pattern)
 	if err != nil {
-		return nil, fmt.Errorf("invalid regex pattern: %w", err)
+		return aggregateSpec{}, fmt.Errorf("invalid regex pattern: %w", err)
 	}
 
-	// Count rows that don't match the pattern (depends on database regex support)
-	// This is a simplified version - actual implementation depends on database
-	query := fmt.Sprintf(`
-		SELECT 
-			COUNT(*) as total_count,
-			SUM(CASE WHEN %s ~ '%s' THEN 1 ELSE 0 END) as match_count
-		FROM %s`, check.Column, pattern, check.Table)
+	return patternSpec(ctx, check, connector, pattern, map[string]interface{}{"pattern": pattern})
+}
 
-	queryResult, err := connector.Query(ctx, query)
+// runFormatCheck executes a check that values match a named format
+func (m *Manager) runFormatCheck(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
+	return m.runSpec(ctx, check, connector, formatSpec)
+}
+
+// formatSpec builds the aggregate of a named format check
+func formatSpec(ctx context.Context, check *Check, connector datasource.Connector) (aggregateSpec, error) {
+	name := check.Parameters.Format
+	if name == "" {
+		return aggregateSpec{}, fmt.Errorf("format not specified")
+	}
+	pattern, ok := Formats[name]
+	if !ok {
+		return aggregateSpec{}, fmt.Errorf("unknown format: %s", name)
+	}
+
+	return patternSpec(ctx, check, connector, pattern, map[string]interface{}{"format": name, "pattern": pattern})
+}
+
+// patternSpec builds the aggregate measuring the percentage of rows matching a
+// regular expression
+func patternSpec(ctx context.Context, check *Check, connector datasource.Connector, pattern string, details map[string]interface{}) (aggregateSpec, error) {
+	match, err := datasource.DialectFor(connector.Type()).RegexMatch(check.Column, pattern)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute regex check query: %w", err)
+		return aggregateSpec{}, err
 	}
 
-	if len(queryResult.Rows) == 0 {
-		return nil, fmt.Errorf("regex check query returned no results")
+	from, err := tableRef(ctx, check, connector, "")
+	if err != nil {
+		return aggregateSpec{}, err
 	}
 
-	row := queryResult.Rows[0]
-	totalCount := toInt64(row["total_count"])
-	matchCount := toInt64(row["match_count"])
-	nonMatchCount := totalCount - matchCount
+	return aggregateSpec{
+		description: string(check.Type) + " check",
+		aggregates: []aggregate{
+			{"COUNT(*)", "total_count"},
+			{fmt.Sprintf("SUM(CASE WHEN %s THEN 1 ELSE 0 END)", match), "match_count"},
+		},
+		from: from,
+		evaluate: func(row map[string]interface{}) (*CheckResult, error) {
+			totalCount := toInt64(row["total_count"])
+			matchCount := toInt64(row["match_count"])
+			nonMatchCount := totalCount - matchCount
+
+			var matchPercentage float64
+			if totalCount > 0 {
+				matchPercentage = float64(matchCount) / float64(totalCount) * 100
+			}
 
-	var matchPercentage float64
-	if totalCount > 0 {
-		matchPercentage = float64(matchCount) / float64(totalCount) * 100
-	}
+			result := &CheckResult{
+				ActualValue: matchPercentage,
+				Details:     make(map[string]interface{}, len(details)+4),
+			}
+			for k, v := range details {
+				result.Details[k] = v
+			}
+			result.Details["total_count"] = totalCount
+			result.Details["match_count"] = matchCount
+			result.Details["non_match_count"] = nonMatchCount
+			result.Details["match_percentage"] = matchPercentage
+			recordSample(result, check, totalCount)
+			recordProportionBounds(result, check, matchCount, totalCount)
+
+			expectedMatch := 100.0
+			if check.Threshold.Value > 0 {
+				expectedMatch = check.Threshold.Value
+			}
 
-	result := &CheckResult{
-		ActualValue: matchPercentage,
-		Details: map[string]interface{}{
-			"total_count":      totalCount,
-			"match_count":      matchCount,
-			"non_match_count":  nonMatchCount,
-			"match_percentage": matchPercentage,
-			"pattern":          pattern,
+			matches := rowMetric("match count", matchCount, totalCount, "pattern match")
+			if err := evaluateThreshold(result, check.Threshold, matches, Threshold{Type: ThresholdPercentage, Operator: OperatorGte, Value: expectedMatch}); err != nil {
+				return nil, err
+			}
+
+			return result, nil
 		},
-	}
+	}, nil
+}
 
-	expectedMatch := 100.0
-	if check.Threshold.Value > 0 {
-		expectedMatch = check.Threshold.Value
-	}
+// runStdDevCheck compares a column's sample standard deviation to an expected value
+func (m *Manager) runStdDevCheck(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
+	from, err := tableRef(ctx, check, connector, "")
+	if err != nil {
+		return nil, err
+	}
+
+	stddev := datasource.DialectFor(connector.Type()).StdDev(check.Column)
+	return m.runAggregate(ctx, check, connector, aggregateSpec{
+		description: "std dev check",
+		aggregates:  []aggregate{{stddev, "value"}},
+		from:        from,
+		evaluate: func(row map[string]interface{}) (*CheckResult, error) {
+			actualValue := toFloat64(row["value"])
+
+			result := &CheckResult{
+				ActualValue: actualValue,
+				Details: map[string]interface{}{
+					"column": check.Column,
+					"table":  check.Table,
+				},
+			}
+			recordSample(result, check, 0)
 
-	if matchPercentage < expectedMatch {
-		result.Status = StatusFailed
-		result.ExpectedValue = expectedMatch
-		result.Message = fmt.Sprintf("pattern match %.2f%% is below expected %.2f%%", matchPercentage, expectedMatch)
-	} else {
-		result.Status = StatusPassed
-		result.Message = fmt.Sprintf("pattern match %.2f%% meets expectation", matchPercentage)
-	}
+			params := check.Parameters
+			tolerance := params.Tolerance
+			if tolerance == 0 {
+				tolerance = check.Threshold.Value
+			}
 
-	return result, nil
+			if params.ExpectedStdDev > 0 {
+				result.Details["expected_std_dev"] = params.ExpectedStdDev
+				result.Details["tolerance"] = tolerance
+			}
+			if err := evaluateToleranceThreshold(result, check, "std dev", actualValue, params.ExpectedStdDev, tolerance); err != nil {
+				return nil, err
+			}
+
+			return result, nil
+		},
+	})
 }
 
 // runRangeCheck executes a value range check
 func (m *Manager) runRangeCheck(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
+	return m.runSpec(ctx, check, connector, rangeSpec)
+}
+
+// rangeSpec builds the aggregate of a value range check
+func rangeSpec(ctx context.Context, check *Check, connector datasource.Connector) (aggregateSpec, error) {
 	params := check.Parameters
-	
-	query := fmt.Sprintf(`
-		SELECT 
-			COUNT(*) as total_count,
-			SUM(CASE WHEN %s >= %f AND %s <= %f THEN 1 ELSE 0 END) as in_range_count
-		FROM %s`, check.Column, params.ExpectedMin, check.Column, params.ExpectedMax, check.Table)
 
-	queryResult, err := connector.Query(ctx, query)
+	from, err := tableRef(ctx, check, connector, "")
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute range check query: %w", err)
+		return aggregateSpec{}, err
 	}
+	
+	return aggregateSpec{
+		description: "range check",
+		aggregates: []aggregate{
+			{"COUNT(*)", "total_count"},
+			{fmt.Sprintf("SUM(CASE WHEN %s >= %f AND %s <= %f THEN 1 ELSE 0 END)", check.Column, params.ExpectedMin, check.Column, params.ExpectedMax), "in_range_count"},
+		},
+		from: from,
+		evaluate: func(row map[string]interface{}) (*CheckResult, error) {
+			totalCount := toInt64(row["total_count"])
+			inRangeCount := toInt64(row["in_range_count"])
+			outOfRangeCount := totalCount - inRangeCount
+
+			var inRangePercentage float64
+			if totalCount > 0 {
+				inRangePercentage = float64(inRangeCount) / float64(totalCount) * 100
+			}
 
-	if len(queryResult.Rows) == 0 {
-		return nil, fmt.Errorf("range check query returned no results")
-	}
+			result := &CheckResult{
+				ActualValue: inRangePercentage,
+				Details: map[string]interface{}{
+					"total_count":         totalCount,
+					"in_range_count":      inRangeCount,
+					"out_of_range_count":  outOfRangeCount,
+					"in_range_percentage": inRangePercentage,
+					"min_value":           params.ExpectedMin,
+					"max_value":           params.ExpectedMax,
+				},
+			}
+			recordSample(result, check, totalCount)
+			recordProportionBounds(result, check, inRangeCount, totalCount)
 
-	row := queryResult.Rows[0]
-	totalCount := toInt64(row["total_count"])
-	inRangeCount := toInt64(row["in_range_count"])
-	outOfRangeCount := totalCount - inRangeCount
+			expectedInRange := 100.0
+			if check.Threshold.Value > 0 {
+				expectedInRange = check.Threshold.Value
+			}
 
-	var inRangePercentage float64
-	if totalCount > 0 {
-		inRangePercentage = float64(inRangeCount) / float64(totalCount) * 100
-	}
+			inRange := rowMetric("in-range count", inRangeCount, totalCount, "in-range percentage")
+			if err := evaluateThreshold(result, check.Threshold, inRange, Threshold{Type: ThresholdPercentage, Operator: OperatorGte, Value: expectedInRange}); err != nil {
+				return nil, err
+			}
 
-	result := &CheckResult{
-		ActualValue: inRangePercentage,
-		Details: map[string]interface{}{
-			"total_count":         totalCount,
-			"in_range_count":      inRangeCount,
-			"out_of_range_count":  outOfRangeCount,
-			"in_range_percentage": inRangePercentage,
-			"min_value":           params.ExpectedMin,
-			"max_value":           params.ExpectedMax,
+			return result, nil
 		},
-	}
-
-	expectedInRange := 100.0
-	if check.Threshold.Value > 0 {
-		expectedInRange = check.Threshold.Value
-	}
-
-	if inRangePercentage < expectedInRange {
-		result.Status = StatusFailed
-		result.ExpectedValue = expectedInRange
-		result.Message = fmt.Sprintf("in-range percentage %.2f%% is below expected %.2f%%", inRangePercentage, expectedInRange)
-	} else {
-		result.Status = StatusPassed
-		result.Message = fmt.Sprintf("in-range percentage %.2f%% meets expectation", inRangePercentage)
-	}
-
-	return result, nil
+	}, nil
 }
 
 // runSetMembershipCheck executes a set membership check
 func (m *Manager) runSetMembershipCheck(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
+	return m.runSpec(ctx, check, connector, setMembershipSpec)
+}
+
+// setMembershipSpec builds the aggregate of a set membership check
+func setMembershipSpec(ctx context.Context, check *Check, connector datasource.Connector) (aggregateSpec, error) {
 	allowedValues := check.Parameters.AllowedValues
 	if len(allowedValues) == 0 {
-		return nil, fmt.Errorf("allowed values not specified for set membership check")
-	}
-
-	// Build IN clause
-	inClause := ""
-	for i, v := range allowedValues {
-		if i > 0 {
-			inClause += ", "
-		}
-		inClause += fmt.Sprintf("'%s'", v)
+		return aggregateSpec{}, fmt.Errorf("allowed values not specified for set membership check")
 	}
 
-	query := fmt.Sprintf(`
-		SELECT 
-			COUNT(*) as total_count,
-			SUM(CASE WHEN %s IN (%s) THEN 1 ELSE 0 END) as valid_count
-		FROM %s`, check.Column, inClause, check.Table)
+	inClause := inList(allowedValues)
 
-	queryResult, err := connector.Query(ctx, query)
+	from, err := tableRef(ctx, check, connector, "")
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute set membership check query: %w", err)
-	}
-
-	if len(queryResult.Rows) == 0 {
-		return nil, fmt.Errorf("set membership check query returned no results")
+		return aggregateSpec{}, err
 	}
 
-	row := queryResult.Rows[0]
-	totalCount := toInt64(row["total_count"])
-	validCount := toInt64(row["valid_count"])
-	invalidCount := totalCount - validCount
-
-	var validPercentage float64
-	if totalCount > 0 {
-		validPercentage = float64(validCount) / float64(totalCount) * 100
-	}
-
-	result := &CheckResult{
-		ActualValue: validPercentage,
-		Details: map[string]interface{}{
-			"total_count":      totalCount,
-			"valid_count":      validCount,
-			"invalid_count":    invalidCount,
-			"valid_percentage": validPercentage,
-			"allowed_values":   allowedValues,
+	return aggregateSpec{
+		description: "set membership check",
+		aggregates: []aggregate{
+			{"COUNT(*)", "total_count"},
+			{fmt.Sprintf("SUM(CASE WHEN %s IN (%s) THEN 1 ELSE 0 END)", check.Column, inClause), "valid_count"},
 		},
-	}
+		from: from,
+		evaluate: func(row map[string]interface{}) (*CheckResult, error) {
+			totalCount := toInt64(row["total_count"])
+			validCount := toInt64(row["valid_count"])
+			invalidCount := totalCount - validCount
+
+			var validPercentage float64
+			if totalCount > 0 {
+				validPercentage = float64(validCount) / float64(totalCount) * 100
+			}
 
-	expectedValid := 100.0
-	if check.Threshold.Value > 0 {
-		expectedValid = check.Threshold.Value
-	}
+			result := &CheckResult{
+				ActualValue: validPercentage,
+				Details: map[string]interface{}{
+					"total_count":      totalCount,
+					"valid_count":      validCount,
+					"invalid_count":    invalidCount,
+					"valid_percentage": validPercentage,
+					"allowed_values":   allowedValues,
+				},
+			}
+			recordSample(result, check, totalCount)
+			recordProportionBounds(result, check, validCount, totalCount)
 
-	if validPercentage < expectedValid {
-		result.Status = StatusFailed
-		result.ExpectedValue = expectedValid
-		result.Message = fmt.Sprintf("valid percentage %.2f%% is below expected %.2f%%", validPercentage, expectedValid)
-	} else {
-		result.Status = StatusPassed
-		result.Message = fmt.Sprintf("valid percentage %.2f%% meets expectation", validPercentage)
-	}
+			expectedValid := 100.0
+			if check.Threshold.Value > 0 {
+				expectedValid = check.Threshold.Value
+			}
 
-	return result, nil
+			valid := rowMetric("valid count", validCount, totalCount, "valid percentage")
+			if err := evaluateThreshold(result, check.Threshold, valid, Threshold{Type: ThresholdPercentage, Operator: OperatorGte, Value: expectedValid}); err != nil {
+				return nil, err
+			}
+
+			return result, nil
+		},
+	}, nil
 }
 
 // runReferentialCheck executes a referential integrity check
@@ -558,65 +653,64 @@ func (m *Manager) runReferentialCheck(ctx context.Context, check *Check, connect
 		return nil, fmt.Errorf("reference table/column not specified")
 	}
 
-	query := fmt.Sprintf(`
-		SELECT 
-			COUNT(*) as total_count,
-			COUNT(r.%s) as matched_count
-		FROM %s t
-		LEFT JOIN %s r ON t.%s = r.%s`,
-		params.ReferenceColumn,
-		check.Table,
-		params.ReferenceTable,
-		check.Column,
-		params.ReferenceColumn)
-
-	queryResult, err := connector.Query(ctx, query)
+	from, err := tableRef(ctx, check, connector, "t")
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute referential check query: %w", err)
+		return nil, err
 	}
 
-	if len(queryResult.Rows) == 0 {
-		return nil, fmt.Errorf("referential check query returned no results")
-	}
-
-	row := queryResult.Rows[0]
-	totalCount := toInt64(row["total_count"])
-	matchedCount := toInt64(row["matched_count"])
-	orphanCount := totalCount - matchedCount
-
-	var integrityPercentage float64
-	if totalCount > 0 {
-		integrityPercentage = float64(matchedCount) / float64(totalCount) * 100
-	}
-
-	result := &CheckResult{
-		ActualValue: integrityPercentage,
-		Details: map[string]interface{}{
-			"total_count":          totalCount,
-			"matched_count":        matchedCount,
-			"orphan_count":         orphanCount,
-			"integrity_percentage": integrityPercentage,
-			"reference_table":      params.ReferenceTable,
-			"reference_column":     params.ReferenceColumn,
+	return m.runAggregate(ctx, check, connector, aggregateSpec{
+		description: "referential check",
+		aggregates: []aggregate{
+			{"COUNT(*)", "total_count"},
+			{fmt.Sprintf("COUNT(r.%s)", params.ReferenceColumn), "matched_count"},
 		},
-	}
+		from: fmt.Sprintf(`%s
+		LEFT JOIN %s r ON t.%s = r.%s`,
+			from,
+			params.ReferenceTable,
+			check.Column,
+			params.ReferenceColumn),
+		alias: "t",
+		evaluate: func(row map[string]interface{}) (*CheckResult, error) {
+			totalCount := toInt64(row["total_count"])
+			matchedCount := toInt64(row["matched_count"])
+			orphanCount := totalCount - matchedCount
+
+			var integrityPercentage float64
+			if totalCount > 0 {
+				integrityPercentage = float64(matchedCount) / float64(totalCount) * 100
+			}
 
-	expectedIntegrity := 100.0
-	if check.Threshold.Value > 0 {
-		expectedIntegrity = check.Threshold.Value
-	}
+			result := &CheckResult{
+				ActualValue: integrityPercentage,
+				Details: map[string]interface{}{
+					"total_count":          totalCount,
+					"matched_count":        matchedCount,
+					"orphan_count":         orphanCount,
+					"integrity_percentage": integrityPercentage,
+					"reference_table":      params.ReferenceTable,
+					"reference_column":     params.ReferenceColumn,
+				},
+			}
+			recordSample(result, check, totalCount)
+			recordProportionBounds(result, check, matchedCount, totalCount)
 
-	if integrityPercentage < expectedIntegrity {
-		result.Status = StatusFailed
-		result.ExpectedValue = expectedIntegrity
-		result.Message = fmt.Sprintf("referential integrity %.2f%% is below expected %.2f%% (%d orphans)", 
-			integrityPercentage, expectedIntegrity, orphanCount)
-	} else {
-		result.Status = StatusPassed
-		result.Message = fmt.Sprintf("referential integrity %.2f%% meets expectation", integrityPercentage)
-	}
+			expectedIntegrity := 100.0
+			if check.Threshold.Value > 0 {
+				expectedIntegrity = check.Threshold.Value
+			}
 
-	return result, nil
+			matched := rowMetric("matched count", matchedCount, totalCount, "referential integrity")
+			if err := evaluateThreshold(result, check.Threshold, matched, Threshold{Type: ThresholdPercentage, Operator: OperatorGte, Value: expectedIntegrity}); err != nil {
+				return nil, err
+			}
+			if result.Status != StatusPassed {
+				result.Message += fmt.Sprintf(" (%d orphans)", orphanCount)
+			}
+
+			return result, nil
+		},
+	})
 }
 
 // runSchemaCheck executes a schema validation check
@@ -677,13 +771,114 @@ func (m *Manager) runSchemaCheck(ctx context.Context, check *Check, connector da
 	result.Details["extra_columns"] = extraColumns
 	result.Details["type_mismatches"] = typeMismatches
 
-	if len(missingColumns) > 0 || len(typeMismatches) > 0 {
-		result.Status = StatusFailed
-		result.Message = fmt.Sprintf("schema mismatch: %d missing columns, %d type mismatches", 
-			len(missingColumns), len(typeMismatches))
-	} else {
-		result.Status = StatusPassed
-		result.Message = "schema matches expected"
+	mismatches := metric{name: "schema mismatches", value: float64(len(missingColumns) + len(typeMismatches))}
+	if err := evaluateThreshold(result, check.Threshold, mismatches, Threshold{Type: ThresholdAbsolute, Operator: OperatorEq, Value: 0}); err != nil {
+		return nil, err
+	}
+	if result.Status != StatusPassed {
+		result.Message = fmt.Sprintf("schema mismatch: %d missing columns, %d type mismatches (%s)",
+			len(missingColumns), len(typeMismatches), result.Message)
+	}
+
+	return result, nil
+}
+
+// runColumnCountCheck compares a table's number of columns to the expected count
+func (m *Manager) runColumnCountCheck(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
+	expected := check.Parameters.ExpectedColumns
+	if expected == 0 {
+		expected = len(check.Parameters.ExpectedSchema)
+	}
+	if expected == 0 {
+		return nil, fmt.Errorf("expected column count not specified")
+	}
+
+	columns, err := connector.GetColumns(ctx, check.Table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table columns: %w", err)
+	}
+
+	names := make([]string, 0, len(columns))
+	for _, col := range columns {
+		names = append(names, col.Name)
+	}
+
+	result := &CheckResult{
+		ActualValue: len(columns),
+		Details: map[string]interface{}{
+			"column_count":     len(columns),
+			"expected_columns": expected,
+			"columns":          names,
+		},
+	}
+
+	result.ExpectedValue = expected
+	count := metric{name: "column count", value: float64(len(columns)), reference: float64(expected), percentName: "column count percentage of expected"}
+	if err := evaluateThreshold(result, check.Threshold, count, Threshold{Type: ThresholdAbsolute, Operator: OperatorEq, Value: float64(expected)}); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// runColumnTypeCheck verifies the data types of the columns listed in the
+// expected schema, ignoring columns that are not listed. When the check names
+// a column, only that column is verified.
+func (m *Manager) runColumnTypeCheck(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
+	expected := []datasource.ColumnInfo{}
+	for _, col := range check.Parameters.ExpectedSchema {
+		if check.Column == "" || col.Name == check.Column {
+			expected = append(expected, col)
+		}
+	}
+	if len(expected) == 0 {
+		return nil, fmt.Errorf("expected column types not specified")
+	}
+
+	columns, err := connector.GetColumns(ctx, check.Table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table columns: %w", err)
+	}
+
+	actualTypes := make(map[string]string, len(columns))
+	for _, col := range columns {
+		actualTypes[col.Name] = col.DataType
+	}
+
+	missingColumns := []string{}
+	typeMismatches := []string{}
+	for _, col := range expected {
+		actual, exists := actualTypes[col.Name]
+		switch {
+		case !exists:
+			missingColumns = append(missingColumns, col.Name)
+		case !sameType(col.DataType, actual):
+			typeMismatches = append(typeMismatches, fmt.Sprintf("%s: expected %s, got %s", col.Name, col.DataType, actual))
+		}
+	}
+
+	result := &CheckResult{
+		ActualValue: len(expected) - len(missingColumns) - len(typeMismatches),
+		Details: map[string]interface{}{
+			"checked_columns": len(expected),
+			"missing_columns": missingColumns,
+			"type_mismatches": typeMismatches,
+		},
+	}
+
+	result.ExpectedValue = len(expected)
+	matching := metric{
+		name:        "matching column types",
+		value:       float64(len(expected) - len(missingColumns) - len(typeMismatches)),
+		reference:   float64(len(expected)),
+		percentName: "matching column type percentage",
+	}
+	if err := evaluateThreshold(result, check.Threshold, matching, Threshold{Type: ThresholdAbsolute, Operator: OperatorEq, Value: float64(len(expected))}); err != nil {
+		return nil, err
+	}
+	if result.Status != StatusPassed {
+		result.Message = fmt.Sprintf("column types mismatch: %d missing columns, %d type mismatches (%s)",
+			len(missingColumns), len(typeMismatches), result.Message)
 	}
 
 	return result, nil
@@ -691,6 +886,11 @@ func (m *Manager) runSchemaCheck(ctx context.Context, check *Check, connector da
 
 // Helper functions
 
+// sameType compares data type names ignoring case and surrounding whitespace
+func sameType(expected, actual string) bool {
+	return strings.EqualFold(strings.TrimSpace(expected), strings.TrimSpace(actual))
+}
+
 func toInt64(v interface{}) int64 {
 	switch val := v.(type) {
 	case int64:
@@ -708,6 +908,33 @@ func toInt64(v interface{}) int64 {
 	}
 }
 
+// inList renders values as the quoted list of a SQL IN clause
+func inList(values []string) string {
+	list := ""
+	for i, v := range values {
+		if i > 0 {
+			list += ", "
+		}
+		list += fmt.Sprintf("'%s'", v)
+	}
+	return list
+}
+
+// parseMetric parses a numeric value that a driver returned as text or bytes
+func parseMetric(v interface{}) (float64, bool) {
+	var text string
+	switch val := v.(type) {
+	case string:
+		text = val
+	case []byte:
+		text = string(val)
+	default:
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	return value, err == nil
+}
+
 func toFloat64(v interface{}) float64 {
 	switch val := v.(type) {
 	case float64:
@@ -0,0 +1,189 @@
+package check
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+func TestRunStdDevCheck(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dsType   datasource.Type
+		value    float64
+		expected Status
+		sql      string
+	}{
+		{"within tolerance", datasource.TypePostgres, 10.4, StatusPassed, "STDDEV_SAMP(amount)"},
+		{"outside tolerance", datasource.TypePostgres, 12, StatusFailed, "STDDEV_SAMP(amount)"},
+		{"sql server", datasource.TypeSQLServer, 10, StatusPassed, "STDEV(amount)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			connector := &fakeConnector{dsType: tc.dsType, rows: []map[string]interface{}{{"value": tc.value}}}
+			check := &Check{
+				Type:       TypeStdDev,
+				Table:      "orders",
+				Column:     "amount",
+				Parameters: CheckParameters{ExpectedStdDev: 10, Tolerance: 0.5},
+			}
+
+			result, err := NewManager(datasource.NewManager()).executeCheck(context.Background(), check, connector)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Status != tc.expected {
+				t.Errorf("expected status %s, got %s: %s", tc.expected, result.Status, result.Message)
+			}
+			if !strings.Contains(connector.queries[0], tc.sql) {
+				t.Errorf("expected %s in query, got %s", tc.sql, connector.queries[0])
+			}
+		})
+	}
+}
+
+func TestRunFormatCheck(t *testing.T) {
+	connector := &fakeConnector{rows: []map[string]interface{}{{"total_count": int64(100), "match_count": int64(97)}}}
+	check := &Check{
+		Type:       TypeFormat,
+		Table:      "users",
+		Column:     "email",
+		Parameters: CheckParameters{Format: "email"},
+		Threshold:  Threshold{Value: 95},
+	}
+
+	result, err := NewManager(datasource.NewManager()).executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusPassed {
+		t.Errorf("expected passed, got %s: %s", result.Status, result.Message)
+	}
+	if result.Details["format"] != "email" || result.Details["non_match_count"] != int64(3) {
+		t.Errorf("unexpected details: %v", result.Details)
+	}
+	if !strings.Contains(connector.queries[0], "email ~ '"+Formats["email"]+"'") {
+		t.Errorf("expected postgres regex match in query, got %s", connector.queries[0])
+	}
+}
+
+func TestRunFormatCheck_Errors(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+
+	unknown := &Check{Type: TypeFormat, Table: "users", Column: "email", Parameters: CheckParameters{Format: "zip"}}
+	if _, err := m.executeCheck(context.Background(), unknown, &fakeConnector{}); err == nil {
+		t.Error("expected error for unknown format")
+	}
+
+	unsupported := &Check{Type: TypeFormat, Table: "users", Column: "email", Parameters: CheckParameters{Format: "email"}}
+	if _, err := m.executeCheck(context.Background(), unsupported, &fakeConnector{dsType: datasource.TypeSQLServer}); err == nil {
+		t.Error("expected error for engine without regex support")
+	}
+}
+
+func TestFormats(t *testing.T) {
+	testCases := []struct {
+		format  string
+		valid   []string
+		invalid []string
+	}{
+		{"email", []string{"a.b+c@example.co.uk"}, []string{"a@b", "no-at.example.com"}},
+		{"uuid", []string{"123e4567-e89b-12d3-a456-426614174000"}, []string{"123e4567e89b12d3a456426614174000"}},
+		{"iso_date", []string{"2024-02-29"}, []string{"2024-13-01", "24-01-01"}},
+		{"iso_datetime", []string{"2024-02-29T13:45:00Z", "2024-02-29 13:45:00.123+05:30"}, []string{"2024-02-29T25:00"}},
+		{"phone", []string{"+14155552671", "442071838750"}, []string{"+1 415 555", "0123"}},
+		{"iban", []string{"GB82WEST12345698765432"}, []string{"GB82 WEST 1234"}},
+		{"ip", []string{"192.168.0.1", "2001:db8::1"}, []string{"256.1.1.1", "host"}},
+		{"ipv6",
+			[]string{"2001:0db8:85a3:0000:0000:8a2e:0370:7334", "2001:db8::1", "::1", "::", "fe80::", "1:2:3:4:5:6:7::", "::2:3:4:5:6:7:8"},
+			[]string{":::::::", "1::2::3", "1:2:3:4:5:6:7:8:9", "1:2:3:4:5:6:7", "12345::1", "g::1", ":1:2::3", "1:2:3::4:"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			re := regexp.MustCompile(Formats[tc.format])
+			for _, v := range tc.valid {
+				if !re.MatchString(v) {
+					t.Errorf("expected %q to match", v)
+				}
+			}
+			for _, v := range tc.invalid {
+				if re.MatchString(v) {
+					t.Errorf("expected %q not to match", v)
+				}
+			}
+		})
+	}
+}
+
+func TestRunColumnCountCheck(t *testing.T) {
+	connector := &fakeConnector{columns: []datasource.ColumnInfo{{Name: "id"}, {Name: "email"}, {Name: "created_at"}}}
+	m := NewManager(datasource.NewManager())
+
+	pass := &Check{Type: TypeColumnCount, Table: "users", Parameters: CheckParameters{ExpectedColumns: 3}}
+	result, err := m.executeCheck(context.Background(), pass, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusPassed {
+		t.Errorf("expected passed, got %s: %s", result.Status, result.Message)
+	}
+
+	fromSchema := &Check{Type: TypeColumnCount, Table: "users", Parameters: CheckParameters{
+		ExpectedSchema: []datasource.ColumnInfo{{Name: "id"}, {Name: "email"}},
+	}}
+	result, err = m.executeCheck(context.Background(), fromSchema, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusFailed || result.ExpectedValue != 2 {
+		t.Errorf("expected failure against 2 columns, got %s: %s", result.Status, result.Message)
+	}
+
+	missing := &Check{Type: TypeColumnCount, Table: "users"}
+	if _, err := m.executeCheck(context.Background(), missing, connector); err == nil {
+		t.Error("expected error without an expected count")
+	}
+}
+
+func TestRunColumnTypeCheck(t *testing.T) {
+	connector := &fakeConnector{columns: []datasource.ColumnInfo{
+		{Name: "id", DataType: "bigint"},
+		{Name: "email", DataType: "text"},
+		{Name: "extra", DataType: "jsonb"},
+	}}
+	m := NewManager(datasource.NewManager())
+	schema := []datasource.ColumnInfo{
+		{Name: "id", DataType: "BIGINT"},
+		{Name: "email", DataType: "varchar"},
+		{Name: "created_at", DataType: "timestamp"},
+	}
+
+	all := &Check{Type: TypeColumnType, Table: "users", Parameters: CheckParameters{ExpectedSchema: schema}}
+	result, err := m.executeCheck(context.Background(), all, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusFailed {
+		t.Errorf("expected failure, got %s", result.Status)
+	}
+	if missing := result.Details["missing_columns"].([]string); len(missing) != 1 || missing[0] != "created_at" {
+		t.Errorf("expected created_at missing, got %v", missing)
+	}
+	if mismatches := result.Details["type_mismatches"].([]string); len(mismatches) != 1 {
+		t.Errorf("expected 1 type mismatch, got %v", mismatches)
+	}
+
+	single := &Check{Type: TypeColumnType, Table: "users", Column: "id", Parameters: CheckParameters{ExpectedSchema: schema}}
+	result, err = m.executeCheck(context.Background(), single, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusPassed {
+		t.Errorf("expected case-insensitive type match to pass, got %s: %s", result.Status, result.Message)
+	}
+}
@@ -0,0 +1,178 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+	"github.com/vinod901/opendq-go/internal/expr"
+)
+
+// ExpressionMode selects where an expression check evaluates its rule
+type ExpressionMode string
+
+const (
+	ExpressionAuto ExpressionMode = "auto" // In SQL when the rule translates, in Go otherwise
+	ExpressionSQL  ExpressionMode = "sql"  // In SQL only; rules without a translation fail
+	ExpressionGo   ExpressionMode = "go"   // In Go over the fetched rows
+)
+
+// maxExpressionRows is how many rows an expression check evaluates in Go
+const maxExpressionRows = 100000
+
+// validateExpressionParams checks an expression check's rule syntax and mode
+func validateExpressionParams(params CheckParameters) error {
+	switch params.ExpressionMode {
+	case "", ExpressionAuto, ExpressionSQL, ExpressionGo:
+	default:
+		return fmt.Errorf("unsupported expression mode: %s", params.ExpressionMode)
+	}
+	return expr.Parse(params.Expression)
+}
+
+// compileExpression compiles an expression check's rule against the columns
+// of its table, which connectors resolve when it is schema-qualified
+func compileExpression(ctx context.Context, check *Check, connector datasource.Connector) (*expr.Expression, error) {
+	columns, err := connector.GetColumns(ctx, check.Table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table columns: %w", err)
+	}
+	return expr.Compile(check.Parameters.Expression, columns)
+}
+
+// runExpressionCheck counts the rows violating a rule, i.e. for which it is
+// not true. The rule is evaluated in SQL when it translates to the engine's
+// dialect and the mode allows it, and over the fetched rows otherwise.
+func (m *Manager) runExpressionCheck(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
+	if err := validateExpressionParams(check.Parameters); err != nil {
+		return nil, err
+	}
+	compiled, err := compileExpression(ctx, check, connector)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := check.Parameters.ExpressionMode
+	if mode != ExpressionGo {
+		condition, err := compiled.SQL(datasource.DialectFor(connector.Type()))
+		if err == nil {
+			return m.runSpec(ctx, check, connector, func(ctx context.Context, check *Check, connector datasource.Connector) (aggregateSpec, error) {
+				return expressionSpec(ctx, check, connector, compiled, condition)
+			})
+		}
+		if mode == ExpressionSQL || !errors.Is(err, expr.ErrNoSQL) {
+			return nil, err
+		}
+	}
+
+	if len(check.SegmentBy) > 0 {
+		return nil, fmt.Errorf("segmented expression checks need a rule that translates to SQL")
+	}
+	return evaluateExpressionRows(ctx, check, connector, compiled)
+}
+
+// expressionSpec builds the aggregate counting the rows violating a rule
+// translated to a SQL condition
+func expressionSpec(ctx context.Context, check *Check, connector datasource.Connector, compiled *expr.Expression, condition string) (aggregateSpec, error) {
+	from, err := tableRef(ctx, check, connector, "")
+	if err != nil {
+		return aggregateSpec{}, err
+	}
+
+	return aggregateSpec{
+		description: "expression check",
+		aggregates: []aggregate{
+			{"COUNT(*)", "total_count"},
+			{fmt.Sprintf("SUM(CASE WHEN %s THEN 0 ELSE 1 END)", condition), "violation_count"},
+		},
+		from: from,
+		evaluate: func(row map[string]interface{}) (*CheckResult, error) {
+			result, err := evaluateExpression(check, compiled, toInt64(row["total_count"]), toInt64(row["violation_count"]))
+			if err != nil {
+				return nil, err
+			}
+			result.Details["evaluated_in"] = "sql"
+			result.Details["sql"] = condition
+			return result, nil
+		},
+	}, nil
+}
+
+// evaluateExpressionRows fetches the columns a rule references and counts
+// the rows violating it. Rows whose evaluation fails, e.g. on arithmetic
+// with NULL, violate it. Tables over maxExpressionRows rows, after filters
+// and sampling, fail the run rather than load into memory.
+func evaluateExpressionRows(ctx context.Context, check *Check, connector datasource.Connector, compiled *expr.Expression) (*CheckResult, error) {
+	from, err := tableRef(ctx, check, connector, "")
+	if err != nil {
+		return nil, err
+	}
+	columns := "*"
+	if refs := compiled.Columns(); len(refs) > 0 {
+		columns = strings.Join(refs, ", ")
+	}
+
+	limit := datasource.DialectFor(connector.Type()).LimitClause(maxExpressionRows + 1)
+	queryResult, err := connector.Query(ctx, fmt.Sprintf("SELECT %s FROM %s %s", columns, from, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute expression check query: %w", err)
+	}
+	if len(queryResult.Rows) > maxExpressionRows {
+		return nil, fmt.Errorf("expression checks evaluated in Go read at most %d rows: filter or sample the table, or use a rule that translates to SQL", maxExpressionRows)
+	}
+
+	var violations, errorCount int64
+	var firstError string
+	for _, row := range queryResult.Rows {
+		ok, err := compiled.Eval(row)
+		if err != nil {
+			errorCount++
+			if firstError == "" {
+				firstError = err.Error()
+			}
+		}
+		if !ok {
+			violations++
+		}
+	}
+
+	result, err := evaluateExpression(check, compiled, int64(len(queryResult.Rows)), violations)
+	if err != nil {
+		return nil, err
+	}
+	result.Details["evaluated_in"] = "go"
+	if errorCount > 0 {
+		result.Details["error_count"] = errorCount
+		result.Details["first_error"] = firstError
+	}
+	return result, nil
+}
+
+// evaluateExpression judges a rule's violation count. Without a threshold
+// of its own the check fails on any violating row.
+func evaluateExpression(check *Check, compiled *expr.Expression, totalCount, violationCount int64) (*CheckResult, error) {
+	var violationPercentage float64
+	if totalCount > 0 {
+		violationPercentage = float64(violationCount) / float64(totalCount) * 100
+	}
+
+	result := &CheckResult{
+		ActualValue: violationPercentage,
+		Details: map[string]interface{}{
+			"expression":           compiled.Source(),
+			"total_count":          totalCount,
+			"violation_count":      violationCount,
+			"violation_percentage": violationPercentage,
+		},
+	}
+	recordSample(result, check, totalCount)
+	recordProportionBounds(result, check, violationCount, totalCount)
+
+	violations := rowMetric("violation count", violationCount, totalCount, "violation percentage")
+	if err := evaluateThreshold(result, check.Threshold, violations, Threshold{Type: ThresholdAbsolute, Operator: OperatorLte, Value: 0}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
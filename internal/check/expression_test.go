@@ -0,0 +1,150 @@
+package check
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+var orderColumns = []datasource.ColumnInfo{
+	{Name: "price", DataType: "numeric"},
+	{Name: "discount", DataType: "numeric"},
+	{Name: "tier", DataType: "varchar"},
+	{Name: "quantity", DataType: "integer"},
+}
+
+func TestRunExpressionCheck_SQL(t *testing.T) {
+	check := &Check{
+		Type:       TypeExpression,
+		Table:      "orders",
+		Parameters: CheckParameters{Expression: `discount <= price * 0.5 || tier == "vip"`},
+	}
+	connector := &fakeConnector{
+		dsType:  datasource.TypePostgres,
+		columns: orderColumns,
+		rows:    []map[string]interface{}{{"total_count": int64(200), "violation_count": int64(5)}},
+	}
+	m := NewManager(datasource.NewManager())
+
+	result, err := m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusFailed || result.ActualValue != 2.5 || result.Details["evaluated_in"] != "sql" {
+		t.Errorf("expected a failed SQL evaluation at 2.5%%, got %s %v: %v", result.Status, result.ActualValue, result.Details)
+	}
+	query := connector.queries[len(connector.queries)-1]
+	if !strings.Contains(query, "SUM(CASE WHEN ((discount <= (price * 0.5)) OR ((tier = 'vip') AND tier IS NOT NULL)) THEN 0 ELSE 1 END)") {
+		t.Errorf("unexpected query: %s", query)
+	}
+
+	check.Threshold = Threshold{Type: ThresholdPercentage, Operator: OperatorLte, Value: 5}
+	result, err = m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusPassed {
+		t.Errorf("expected 2.5%% to pass a 5%% threshold, got %s: %s", result.Status, result.Message)
+	}
+}
+
+func TestRunExpressionCheck_QualifiedTable(t *testing.T) {
+	check := &Check{
+		Type:       TypeExpression,
+		Table:      "sales.orders",
+		Parameters: CheckParameters{Expression: `discount <= price`},
+	}
+	connector := &fakeConnector{
+		dsType: datasource.TypePostgres,
+		tables: map[string][]datasource.ColumnInfo{"sales.orders": orderColumns},
+		rows:   []map[string]interface{}{{"total_count": int64(10), "violation_count": int64(0)}},
+	}
+	m := NewManager(datasource.NewManager())
+
+	result, err := m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusPassed {
+		t.Errorf("expected the rule to compile against the qualified table, got %s: %s", result.Status, result.Message)
+	}
+	if query := connector.queries[len(connector.queries)-1]; !strings.Contains(query, "FROM sales.orders") {
+		t.Errorf("expected the qualified table in the query, got %s", query)
+	}
+}
+
+func TestRunExpressionCheck_Go(t *testing.T) {
+	check := &Check{
+		Type:       TypeExpression,
+		Table:      "orders",
+		Parameters: CheckParameters{Expression: `quantity % 2 == 0 || tier == "vip"`},
+	}
+	connector := &fakeConnector{
+		dsType:  datasource.TypePostgres,
+		columns: orderColumns,
+		rows: []map[string]interface{}{
+			{"quantity": int64(2), "tier": "basic"},
+			{"quantity": int64(3), "tier": "basic"},
+			{"quantity": int64(3), "tier": "vip"},
+			{"quantity": nil, "tier": "basic"},
+		},
+	}
+	m := NewManager(datasource.NewManager())
+
+	result, err := m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Details["evaluated_in"] != "go" || result.Details["violation_count"] != int64(2) || result.Details["error_count"] != int64(1) {
+		t.Errorf("expected 2 violations in Go with 1 error, got %v", result.Details)
+	}
+	if query := connector.queries[len(connector.queries)-1]; query != "SELECT quantity, tier FROM orders LIMIT 100001" {
+		t.Errorf("expected only referenced columns to be fetched, got %s", query)
+	}
+
+	check.Parameters.ExpressionMode = ExpressionSQL
+	if _, err := m.executeCheck(context.Background(), check, connector); err == nil {
+		t.Error("expected an error for a rule without SQL translation in sql mode")
+	}
+
+	check.Parameters.ExpressionMode = ""
+	check.SegmentBy = []string{"tier"}
+	if _, err := m.executeCheck(context.Background(), check, connector); err == nil {
+		t.Error("expected an error for a segmented rule evaluated in Go")
+	}
+
+	check.SegmentBy = nil
+	connector.rows = make([]map[string]interface{}, maxExpressionRows+1)
+	for i := range connector.rows {
+		connector.rows[i] = map[string]interface{}{"quantity": int64(2), "tier": "basic"}
+	}
+	if _, err := m.executeCheck(context.Background(), check, connector); err == nil || !strings.Contains(err.Error(), "at most 100000 rows") {
+		t.Errorf("expected tables over the row cap to fail, got %v", err)
+	}
+}
+
+func TestExpressionValidation(t *testing.T) {
+	connector := &fakeConnector{dsType: datasource.TypePostgres, columns: orderColumns}
+	check := &Check{Type: TypeExpression, Table: "orders", Parameters: CheckParameters{Expression: `missing > 1`}}
+	if _, err := compileExpression(context.Background(), check, connector); err == nil || !strings.Contains(err.Error(), "undeclared reference to 'missing'") {
+		t.Errorf("expected an undeclared column error, got %v", err)
+	}
+
+	m := NewManager(datasource.NewManager())
+	for _, params := range []CheckParameters{
+		{Expression: `price >`},
+		{Expression: ""},
+		{Expression: `price > 1.0`, ExpressionMode: "python"},
+	} {
+		err := m.CreateCheck(context.Background(), &Check{Type: TypeExpression, Table: "orders", Parameters: params})
+		if err == nil {
+			t.Errorf("%+v: expected error", params)
+		}
+	}
+	err := m.CreateCheck(context.Background(), &Check{Type: TypeExpression, DatasourceID: "missing", Table: "orders", Parameters: CheckParameters{Expression: `price > 1.0`}})
+	if err == nil || !strings.Contains(err.Error(), "failed to get datasource connector") {
+		t.Errorf("expected the rule to be type-checked against the datasource, got %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+const (
+	defaultFailingRowsLimit = 10
+	// maskedValue replaces the values of columns tagged as PII
+	maskedValue = "****"
+)
+
+// FailingRowsConfig configures the capture of rows that fail a check
+type FailingRowsConfig struct {
+	Limit   int      `json:"limit,omitempty"`   // Rows attached to a result, default 10
+	Columns []string `json:"columns,omitempty"` // Defaults to the checked column; primary key columns always come first
+}
+
+// FailingRows is a set of rows that fail a check
+type FailingRows struct {
+	Columns   []string                 `json:"columns"`
+	Rows      []map[string]interface{} `json:"rows"`
+	Masked    []string                 `json:"masked_columns,omitempty"`
+	Truncated bool                     `json:"truncated"` // More rows fail than were captured
+}
+
+// supportsFailingRows reports whether a check type judges individual rows,
+// so that the rows failing it can be selected
+func supportsFailingRows(checkType Type) bool {
+	switch checkType {
+	case TypeNullCheck, TypeRegex, TypeFormat, TypeRange, TypeSetMembership, TypeReferentialIntegrity:
+		return true
+	default:
+		return false
+	}
+}
+
+// captureFailingRows attaches a sample of failing rows to a failed or warning
+// result when the check asks for them. Capture problems are recorded in the
+// result details rather than failing the run.
+func (m *Manager) captureFailingRows(ctx context.Context, check *Check, connector datasource.Connector, result *CheckResult) {
+	config := check.Parameters.FailingRows
+	if config == nil || !supportsFailingRows(check.Type) {
+		return
+	}
+	if result.Status != StatusFailed && result.Status != StatusWarning {
+		return
+	}
+
+	limit := config.Limit
+	if limit <= 0 {
+		limit = defaultFailingRowsLimit
+	}
+
+	// Without the datasource's column tags, PII columns could not be masked
+	ds, err := m.datasourceManager.GetDatasource(ctx, check.DatasourceID)
+	if err != nil {
+		result.Details["failing_rows_error"] = err.Error()
+		return
+	}
+
+	// One extra row tells whether the sample is truncated
+	rows, err := queryFailingRows(ctx, check, connector, ds, limit+1)
+	if err != nil {
+		result.Details["failing_rows_error"] = err.Error()
+		return
+	}
+	if len(rows.Rows) > limit {
+		rows.Rows = rows.Rows[:limit]
+		rows.Truncated = true
+	}
+	result.FailingRows = rows
+}
+
+// FailingRows fetches the rows currently failing a check, at most limit when
+// limit is positive. Values of columns tagged as PII are masked.
+func (m *Manager) FailingRows(ctx context.Context, id string, limit int) (*FailingRows, error) {
+	check, err := m.GetCheck(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !supportsFailingRows(check.Type) {
+		return nil, fmt.Errorf("failing rows are not available for %s checks", check.Type)
+	}
+
+	ds, err := m.datasourceManager.GetDatasource(ctx, check.DatasourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = datasource.WithQueryOrigin(ctx, datasource.QueryOrigin{Type: datasource.OriginCheck, ID: check.ID})
+	connector, err := m.datasourceManager.GetConnector(ctx, check.DatasourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get datasource connector: %w", err)
+	}
+
+	return queryFailingRows(ctx, check, connector, ds, limit)
+}
+
+// queryFailingRows selects the rows failing a check, ordered by their first
+// column, which is the primary key when the table has one
+func queryFailingRows(ctx context.Context, check *Check, connector datasource.Connector, ds *datasource.Datasource, limit int) (*FailingRows, error) {
+	columns, err := failingRowColumns(ctx, check, connector)
+	if err != nil {
+		return nil, err
+	}
+
+	join, predicate, err := failingRowPredicate(check, connector)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make([]string, len(columns))
+	for i, col := range columns {
+		selected[i] = "t." + col
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s t%s WHERE %s ORDER BY %s",
+		strings.Join(selected, ", "), filteredTable(check), join, predicate, selected[0])
+	if limit > 0 {
+		query += " " + datasource.DialectFor(connector.Type()).LimitClause(limit)
+	}
+
+	queryResult, err := connector.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query failing rows: %w", err)
+	}
+
+	rows := &FailingRows{Columns: columns, Rows: make([]map[string]interface{}, 0, len(queryResult.Rows))}
+	masked := make(map[string]bool)
+	for _, col := range columns {
+		if ds.HasColumnTag(check.Table, col, datasource.TagPII) {
+			masked[col] = true
+			rows.Masked = append(rows.Masked, col)
+		}
+	}
+	for _, row := range queryResult.Rows {
+		out := make(map[string]interface{}, len(columns))
+		for _, col := range columns {
+			value := row[col]
+			if masked[col] && value != nil {
+				value = maskedValue
+			}
+			out[col] = value
+		}
+		rows.Rows = append(rows.Rows, out)
+	}
+
+	return rows, nil
+}
+
+// failingRowColumns returns the table's primary key columns followed by the
+// configured columns, or the checked column. Connectors resolve
+// schema-qualified table names, so keys are found on sales.orders too.
+func failingRowColumns(ctx context.Context, check *Check, connector datasource.Connector) ([]string, error) {
+	tableColumns, err := connector.GetColumns(ctx, check.Table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table columns: %w", err)
+	}
+
+	var columns []string
+	seen := make(map[string]bool)
+	add := func(col string) {
+		if col != "" && !seen[col] {
+			seen[col] = true
+			columns = append(columns, col)
+		}
+	}
+	for _, col := range tableColumns {
+		if col.IsPrimaryKey {
+			add(col.Name)
+		}
+	}
+
+	requested := []string{check.Column}
+	if config := check.Parameters.FailingRows; config != nil && len(config.Columns) > 0 {
+		requested = config.Columns
+	}
+	for _, col := range requested {
+		add(col)
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns to select for failing rows")
+	}
+	return columns, nil
+}
+
+// failingRowPredicate returns the join and WHERE predicate selecting the rows
+// a check counts as failing, with the checked table aliased as t. As in the
+// executors' counts, a NULL in the checked column fails every check type.
+func failingRowPredicate(check *Check, connector datasource.Connector) (string, string, error) {
+	column := "t." + check.Column
+	params := check.Parameters
+
+	switch check.Type {
+	case TypeNullCheck:
+		return "", column + " IS NULL", nil
+	case TypeRegex, TypeFormat:
+		pattern := params.Pattern
+		if check.Type == TypeFormat {
+			var ok bool
+			if pattern, ok = Formats[params.Format]; !ok {
+				return "", "", fmt.Errorf("unknown format: %s", params.Format)
+			}
+		}
+		match, err := datasource.DialectFor(connector.Type()).RegexMatch(column, pattern)
+		if err != nil {
+			return "", "", err
+		}
+		return "", fmt.Sprintf("(NOT (%s) OR %s IS NULL)", match, column), nil
+	case TypeRange:
+		return "", fmt.Sprintf("(NOT (%s >= %f AND %s <= %f) OR %s IS NULL)",
+			column, params.ExpectedMin, column, params.ExpectedMax, column), nil
+	case TypeSetMembership:
+		return "", fmt.Sprintf("(%s NOT IN (%s) OR %s IS NULL)", column, inList(params.AllowedValues), column), nil
+	case TypeReferentialIntegrity:
+		join := fmt.Sprintf(" LEFT JOIN %s r ON %s = r.%s", params.ReferenceTable, column, params.ReferenceColumn)
+		return join, fmt.Sprintf("r.%s IS NULL", params.ReferenceColumn), nil
+	default:
+		return "", "", fmt.Errorf("failing rows are not available for %s checks", check.Type)
+	}
+}
@@ -0,0 +1,112 @@
+package check
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+func TestQueryFailingRows(t *testing.T) {
+	connector := &fakeConnector{
+		columns: []datasource.ColumnInfo{
+			{Name: "email", DataType: "text"},
+			{Name: "id", DataType: "integer", IsPrimaryKey: true},
+		},
+		rows: []map[string]interface{}{
+			{"id": int64(7), "email": "not-an-email"},
+			{"id": int64(9), "email": nil},
+		},
+	}
+	check := &Check{
+		Type:       TypeFormat,
+		Table:      "users",
+		Column:     "email",
+		Parameters: CheckParameters{Format: "email"},
+	}
+	ds := &datasource.Datasource{ColumnTags: map[string][]string{"users.email": {"PII"}}}
+
+	rows, err := queryFailingRows(context.Background(), check, connector, ds, 11)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	query := connector.queries[0]
+	for _, want := range []string{"SELECT t.id, t.email FROM users t", "IS NULL", "ORDER BY t.id", "LIMIT 11"} {
+		if !strings.Contains(query, want) {
+			t.Errorf("expected %q in query, got %s", want, query)
+		}
+	}
+	if len(rows.Masked) != 1 || rows.Masked[0] != "email" {
+		t.Errorf("expected email to be masked, got %v", rows.Masked)
+	}
+	if rows.Rows[0]["email"] != maskedValue || rows.Rows[0]["id"] != int64(7) {
+		t.Errorf("unexpected first row: %v", rows.Rows[0])
+	}
+	if rows.Rows[1]["email"] != nil {
+		t.Errorf("expected NULL to stay NULL, got %v", rows.Rows[1]["email"])
+	}
+}
+
+func TestQueryFailingRows_QualifiedTable(t *testing.T) {
+	connector := &fakeConnector{
+		tables: map[string][]datasource.ColumnInfo{
+			"sales.orders": {
+				{Name: "order_id", DataType: "integer", IsPrimaryKey: true},
+				{Name: "amount", DataType: "numeric"},
+			},
+		},
+		rows: []map[string]interface{}{{"order_id": int64(3), "amount": nil}},
+	}
+	check := &Check{Type: TypeNullCheck, Table: "sales.orders", Column: "amount"}
+
+	if _, err := queryFailingRows(context.Background(), check, connector, &datasource.Datasource{}, 10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	query := connector.queries[0]
+	for _, want := range []string{"SELECT t.order_id, t.amount FROM sales.orders t", "ORDER BY t.order_id"} {
+		if !strings.Contains(query, want) {
+			t.Errorf("expected %q in query, got %s", want, query)
+		}
+	}
+}
+
+func TestFailingRowPredicate(t *testing.T) {
+	testCases := []struct {
+		name  string
+		check *Check
+		want  string
+	}{
+		{"null", &Check{Type: TypeNullCheck, Column: "email"}, "t.email IS NULL"},
+		{"set membership", &Check{Type: TypeSetMembership, Column: "status", Parameters: CheckParameters{AllowedValues: []string{"a", "b"}}}, "t.status NOT IN ('a', 'b')"},
+		{"referential", &Check{Type: TypeReferentialIntegrity, Column: "user_id", Parameters: CheckParameters{ReferenceTable: "users", ReferenceColumn: "id"}}, "r.id IS NULL"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			join, predicate, err := failingRowPredicate(tc.check, &fakeConnector{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(join+" "+predicate, tc.want) {
+				t.Errorf("expected %q, got join %q predicate %q", tc.want, join, predicate)
+			}
+		})
+	}
+
+	if _, _, err := failingRowPredicate(&Check{Type: TypeRowCount}, &fakeConnector{}); err == nil {
+		t.Error("expected error for a check that does not judge rows")
+	}
+}
+
+func TestCaptureFailingRows_PassedResult(t *testing.T) {
+	connector := &fakeConnector{}
+	check := &Check{Type: TypeNullCheck, Column: "email", Parameters: CheckParameters{FailingRows: &FailingRowsConfig{}}}
+	result := &CheckResult{Status: StatusPassed, Details: map[string]interface{}{}}
+
+	NewManager(datasource.NewManager()).captureFailingRows(context.Background(), check, connector, result)
+	if result.FailingRows != nil || len(connector.queries) != 0 {
+		t.Errorf("expected no capture for a passed result, got %v", result.FailingRows)
+	}
+}
@@ -0,0 +1,45 @@
+package check
+
+import (
+	"fmt"
+
+	"github.com/vinod901/opendq-go/internal/view"
+)
+
+// supportsFilters reports whether a check type reads the rows of its table,
+// so that the rows can be restricted by a filter
+func supportsFilters(checkType Type) bool {
+	switch checkType {
+	case TypeCustomSQL, TypeSchemaMatch, TypeColumnCount, TypeColumnType:
+		return false
+	default:
+		return true
+	}
+}
+
+// rowFilter returns the predicate selecting the rows a check evaluates: its
+// structured filters and raw predicate combined, or "" for the whole table
+func (c *Check) rowFilter() string {
+	return andPredicates(view.FilterSQL(c.Filters), c.Where)
+}
+
+// andPredicates combines two predicates, either of which may be empty
+func andPredicates(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	default:
+		return fmt.Sprintf("(%s) AND (%s)", a, b)
+	}
+}
+
+// filteredTable returns the check's table, restricted to the rows its filter
+// selects. A filtered table is a subquery the caller must give an alias.
+func filteredTable(check *Check) string {
+	if filter := check.rowFilter(); filter != "" {
+		return fmt.Sprintf("(SELECT * FROM %s WHERE %s)", check.Table, filter)
+	}
+	return check.Table
+}
@@ -0,0 +1,145 @@
+package check
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+	"github.com/vinod901/opendq-go/internal/view"
+)
+
+func TestRunNullCheck_Filtered(t *testing.T) {
+	connector := &fakeConnector{rows: []map[string]interface{}{{"total_count": int64(10), "null_count": int64(0)}}}
+	check := &Check{
+		Type:    TypeNullCheck,
+		Table:   "customers",
+		Column:  "email",
+		Filters: []view.FilterDef{{Column: "status", Operator: "eq", Value: "active"}},
+		Where:   "created_at > '2024-01-01'",
+	}
+	m := NewManager(datasource.NewManager())
+
+	if _, err := m.executeCheck(context.Background(), check, connector); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "FROM (SELECT * FROM customers WHERE (status = 'active') AND (created_at > '2024-01-01')) _filtered"
+	if !strings.Contains(connector.queries[0], want) {
+		t.Errorf("expected %q in query, got %s", want, connector.queries[0])
+	}
+}
+
+func TestRunFreshnessCheck_Filtered(t *testing.T) {
+	connector := &fakeConnector{rows: []map[string]interface{}{{"latest_timestamp": time.Now().Add(-time.Hour)}}}
+	check := &Check{
+		Type:       TypeFreshness,
+		Table:      "events",
+		Where:      "source = 'web'",
+		Parameters: CheckParameters{TimestampColumn: "created_at", MaxAgeHours: 24},
+	}
+	m := NewManager(datasource.NewManager())
+
+	result, err := m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusPassed {
+		t.Errorf("expected an hour-old table to be fresh, got %s: %s", result.Status, result.Message)
+	}
+	want := "SELECT MAX(created_at) as latest_timestamp FROM (SELECT * FROM events WHERE source = 'web') _filtered"
+	if connector.queries[0] != want {
+		t.Errorf("expected an aliased filtered subquery, got %s", connector.queries[0])
+	}
+}
+
+func TestRunReferentialCheck_Filtered(t *testing.T) {
+	connector := &fakeConnector{rows: []map[string]interface{}{{"total_count": int64(10), "matched_count": int64(10)}}}
+	check := &Check{
+		Type:    TypeReferentialIntegrity,
+		Table:   "orders",
+		Column:  "customer_id",
+		Filters: []view.FilterDef{{Column: "status", Operator: "ne", Value: "draft"}},
+		Parameters: CheckParameters{
+			ReferenceTable:  "customers",
+			ReferenceColumn: "id",
+		},
+	}
+	m := NewManager(datasource.NewManager())
+
+	if _, err := m.executeCheck(context.Background(), check, connector); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "FROM (SELECT * FROM orders WHERE status <> 'draft') t\n\t\tLEFT JOIN customers r ON t.customer_id = r.id"
+	if !strings.Contains(connector.queries[0], want) {
+		t.Errorf("expected the filter inside the joined subquery, got %s", connector.queries[0])
+	}
+}
+
+func TestRunRowCountCheck_Filtered(t *testing.T) {
+	connector := &fakeConnector{rows: []map[string]interface{}{{"row_count": int64(3)}}}
+	check := &Check{
+		Type:       TypeRowCount,
+		Table:      "customers",
+		Where:      "region = 'EU'",
+		Parameters: CheckParameters{MinRows: 5},
+	}
+	m := NewManager(datasource.NewManager())
+
+	result, err := m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(connector.queries) != 1 || !strings.Contains(connector.queries[0], "SELECT COUNT(*) as row_count FROM (SELECT * FROM customers WHERE region = 'EU')") {
+		t.Fatalf("expected a filtered count query, got %v", connector.queries)
+	}
+	if result.Status != StatusFailed || result.ActualValue != int64(3) {
+		t.Errorf("expected 3 filtered rows to fail, got %s with %v", result.Status, result.ActualValue)
+	}
+}
+
+func TestCreateCheck_ValidatesFilters(t *testing.T) {
+	testCases := []struct {
+		name  string
+		check *Check
+	}{
+		{
+			"unknown operator",
+			&Check{Type: TypeNullCheck, Table: "t", Column: "c", Filters: []view.FilterDef{{Column: "status", Operator: "equals", Value: "x"}}},
+		},
+		{
+			"in without values",
+			&Check{Type: TypeNullCheck, Table: "t", Column: "c", Filters: []view.FilterDef{{Column: "status", Operator: "in"}}},
+		},
+		{
+			"statement terminator",
+			&Check{Type: TypeNullCheck, Table: "t", Column: "c", Where: "1 = 1; DROP TABLE t"},
+		},
+		{
+			"unbalanced parenthesis",
+			&Check{Type: TypeNullCheck, Table: "t", Column: "c", Where: "1 = 1) OR (1 = 1"},
+		},
+		{
+			"comment",
+			&Check{Type: TypeNullCheck, Table: "t", Column: "c", Where: "status = 'a' -- and more"},
+		},
+		{
+			"unsupported type",
+			&Check{Type: TypeCustomSQL, Table: "t", Where: "status = 'a'"},
+		},
+	}
+
+	m := NewManager(datasource.NewManager())
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := m.CreateCheck(context.Background(), tc.check); err == nil {
+				t.Error("expected a validation error")
+			}
+		})
+	}
+
+	valid := &Check{Type: TypeNullCheck, Table: "t", Column: "c", Where: "note <> 'a;b' AND (x > 1)"}
+	if err := m.CreateCheck(context.Background(), valid); err != nil {
+		t.Errorf("unexpected error for a valid predicate: %v", err)
+	}
+}
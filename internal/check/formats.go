@@ -0,0 +1,32 @@
+package check
+
+// Formats maps the named formats accepted by format checks to regular
+// expressions. The patterns avoid backslash escapes and lookarounds so they
+// behave the same across database regex engines.
+var Formats = map[string]string{
+	"email":        `^[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+[.][A-Za-z]{2,}$`,
+	"uuid":         `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`,
+	"iso_date":     `^[0-9]{4}-(0[1-9]|1[0-2])-(0[1-9]|[12][0-9]|3[01])$`,
+	"iso_datetime": `^[0-9]{4}-(0[1-9]|1[0-2])-(0[1-9]|[12][0-9]|3[01])[T ]([01][0-9]|2[0-3]):[0-5][0-9](:[0-5][0-9]([.][0-9]+)?)?(Z|[+-][0-9]{2}:?[0-9]{2})?$`,
+	"phone":        `^[+]?[1-9][0-9]{7,14}$`, // E.164, without separators
+	"iban":         `^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`,
+	"ipv4":         `^` + ipv4Pattern + `$`,
+	"ipv6":         `^` + ipv6Pattern + `$`,
+	"ip":           `^(` + ipv4Pattern + `|` + ipv6Pattern + `)$`,
+}
+
+const (
+	ipv4Octet   = `(25[0-5]|2[0-4][0-9]|1[0-9]{2}|[1-9]?[0-9])`
+	ipv4Pattern = `(` + ipv4Octet + `[.]){3}` + ipv4Octet
+	ipv6Group   = `[0-9a-fA-F]{1,4}`
+	// Eight groups, or fewer around a single :: standing for the rest
+	ipv6Pattern = `((` + ipv6Group + `:){7}` + ipv6Group +
+		`|(` + ipv6Group + `:){1,7}:` +
+		`|(` + ipv6Group + `:){1,6}:` + ipv6Group +
+		`|(` + ipv6Group + `:){1,5}(:` + ipv6Group + `){1,2}` +
+		`|(` + ipv6Group + `:){1,4}(:` + ipv6Group + `){1,3}` +
+		`|(` + ipv6Group + `:){1,3}(:` + ipv6Group + `){1,4}` +
+		`|(` + ipv6Group + `:){1,2}(:` + ipv6Group + `){1,5}` +
+		`|` + ipv6Group + `:(:` + ipv6Group + `){1,6}` +
+		`|:((:` + ipv6Group + `){1,7}|:))`
+)
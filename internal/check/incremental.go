@@ -0,0 +1,263 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// IncrementalConfig makes a check evaluate only the rows added since its last
+// successful run, tracked by a timestamp or monotonically increasing ID column
+type IncrementalConfig struct {
+	WatermarkColumn string  `json:"watermark_column"`
+	LookbackHours   float64 `json:"lookback_hours,omitempty"` // Timestamp watermarks: also re-evaluate rows this far behind the watermark
+	LookbackIDs     int64   `json:"lookback_ids,omitempty"`   // ID watermarks: also re-evaluate this many IDs behind the watermark
+}
+
+// WatermarkKind is the type of a watermark column
+type WatermarkKind string
+
+const (
+	WatermarkTimestamp WatermarkKind = "timestamp"
+	WatermarkID        WatermarkKind = "id"
+)
+
+// Watermark is the highest watermark column value a check run evaluated
+type Watermark struct {
+	Column    string        `json:"column"`
+	Kind      WatermarkKind `json:"kind"`
+	Timestamp time.Time     `json:"timestamp,omitempty"`
+	ID        int64         `json:"id,omitempty"`
+}
+
+// literal renders the watermark as a SQL literal
+func (w *Watermark) literal(dialect datasource.Dialect) string {
+	if w.Kind == WatermarkTimestamp {
+		return dialect.TimestampLiteral(w.Timestamp)
+	}
+	return strconv.FormatInt(w.ID, 10)
+}
+
+// lookback returns the lower bound of a run's window: the watermark moved
+// back by the configured overlap for late-arriving rows
+func (w *Watermark) lookback(config *IncrementalConfig) *Watermark {
+	lower := *w
+	if w.Kind == WatermarkTimestamp {
+		lower.Timestamp = w.Timestamp.Add(-time.Duration(config.LookbackHours * float64(time.Hour)))
+	} else {
+		lower.ID = w.ID - config.LookbackIDs
+	}
+	return &lower
+}
+
+// supportsIncremental reports whether a check type computes its metric from
+// the rows of its table, so that the rows can be limited to a window
+func supportsIncremental(checkType Type) bool {
+	switch checkType {
+	case TypeRowCount, TypeVolume, TypeNullCheck, TypeUniqueness, TypeMinValue, TypeMaxValue, TypeMeanValue, TypeSumValue,
+		TypeStdDev, TypeRegex, TypeFormat, TypeRange, TypeSetMembership, TypeReferentialIntegrity, TypeDistribution, TypeExpression:
+		return true
+	default:
+		return false
+	}
+}
+
+// incrementalWindow is the range of watermark values a check run evaluates
+type incrementalWindow struct {
+	column string
+	lower  *Watermark // Exclusive; nil on the first run
+	upper  *Watermark // Inclusive; nil when the table has no watermarked rows
+	rows   int64
+	// predicate selects the window's rows from the unaliased table
+	predicate string
+}
+
+type windowKey struct{}
+
+// withWindow returns a context limiting tableRef to an incremental window
+func withWindow(ctx context.Context, window *incrementalWindow) context.Context {
+	return context.WithValue(ctx, windowKey{}, window)
+}
+
+// windowFrom returns the incremental window of a check run, if any
+func windowFrom(ctx context.Context) *incrementalWindow {
+	window, _ := ctx.Value(windowKey{}).(*incrementalWindow)
+	return window
+}
+
+// executeIncremental runs a check over the rows past its last watermark, less
+// the lookback, up to the highest watermark present when the run starts. Rows
+// arriving during the run are left for the next one. When the window is empty
+// only row counts are evaluated; other checks are skipped.
+func (m *Manager) executeIncremental(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
+	config := check.Parameters.Incremental
+	if config.WatermarkColumn == "" {
+		return nil, fmt.Errorf("watermark column not specified for incremental check")
+	}
+	if !supportsIncremental(check.Type) {
+		return nil, fmt.Errorf("incremental mode is not supported for %s checks", check.Type)
+	}
+
+	previous := m.lastWatermark(check.ID, config.WatermarkColumn)
+	window, err := openWindow(ctx, check, connector, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	// An empty window keeps the previous watermark
+	watermark := window.upper
+	if watermark == nil || window.rows == 0 {
+		watermark = previous
+	}
+
+	details := map[string]interface{}{
+		"watermark_column": config.WatermarkColumn,
+		"rows":             window.rows,
+	}
+	if window.lower != nil {
+		details["from"] = window.lower
+	}
+	if window.upper != nil {
+		details["to"] = window.upper
+	}
+
+	if window.rows == 0 && check.Type != TypeRowCount && check.Type != TypeVolume {
+		return &CheckResult{
+			Status:    StatusSkipped,
+			Message:   fmt.Sprintf("no rows past the %s watermark", config.WatermarkColumn),
+			Details:   map[string]interface{}{"incremental": details},
+			Watermark: watermark,
+		}, nil
+	}
+
+	result, err := m.runCheckType(withWindow(ctx, window), check, connector)
+	if err != nil {
+		return nil, err
+	}
+	if result.Details == nil {
+		result.Details = make(map[string]interface{})
+	}
+	result.Details["incremental"] = details
+	result.Watermark = watermark
+	return result, nil
+}
+
+// lastWatermark returns the watermark of the check's latest run that
+// completed, ignoring runs against a different watermark column
+func (m *Manager) lastWatermark(checkID, column string) *Watermark {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	results := m.results[checkID]
+	for i := len(results) - 1; i >= 0; i-- {
+		r := results[i]
+		if r.Status == StatusError || r.Watermark == nil {
+			continue
+		}
+		if r.Watermark.Column != column {
+			return nil
+		}
+		return r.Watermark
+	}
+	return nil
+}
+
+// openWindow reads the highest watermark and the number of rows past the
+// lower bound, and builds the predicate selecting them
+func openWindow(ctx context.Context, check *Check, connector datasource.Connector, previous *Watermark) (*incrementalWindow, error) {
+	config := check.Parameters.Incremental
+	column := config.WatermarkColumn
+	dialect := datasource.DialectFor(connector.Type())
+
+	window := &incrementalWindow{column: column}
+	var lowerPredicate string
+	if previous != nil {
+		window.lower = previous.lookback(config)
+		lowerPredicate = fmt.Sprintf("%s > %s", column, window.lower.literal(dialect))
+	}
+
+	query := fmt.Sprintf("SELECT MAX(%s) as watermark, COUNT(%s) as row_count FROM %s", column, column, check.Table)
+	if lowerPredicate != "" {
+		query += " WHERE " + lowerPredicate
+	}
+
+	queryResult, err := connector.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watermark: %w", err)
+	}
+	if len(queryResult.Rows) == 0 {
+		return nil, fmt.Errorf("watermark query returned no results")
+	}
+
+	row := queryResult.Rows[0]
+	window.rows = toInt64(row["row_count"])
+	if row["watermark"] != nil {
+		window.upper, err = watermarkValue(column, row["watermark"])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if previous != nil && window.upper != nil && window.upper.Kind != previous.Kind {
+		return nil, fmt.Errorf("watermark column %s changed from %s to %s values", column, previous.Kind, window.upper.Kind)
+	}
+
+	switch {
+	case window.upper == nil:
+		window.predicate = "1 = 0"
+	case lowerPredicate == "":
+		window.predicate = fmt.Sprintf("%s <= %s", column, window.upper.literal(dialect))
+	default:
+		window.predicate = fmt.Sprintf("%s AND %s <= %s", lowerPredicate, column, window.upper.literal(dialect))
+	}
+	return window, nil
+}
+
+// watermarkValue converts the maximum of a watermark column to a watermark
+func watermarkValue(column string, v interface{}) (*Watermark, error) {
+	switch val := v.(type) {
+	case time.Time:
+		return &Watermark{Column: column, Kind: WatermarkTimestamp, Timestamp: val}, nil
+	case int64, int, int32:
+		return &Watermark{Column: column, Kind: WatermarkID, ID: toInt64(val)}, nil
+	case float64, float32:
+		f := toFloat64(val)
+		if f != float64(int64(f)) {
+			return nil, fmt.Errorf("watermark column %s must hold timestamps or integer IDs, got %v", column, v)
+		}
+		return &Watermark{Column: column, Kind: WatermarkID, ID: int64(f)}, nil
+	case []byte, string:
+		text := fmt.Sprintf("%s", val)
+		if id, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return &Watermark{Column: column, Kind: WatermarkID, ID: id}, nil
+		}
+		return nil, fmt.Errorf("watermark column %s must hold timestamps or integer IDs, got %q", column, text)
+	default:
+		return nil, fmt.Errorf("watermark column %s must hold timestamps or integer IDs, got %T", column, v)
+	}
+}
+
+// rowCount returns the number of rows a check run evaluates: the table's, or
+// the incremental window's, less the rows the check's filter excludes
+func rowCount(ctx context.Context, check *Check, connector datasource.Connector) (int64, error) {
+	if check.rowFilter() != "" {
+		from, err := tableRef(ctx, check, connector, "")
+		if err != nil {
+			return 0, err
+		}
+		queryResult, err := connector.Query(ctx, fmt.Sprintf("SELECT COUNT(*) as row_count FROM %s", from))
+		if err != nil {
+			return 0, err
+		}
+		if len(queryResult.Rows) == 0 {
+			return 0, fmt.Errorf("row count query returned no results")
+		}
+		return toInt64(queryResult.Rows[0]["row_count"]), nil
+	}
+	if window := windowFrom(ctx); window != nil {
+		return window.rows, nil
+	}
+	return connector.GetRowCount(ctx, check.Table)
+}
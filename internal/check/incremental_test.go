@@ -0,0 +1,109 @@
+package check
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+func TestExecuteIncremental_IDWatermark(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	check := &Check{
+		ID:         "inc",
+		Type:       TypeNullCheck,
+		Table:      "events",
+		Column:     "user_id",
+		Parameters: CheckParameters{Incremental: &IncrementalConfig{WatermarkColumn: "event_id", LookbackIDs: 100}},
+	}
+	connector := &scriptedConnector{responses: map[string][]map[string]interface{}{
+		"as watermark": {{"watermark": int64(5000), "row_count": int64(1200)}},
+		"null_count":   {{"total_count": int64(1200), "null_count": int64(0)}},
+	}}
+
+	// The first run evaluates everything up to the current watermark
+	result, err := m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Watermark == nil || result.Watermark.Kind != WatermarkID || result.Watermark.ID != 5000 {
+		t.Fatalf("expected id watermark 5000, got %+v", result.Watermark)
+	}
+	if !strings.Contains(connector.queries[1], "(SELECT * FROM events WHERE event_id <= 5000) _incremental") {
+		t.Errorf("expected first run to be bounded by the watermark, got %s", connector.queries[1])
+	}
+	m.results["inc"] = append(m.results["inc"], result)
+
+	// The next run starts past the stored watermark, less the lookback
+	connector.queries = nil
+	connector.responses["as watermark"] = []map[string]interface{}{{"watermark": int64(6000), "row_count": int64(1100)}}
+	result, err = m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(connector.queries[0], "WHERE event_id > 4900") {
+		t.Errorf("expected watermark query past 4900, got %s", connector.queries[0])
+	}
+	if !strings.Contains(connector.queries[1], "event_id > 4900 AND event_id <= 6000") {
+		t.Errorf("expected window 4900-6000, got %s", connector.queries[1])
+	}
+	if result.Watermark.ID != 6000 {
+		t.Errorf("expected watermark 6000, got %+v", result.Watermark)
+	}
+}
+
+func TestExecuteIncremental_TimestampWatermark(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	last := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	m.results["inc"] = []*CheckResult{{
+		Status:    StatusPassed,
+		Watermark: &Watermark{Column: "loaded_at", Kind: WatermarkTimestamp, Timestamp: last},
+	}}
+	check := &Check{
+		ID:         "inc",
+		Type:       TypeRowCount,
+		Table:      "events",
+		Parameters: CheckParameters{MinRows: 1, Incremental: &IncrementalConfig{WatermarkColumn: "loaded_at", LookbackHours: 2}},
+	}
+	connector := &scriptedConnector{responses: map[string][]map[string]interface{}{
+		"as watermark": {{"watermark": nil, "row_count": int64(0)}},
+	}}
+
+	result, err := m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(connector.queries[0], "loaded_at > TIMESTAMP '2024-05-01 10:00:00.000000'") {
+		t.Errorf("expected a two hour lookback, got %s", connector.queries[0])
+	}
+	// Row counts are still evaluated on an empty window, and the watermark carries over
+	if result.Status != StatusFailed || result.ActualValue != int64(0) {
+		t.Errorf("expected an empty increment to fail min_rows, got %s: %v", result.Status, result.ActualValue)
+	}
+	if result.Watermark == nil || !result.Watermark.Timestamp.Equal(last) {
+		t.Errorf("expected the previous watermark to carry over, got %+v", result.Watermark)
+	}
+
+	check.Type = TypeNullCheck
+	check.Column = "user_id"
+	result, err = m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusSkipped {
+		t.Errorf("expected other checks to skip an empty increment, got %s", result.Status)
+	}
+}
+
+func TestExecuteIncremental_Unsupported(t *testing.T) {
+	check := &Check{
+		Type:       TypeFreshness,
+		Table:      "events",
+		Parameters: CheckParameters{TimestampColumn: "loaded_at", Incremental: &IncrementalConfig{WatermarkColumn: "loaded_at"}},
+	}
+	if _, err := NewManager(datasource.NewManager()).executeCheck(context.Background(), check, &fakeConnector{}); err == nil {
+		t.Error("expected error for a freshness check in incremental mode")
+	}
+}
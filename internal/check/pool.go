@@ -0,0 +1,200 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConcurrencyLimits bounds how many checks run at once
+type ConcurrencyLimits struct {
+	MaxConcurrent    int           `json:"max_concurrent"`          // Checks running at once across all datasources
+	MaxPerDatasource int           `json:"max_per_datasource"`      // Checks running at once against one datasource
+	CheckTimeout     time.Duration `json:"check_timeout,omitempty"` // Deadline of a check run, 0 for none; Check.TimeoutSeconds overrides it
+}
+
+// DefaultConcurrencyLimits are the limits of a new Manager
+var DefaultConcurrencyLimits = ConcurrencyLimits{
+	MaxConcurrent:    8,
+	MaxPerDatasource: 4,
+}
+
+// workerPool hands out run slots: one global and one for each datasource a
+// run queries. Slots are taken datasources first, in ID order, so a run
+// waiting on a busy datasource never holds a global slot and runs across
+// several datasources cannot deadlock.
+type workerPool struct {
+	limits ConcurrencyLimits
+	global chan struct{}
+
+	mu          sync.Mutex
+	datasources map[string]chan struct{}
+}
+
+func newWorkerPool(limits ConcurrencyLimits) *workerPool {
+	if limits.MaxConcurrent <= 0 {
+		limits.MaxConcurrent = DefaultConcurrencyLimits.MaxConcurrent
+	}
+	if limits.MaxPerDatasource <= 0 {
+		limits.MaxPerDatasource = DefaultConcurrencyLimits.MaxPerDatasource
+	}
+	return &workerPool{
+		limits:      limits,
+		global:      make(chan struct{}, limits.MaxConcurrent),
+		datasources: make(map[string]chan struct{}),
+	}
+}
+
+// acquire waits for a run slot against datasources and returns the function
+// releasing it. It fails when ctx is done first.
+func (p *workerPool) acquire(ctx context.Context, datasourceIDs ...string) (func(), error) {
+	ids := append([]string(nil), datasourceIDs...)
+	sort.Strings(ids)
+
+	var held []chan struct{}
+	releaseHeld := func() {
+		for _, slots := range held {
+			<-slots
+		}
+	}
+	for i, id := range ids {
+		if i > 0 && id == ids[i-1] {
+			continue
+		}
+		p.mu.Lock()
+		slots, ok := p.datasources[id]
+		if !ok {
+			slots = make(chan struct{}, p.limits.MaxPerDatasource)
+			p.datasources[id] = slots
+		}
+		p.mu.Unlock()
+
+		select {
+		case slots <- struct{}{}:
+			held = append(held, slots)
+		case <-ctx.Done():
+			releaseHeld()
+			return nil, fmt.Errorf("waiting for a run slot: %w", ctx.Err())
+		}
+	}
+	select {
+	case p.global <- struct{}{}:
+	case <-ctx.Done():
+		releaseHeld()
+		return nil, fmt.Errorf("waiting for a run slot: %w", ctx.Err())
+	}
+
+	return func() {
+		<-p.global
+		releaseHeld()
+	}, nil
+}
+
+// timeout returns the deadline of a check's runs
+func (p *workerPool) timeout(check *Check) time.Duration {
+	if check.TimeoutSeconds > 0 {
+		return time.Duration(check.TimeoutSeconds) * time.Second
+	}
+	return p.limits.CheckTimeout
+}
+
+// SetConcurrencyLimits replaces the limits of check runs. Runs already
+// holding a slot finish under the previous limits.
+func (m *Manager) SetConcurrencyLimits(limits ConcurrencyLimits) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pool = newWorkerPool(limits)
+}
+
+// GetConcurrencyLimits returns the limits of check runs
+func (m *Manager) GetConcurrencyLimits() ConcurrencyLimits {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.pool.limits
+}
+
+// currentPool returns the pool of new runs
+func (m *Manager) currentPool() *workerPool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.pool
+}
+
+// startRun takes a run slot for a check and applies its timeout to ctx. The
+// returned function releases both.
+func (m *Manager) startRun(ctx context.Context, check *Check) (context.Context, func(), error) {
+	pool := m.currentPool()
+	release, err := pool.acquire(ctx, check.DatasourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	timeout := pool.timeout(check)
+	if timeout <= 0 {
+		return ctx, release, nil
+	}
+	ctx, cancel := context.WithTimeoutCause(ctx, timeout, fmt.Errorf("check timed out after %s", timeout))
+	return ctx, func() {
+		cancel()
+		release()
+	}, nil
+}
+
+// AcquireRunSlot waits for a run slot shared with check runs against the
+// datasources and returns the function releasing it. Table diffs take their
+// slots here so that they count toward the concurrency limits.
+func (m *Manager) AcquireRunSlot(ctx context.Context, datasourceIDs ...string) (func(), error) {
+	return m.currentPool().acquire(ctx, datasourceIDs...)
+}
+
+// timeoutError names the run timeout when that is why a run failed
+func timeoutError(ctx context.Context, err error) error {
+	if err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	if cause := context.Cause(ctx); cause != nil && cause != ctx.Err() {
+		return fmt.Errorf("%v: %w", cause, err)
+	}
+	return err
+}
+
+// RunChecks runs checks in parallel within the concurrency limits and returns
+// their results in the order of ids. Checks run after the checks they depend
+// on, and are skipped when one of those does not pass. A check that cannot be
+// started, e.g. because it does not exist, gets an error result instead of
+// stopping the others.
+func (m *Manager) RunChecks(ctx context.Context, ids []string) []*CheckResult {
+	run := newCheckRun(ids)
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			m.runInOrder(ctx, run, id)
+		}(id)
+	}
+	wg.Wait()
+
+	results := make([]*CheckResult, len(ids))
+	for i, id := range ids {
+		results[i] = run.results[id]
+	}
+	return results
+}
+
+// errorResult reports a check run that failed before its check could execute
+func errorResult(checkID string, err error) *CheckResult {
+	return &CheckResult{
+		ID:        uuid.New().String(),
+		CheckID:   checkID,
+		Status:    StatusError,
+		Message:   fmt.Sprintf("check execution failed: %v", err),
+		Details:   make(map[string]interface{}),
+		Error:     err.Error(),
+		Timestamp: time.Now(),
+	}
+}
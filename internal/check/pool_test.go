@@ -0,0 +1,127 @@
+package check
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// blockingConnector answers no query until the query's context is done
+type blockingConnector struct {
+	fakeConnector
+}
+
+func (c *blockingConnector) Query(ctx context.Context, query string, args ...interface{}) (*datasource.QueryResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestWorkerPool_Limits(t *testing.T) {
+	pool := newWorkerPool(ConcurrencyLimits{MaxConcurrent: 2, MaxPerDatasource: 1})
+
+	release, err := pool.acquire(context.Background(), "ds-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// ds-1 is at its limit, ds-2 is not
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := pool.acquire(ctx, "ds-1"); err == nil {
+		t.Fatal("expected the per-datasource limit to block a second run")
+	}
+	releaseOther, err := pool.acquire(context.Background(), "ds-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Both global slots are taken
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := pool.acquire(ctx, "ds-3"); err == nil {
+		t.Fatal("expected the global limit to block a third run")
+	}
+
+	release()
+	releaseOther()
+	if release, err = pool.acquire(context.Background(), "ds-1"); err != nil {
+		t.Fatalf("expected a released slot to be reusable, got %v", err)
+	}
+	release()
+}
+
+func TestWorkerPool_SeveralDatasources(t *testing.T) {
+	pool := newWorkerPool(ConcurrencyLimits{MaxConcurrent: 2, MaxPerDatasource: 1})
+
+	// A diff of a datasource against itself takes one slot
+	release, err := pool.acquire(context.Background(), "ds-1", "ds-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release()
+
+	release, err = pool.acquire(context.Background(), "ds-2", "ds-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, id := range []string{"ds-1", "ds-2"} {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		if _, err := pool.acquire(ctx, id); err == nil {
+			t.Errorf("expected %s to be at its limit", id)
+		}
+		cancel()
+	}
+	if len(pool.global) != 1 {
+		t.Errorf("expected one global slot taken, got %d", len(pool.global))
+	}
+
+	release()
+	if release, err = pool.acquire(context.Background(), "ds-1"); err != nil {
+		t.Fatalf("expected released slots to be reusable, got %v", err)
+	}
+	release()
+}
+
+func TestStartRun_Timeout(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	m.SetConcurrencyLimits(ConcurrencyLimits{CheckTimeout: 10 * time.Millisecond})
+	check := &Check{Type: TypeNullCheck, Table: "orders", Column: "email"}
+
+	ctx, done, err := m.startRun(context.Background(), check)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer done()
+
+	_, err = m.executeCheck(ctx, check, &blockingConnector{})
+	err = timeoutError(ctx, err)
+	if err == nil || !strings.Contains(err.Error(), "check timed out after 10ms") {
+		t.Errorf("expected a timeout error, got %v", err)
+	}
+}
+
+func TestRunChecks_ContinuesPastFailures(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	inactive := &Check{ID: "inactive", Type: TypeRowCount, Table: "orders"}
+	if err := m.CreateCheck(context.Background(), inactive); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := m.UpdateCheck(context.Background(), "inactive", map[string]interface{}{"active": false}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results := m.RunChecks(context.Background(), []string{"missing", "inactive"})
+
+	if len(results) != 2 {
+		t.Fatalf("expected a result per check, got %d", len(results))
+	}
+	if results[0].CheckID != "missing" || results[0].Status != StatusError {
+		t.Errorf("expected an error result for the missing check, got %+v", results[0])
+	}
+	if results[1].CheckID != "inactive" || results[1].Status != StatusSkipped {
+		t.Errorf("expected the inactive check to be skipped, got %+v", results[1])
+	}
+}
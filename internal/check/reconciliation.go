@@ -0,0 +1,449 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+	"github.com/vinod901/opendq-go/internal/view"
+)
+
+const (
+	// defaultMaxReconciliationGroups caps the groups a grouped reconciliation
+	// compares per side
+	defaultMaxReconciliationGroups = 1000
+	// maxReconciliationMismatches caps the mismatches listed in a result
+	maxReconciliationMismatches = 50
+	// mismatchesInMessage is the number of mismatches named in a result message
+	mismatchesInMessage = 3
+)
+
+// reconciliationFunctions are the aggregates a reconciliation can compare
+var reconciliationFunctions = []string{"sum", "min", "max"}
+
+// ReconciliationConfig describes the target a reconciliation check compares
+// the check's table to, and the metrics it compares
+type ReconciliationConfig struct {
+	TargetDatasourceID string                 `json:"target_datasource_id,omitempty"` // Defaults to the check's datasource
+	TargetTable        string                 `json:"target_table"`
+	Columns            []ReconciliationColumn `json:"columns,omitempty"`             // Aggregated columns; row counts are always compared
+	GroupBy            []string               `json:"group_by,omitempty"`            // Also compare the metrics per group of these columns
+	MaxGroups          int                    `json:"max_groups,omitempty"`          // Groups compared per side, default 1000
+	RowCountTolerance  float64                `json:"row_count_tolerance,omitempty"` // Percent
+	Tolerance          float64                `json:"tolerance,omitempty"`           // Percent, for aggregates without their own
+
+	// Rows of the target compared. When neither is set the target is read
+	// with the check's own filters, whose columns must then exist there too.
+	TargetFilters []view.FilterDef `json:"target_filters,omitempty"`
+	TargetWhere   string           `json:"target_where,omitempty"` // Raw SQL predicate, combined with TargetFilters by AND
+}
+
+// hasTargetFilter reports whether the target side has its own row filter
+func (c *ReconciliationConfig) hasTargetFilter() bool {
+	return len(c.TargetFilters) > 0 || c.TargetWhere != ""
+}
+
+// ReconciliationColumn is a column whose aggregates must match on both sides
+type ReconciliationColumn struct {
+	Column       string   `json:"column"`
+	TargetColumn string   `json:"target_column,omitempty"` // Defaults to Column
+	Functions    []string `json:"functions,omitempty"`     // sum, min, max; all three by default
+	Tolerance    *float64 `json:"tolerance,omitempty"`     // Percent, overrides the config's
+}
+
+// ReconciliationMismatch is a metric that differs between source and target
+type ReconciliationMismatch struct {
+	Group      string      `json:"group,omitempty"`
+	Metric     string      `json:"metric"` // row_count, or function(column)
+	Source     interface{} `json:"source"`
+	Target     interface{} `json:"target"`
+	Difference float64     `json:"difference_percent,omitempty"` // Relative difference of numeric metrics
+}
+
+// reconciliationMetric is a metric compared on both sides
+type reconciliationMetric struct {
+	name      string // row_count, or function(column)
+	source    string // Source aggregate expression
+	target    string // Target aggregate expression
+	alias     string
+	tolerance float64
+}
+
+// runReconciliationCheck compares the check's table to a target table, which
+// may live in another datasource, on row count and column aggregates, in
+// total and optionally per group
+func (m *Manager) runReconciliationCheck(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
+	config := check.Parameters.Reconciliation
+	if config == nil || config.TargetTable == "" {
+		return nil, fmt.Errorf("reconciliation target table not specified")
+	}
+	metrics, err := reconciliationMetrics(config)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := m.targetConnector(ctx, check, connector, config.TargetDatasourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceFrom, err := tableRef(ctx, check, connector, "")
+	if err != nil {
+		return nil, err
+	}
+	targetFrom, err := tableRef(ctx, reconciliationTarget(check), target, "")
+	if err != nil {
+		return nil, err
+	}
+
+	sourceDialect := datasource.DialectFor(connector.Type())
+	targetDialect := datasource.DialectFor(target.Type())
+	sourceTotals, err := queryReconciliation(ctx, connector, reconciliationQuery(sourceDialect, sourceFrom, metrics, nil, 0, true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query source: %w", err)
+	}
+	targetTotals, err := queryReconciliation(ctx, target, reconciliationQuery(targetDialect, targetFrom, metrics, nil, 0, false))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query target: %w", err)
+	}
+	if len(sourceTotals) == 0 || len(targetTotals) == 0 {
+		return nil, fmt.Errorf("reconciliation query returned no results")
+	}
+
+	var mismatches []ReconciliationMismatch
+	compared := compareMetrics("", metrics, sourceTotals[0], targetTotals[0], &mismatches)
+
+	details := map[string]interface{}{
+		"source_table":     check.Table,
+		"target_table":     config.TargetTable,
+		"source_row_count": toInt64(sourceTotals[0]["row_count"]),
+		"target_row_count": toInt64(targetTotals[0]["row_count"]),
+	}
+	if config.TargetDatasourceID != "" {
+		details["target_datasource_id"] = config.TargetDatasourceID
+	}
+
+	if len(config.GroupBy) > 0 {
+		maxGroups := config.MaxGroups
+		if maxGroups <= 0 {
+			maxGroups = defaultMaxReconciliationGroups
+		}
+		sourceGroups, err := queryReconciliation(ctx, connector, reconciliationQuery(sourceDialect, sourceFrom, metrics, config.GroupBy, maxGroups, true))
+		if err != nil {
+			return nil, fmt.Errorf("failed to query source groups: %w", err)
+		}
+		targetGroups, err := queryReconciliation(ctx, target, reconciliationQuery(targetDialect, targetFrom, metrics, config.GroupBy, maxGroups, false))
+		if err != nil {
+			return nil, fmt.Errorf("failed to query target groups: %w", err)
+		}
+
+		groups, groupMetrics, truncated := compareGroups(config.GroupBy, metrics, sourceGroups, targetGroups, maxGroups, &mismatches)
+		compared += groupMetrics
+		details["group_by"] = config.GroupBy
+		details["groups_compared"] = groups
+		if truncated {
+			details["groups_truncated"] = true
+			details["max_groups"] = maxGroups
+		}
+	}
+
+	details["metrics_compared"] = compared
+	details["mismatch_count"] = len(mismatches)
+	if len(mismatches) > maxReconciliationMismatches {
+		details["mismatches"] = mismatches[:maxReconciliationMismatches]
+	} else {
+		details["mismatches"] = mismatches
+	}
+
+	result := &CheckResult{
+		ActualValue:   len(mismatches),
+		ExpectedValue: 0,
+		Details:       details,
+	}
+	if len(mismatches) == 0 {
+		result.Status = StatusPassed
+		result.Message = fmt.Sprintf("%s and %s match on %d metrics", check.Table, config.TargetTable, compared)
+		return result, nil
+	}
+
+	named := make([]string, 0, mismatchesInMessage)
+	for _, mismatch := range mismatches {
+		if len(named) == mismatchesInMessage {
+			break
+		}
+		named = append(named, mismatch.String())
+	}
+	if len(mismatches) > mismatchesInMessage {
+		named = append(named, fmt.Sprintf("and %d more", len(mismatches)-mismatchesInMessage))
+	}
+	result.Status = StatusFailed
+	result.Message = fmt.Sprintf("%d of %d metrics differ between %s and %s: %s", len(mismatches), compared, check.Table, config.TargetTable, strings.Join(named, "; "))
+	return result, nil
+}
+
+// reconciliationTarget returns the check as read on the target side of a
+// reconciliation: the target table, with the target's row filter when one is
+// configured and the check's own otherwise
+func reconciliationTarget(check *Check) *Check {
+	config := check.Parameters.Reconciliation
+	target := *check
+	target.Table = config.TargetTable
+	if config.hasTargetFilter() {
+		target.Filters = config.TargetFilters
+		target.Where = config.TargetWhere
+	}
+	return &target
+}
+
+// DatasourceIDs returns the datasources a check reads: its own and, for
+// checks comparing two tables, the target's
+func (c *Check) DatasourceIDs() []string {
+	ids := []string{c.DatasourceID}
+	if config := c.Parameters.Reconciliation; config != nil && config.TargetDatasourceID != "" {
+		ids = append(ids, config.TargetDatasourceID)
+	}
+	if config := c.Parameters.TableDiff; config != nil && config.TargetDatasourceID != "" {
+		ids = append(ids, config.TargetDatasourceID)
+	}
+	return ids
+}
+
+// targetConnector returns the connector of the other side of a check
+// comparing two tables: the check's own when the target is in its datasource.
+// Another datasource's connector is held to the budgets of that datasource and
+// the check's tenant. When compiling, the target's queries are recorded
+// instead of executed.
+func (m *Manager) targetConnector(ctx context.Context, check *Check, connector datasource.Connector, targetDatasourceID string) (datasource.Connector, error) {
+	if targetDatasourceID == "" || targetDatasourceID == check.DatasourceID {
+		return connector, nil
+	}
+	target, err := m.datasourceManager.GetConnector(ctx, targetDatasourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target datasource connector: %w", err)
+	}
+	if recorder := recorderFrom(ctx); recorder != nil {
+		return recorder.wrap(targetDatasourceID, target), nil
+	}
+	return withBudget(target, m.budgetFor(ctx, targetDatasourceID, check.TenantID)), nil
+}
+
+// String renders a mismatch for a result message
+func (m ReconciliationMismatch) String() string {
+	metric := m.Metric
+	if m.Group != "" {
+		metric = fmt.Sprintf("%s [%s]", m.Metric, m.Group)
+	}
+	if m.Difference > 0 {
+		return fmt.Sprintf("%s %v vs %v (%.2f%%)", metric, m.Source, m.Target, m.Difference)
+	}
+	return fmt.Sprintf("%s %v vs %v", metric, m.Source, m.Target)
+}
+
+// reconciliationMetrics lists the metrics a reconciliation compares: the row
+// count, then each column's functions
+func reconciliationMetrics(config *ReconciliationConfig) ([]reconciliationMetric, error) {
+	metrics := []reconciliationMetric{{
+		name:      "row_count",
+		source:    "COUNT(*)",
+		target:    "COUNT(*)",
+		alias:     "row_count",
+		tolerance: config.RowCountTolerance,
+	}}
+	for i, col := range config.Columns {
+		if col.Column == "" {
+			return nil, fmt.Errorf("reconciliation column %d has no name", i)
+		}
+		targetColumn := col.TargetColumn
+		if targetColumn == "" {
+			targetColumn = col.Column
+		}
+		tolerance := config.Tolerance
+		if col.Tolerance != nil {
+			tolerance = *col.Tolerance
+		}
+		functions := col.Functions
+		if len(functions) == 0 {
+			functions = reconciliationFunctions
+		}
+		for _, function := range functions {
+			function = strings.ToLower(function)
+			if !containsString(reconciliationFunctions, function) {
+				return nil, fmt.Errorf("unsupported reconciliation function %q, expected one of %s", function, strings.Join(reconciliationFunctions, ", "))
+			}
+			sqlFunction := strings.ToUpper(function)
+			metrics = append(metrics, reconciliationMetric{
+				name:      fmt.Sprintf("%s(%s)", function, col.Column),
+				source:    fmt.Sprintf("%s(%s)", sqlFunction, col.Column),
+				target:    fmt.Sprintf("%s(%s)", sqlFunction, targetColumn),
+				alias:     fmt.Sprintf("%s_%d", function, i),
+				tolerance: tolerance,
+			})
+		}
+	}
+	return metrics, nil
+}
+
+// reconciliationQuery renders the query computing the metrics on one side,
+// grouped and capped at limit+1 groups when groupBy is set
+func reconciliationQuery(dialect datasource.Dialect, from string, metrics []reconciliationMetric, groupBy []string, limit int, source bool) string {
+	selects := make([]string, 0, len(groupBy)+len(metrics))
+	for i, col := range groupBy {
+		selects = append(selects, fmt.Sprintf("%s as g_%d", col, i))
+	}
+	for _, metric := range metrics {
+		expr := metric.target
+		if source {
+			expr = metric.source
+		}
+		selects = append(selects, fmt.Sprintf("%s as %s", expr, metric.alias))
+	}
+
+	query := fmt.Sprintf("SELECT %s\n\t\tFROM %s", strings.Join(selects, ", "), from)
+	if len(groupBy) > 0 {
+		columns := strings.Join(groupBy, ", ")
+		query += fmt.Sprintf("\n\t\tGROUP BY %s\n\t\tORDER BY %s\n\t\t%s", columns, columns, dialect.LimitClause(limit+1))
+	}
+	return query
+}
+
+// queryReconciliation runs one side's query and returns its rows
+func queryReconciliation(ctx context.Context, connector datasource.Connector, query string) ([]map[string]interface{}, error) {
+	result, err := connector.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return result.Rows, nil
+}
+
+// compareMetrics compares one row of each side, appending the metrics that
+// differ to mismatches, and returns the number of metrics compared
+func compareMetrics(group string, metrics []reconciliationMetric, source, target map[string]interface{}, mismatches *[]ReconciliationMismatch) int {
+	for _, metric := range metrics {
+		sourceValue := reconciliationValue(source[metric.alias])
+		targetValue := reconciliationValue(target[metric.alias])
+		if difference, ok := valuesMatch(sourceValue, targetValue, metric.tolerance); !ok {
+			*mismatches = append(*mismatches, ReconciliationMismatch{
+				Group:      group,
+				Metric:     metric.name,
+				Source:     sourceValue,
+				Target:     targetValue,
+				Difference: difference,
+			})
+		}
+	}
+	return len(metrics)
+}
+
+// compareGroups matches the groups of both sides by their key and compares
+// their metrics. A group missing on one side is a row count mismatch, unless
+// either side was truncated, in which case only the groups both sides
+// returned are compared. It returns the number of groups and of metrics
+// compared, and whether either side was truncated.
+func compareGroups(groupBy []string, metrics []reconciliationMetric, source, target []map[string]interface{}, maxGroups int, mismatches *[]ReconciliationMismatch) (int, int, bool) {
+	truncated := len(source) > maxGroups || len(target) > maxGroups
+	if len(source) > maxGroups {
+		source = source[:maxGroups]
+	}
+	if len(target) > maxGroups {
+		target = target[:maxGroups]
+	}
+
+	targetByKey := make(map[string]map[string]interface{}, len(target))
+	for _, row := range target {
+		targetByKey[groupLabel(groupBy, row)] = row
+	}
+
+	groups, compared := 0, 0
+	for _, row := range source {
+		label := groupLabel(groupBy, row)
+		targetRow, ok := targetByKey[label]
+		delete(targetByKey, label)
+		if !ok {
+			if !truncated {
+				*mismatches = append(*mismatches, ReconciliationMismatch{Group: label, Metric: "row_count", Source: reconciliationValue(row["row_count"]), Target: 0.0})
+				groups++
+				compared++
+			}
+			continue
+		}
+		compared += compareMetrics(label, metrics, row, targetRow, mismatches)
+		groups++
+	}
+	if !truncated {
+		for _, row := range target {
+			label := groupLabel(groupBy, row)
+			if _, ok := targetByKey[label]; ok {
+				*mismatches = append(*mismatches, ReconciliationMismatch{Group: label, Metric: "row_count", Source: 0.0, Target: reconciliationValue(row["row_count"])})
+				groups++
+				compared++
+			}
+		}
+	}
+	return groups, compared, truncated
+}
+
+// groupLabel renders the group of a grouped row as "col=value, ...", in the
+// order of groupBy
+func groupLabel(groupBy []string, row map[string]interface{}) string {
+	parts := make([]string, len(groupBy))
+	for i, col := range groupBy {
+		value := reconciliationValue(row[fmt.Sprintf("g_%d", i)])
+		if value == nil {
+			parts[i] = col + "=NULL"
+		} else {
+			parts[i] = fmt.Sprintf("%s=%v", col, value)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// reconciliationValue normalizes a value returned by either side, so that
+// values of different engines compare: integers and floats as float64,
+// numeric text as float64, times in UTC and bytes as text
+func reconciliationValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case int64, int, int32, float64, float32:
+		return toFloat64(val)
+	case time.Time:
+		return val.UTC().Format(time.RFC3339Nano)
+	case []byte, string:
+		if number, ok := parseMetric(val); ok {
+			return number
+		}
+		return fmt.Sprintf("%s", val)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// valuesMatch compares two normalized values. Numbers match when their
+// relative difference, in percent of the larger, is within tolerance; other
+// values must be equal. It returns the relative difference of numbers.
+func valuesMatch(source, target interface{}, tolerance float64) (float64, bool) {
+	sourceNumber, sourceOK := source.(float64)
+	targetNumber, targetOK := target.(float64)
+	if !sourceOK || !targetOK {
+		return 0, source == target
+	}
+	if sourceNumber == targetNumber {
+		return 0, true
+	}
+	difference := math.Abs(sourceNumber-targetNumber) / math.Max(math.Abs(sourceNumber), math.Abs(targetNumber)) * 100
+	// Allow for float rounding of sums computed by different engines
+	return difference, difference <= tolerance+1e-9
+}
+
+// containsString reports whether values contains s
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
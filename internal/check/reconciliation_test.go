@@ -0,0 +1,207 @@
+package check
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+	"github.com/vinod901/opendq-go/internal/view"
+)
+
+// sidesConnector answers reconciliation queries by side and by whether they
+// are grouped
+type sidesConnector struct {
+	fakeConnector
+	totals map[string]map[string]interface{}   // Table to its totals row
+	groups map[string][]map[string]interface{} // Table to its group rows
+}
+
+func (c *sidesConnector) Query(ctx context.Context, query string, args ...interface{}) (*datasource.QueryResult, error) {
+	c.queries = append(c.queries, query)
+	for table, row := range c.totals {
+		if !strings.Contains(query, "FROM "+table) {
+			continue
+		}
+		rows := []map[string]interface{}{row}
+		if strings.Contains(query, "GROUP BY") {
+			rows = c.groups[table]
+		}
+		return &datasource.QueryResult{Rows: rows, RowCount: int64(len(rows))}, nil
+	}
+	return &datasource.QueryResult{}, nil
+}
+
+func TestRunReconciliationCheck(t *testing.T) {
+	tolerance := 1.0
+	check := &Check{
+		Type:  TypeReconciliation,
+		Table: "src.orders",
+		Where: "created_at < CURRENT_DATE",
+		Parameters: CheckParameters{Reconciliation: &ReconciliationConfig{
+			TargetTable: "dst.orders",
+			Columns: []ReconciliationColumn{
+				{Column: "amount", TargetColumn: "amount_usd", Functions: []string{"sum"}, Tolerance: &tolerance},
+				{Column: "created_at", Functions: []string{"max"}},
+			},
+		}},
+	}
+	m := NewManager(datasource.NewManager())
+
+	testCases := []struct {
+		name       string
+		target     map[string]interface{}
+		status     Status
+		mismatches int
+	}{
+		{"match within tolerance", map[string]interface{}{"row_count": int64(100), "sum_0": []byte("1005.5"), "max_1": "2024-05-01"}, StatusPassed, 0},
+		{"count and sum differ", map[string]interface{}{"row_count": int64(98), "sum_0": 900.0, "max_1": "2024-05-01"}, StatusFailed, 2},
+		{"max differs", map[string]interface{}{"row_count": int64(100), "sum_0": 1000.0, "max_1": "2024-04-30"}, StatusFailed, 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			connector := &sidesConnector{totals: map[string]map[string]interface{}{
+				"src.orders": {"row_count": int64(100), "sum_0": 1000.0, "max_1": "2024-05-01"},
+				"dst.orders": tc.target,
+			}}
+
+			result, err := m.executeCheck(context.Background(), check, connector)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Status != tc.status || result.Details["mismatch_count"] != tc.mismatches {
+				t.Errorf("expected %s with %d mismatches, got %s: %s", tc.status, tc.mismatches, result.Status, result.Message)
+			}
+		})
+	}
+
+	connector := &sidesConnector{totals: map[string]map[string]interface{}{
+		"src.orders": {"row_count": int64(1)},
+		"dst.orders": {"row_count": int64(1)},
+	}}
+	if _, err := m.executeCheck(context.Background(), check, connector); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(connector.queries[0], "SUM(amount) as sum_0, MAX(created_at) as max_1") ||
+		!strings.Contains(connector.queries[1], "SUM(amount_usd) as sum_0") ||
+		!strings.Contains(connector.queries[1], "FROM (SELECT * FROM dst.orders WHERE created_at < CURRENT_DATE)") {
+		t.Errorf("expected per-side aggregates over filtered tables, got %v", connector.queries)
+	}
+}
+
+func TestRunReconciliationCheck_TargetFilter(t *testing.T) {
+	check := &Check{
+		Type:    TypeReconciliation,
+		Table:   "src.orders",
+		Filters: []view.FilterDef{{Column: "region", Operator: "eq", Value: "EU"}},
+		Parameters: CheckParameters{Reconciliation: &ReconciliationConfig{
+			TargetTable: "dst.orders",
+			TargetWhere: "market = 'EU'",
+		}},
+	}
+	connector := &sidesConnector{totals: map[string]map[string]interface{}{
+		"src.orders":                {"row_count": int64(10)},
+		"(SELECT * FROM dst.orders": {"row_count": int64(10)},
+	}}
+	m := NewManager(datasource.NewManager())
+
+	result, err := m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusPassed {
+		t.Errorf("expected the filtered sides to match, got %s: %s", result.Status, result.Message)
+	}
+	if !strings.Contains(connector.queries[0], "region") ||
+		!strings.Contains(connector.queries[1], "FROM (SELECT * FROM dst.orders WHERE market = 'EU')") || strings.Contains(connector.queries[1], "region") {
+		t.Errorf("expected each side read with its own filter, got %v", connector.queries)
+	}
+}
+
+func TestRunReconciliationCheck_Grouped(t *testing.T) {
+	check := &Check{
+		Type:  TypeReconciliation,
+		Table: "src.orders",
+		Parameters: CheckParameters{Reconciliation: &ReconciliationConfig{
+			TargetTable: "dst.orders",
+			GroupBy:     []string{"country"},
+		}},
+	}
+	connector := &sidesConnector{
+		totals: map[string]map[string]interface{}{
+			"src.orders": {"row_count": int64(30)},
+			"dst.orders": {"row_count": int64(30)},
+		},
+		groups: map[string][]map[string]interface{}{
+			"src.orders": {
+				{"g_0": "DE", "row_count": int64(10)},
+				{"g_0": "FR", "row_count": int64(10)},
+				{"g_0": "US", "row_count": int64(10)},
+			},
+			"dst.orders": {
+				{"g_0": "DE", "row_count": int64(10)},
+				{"g_0": "FR", "row_count": int64(12)},
+				{"g_0": "UK", "row_count": int64(8)},
+			},
+		},
+	}
+	m := NewManager(datasource.NewManager())
+
+	result, err := m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Status != StatusFailed || result.ActualValue != 3 {
+		t.Fatalf("expected FR to differ and US and UK to be missing, got %s: %s", result.Status, result.Message)
+	}
+	mismatches := result.Details["mismatches"].([]ReconciliationMismatch)
+	var groups []string
+	for _, mismatch := range mismatches {
+		groups = append(groups, mismatch.Group)
+	}
+	if strings.Join(groups, "; ") != "country=FR; country=US; country=UK" {
+		t.Errorf("unexpected mismatching groups: %v", groups)
+	}
+	if !strings.Contains(connector.queries[2], "GROUP BY country") || !strings.Contains(connector.queries[2], "LIMIT 1001") {
+		t.Errorf("expected a capped grouped query, got %s", connector.queries[2])
+	}
+}
+
+func TestRunReconciliationCheck_InvalidConfig(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	for _, config := range []*ReconciliationConfig{
+		nil,
+		{TargetTable: "dst.orders", Columns: []ReconciliationColumn{{Column: "amount", Functions: []string{"avg"}}}},
+	} {
+		check := &Check{Type: TypeReconciliation, Table: "src.orders", Parameters: CheckParameters{Reconciliation: config}}
+		if _, err := m.executeCheck(context.Background(), check, &fakeConnector{}); err == nil {
+			t.Errorf("expected an error for %+v", config)
+		}
+	}
+}
+
+func TestReconciliationQuery_Dialect(t *testing.T) {
+	metrics := []reconciliationMetric{{source: "COUNT(*)", target: "COUNT(*)", alias: "row_count"}}
+	query := reconciliationQuery(datasource.DialectFor(datasource.TypeSQLServer), "orders", metrics, []string{"country"}, 100, true)
+	if !strings.HasSuffix(query, "ORDER BY country\n\t\tOFFSET 0 ROWS FETCH NEXT 101 ROWS ONLY") {
+		t.Errorf("expected a SQL Server row cap, got %s", query)
+	}
+	if strings.Contains(query, "LIMIT") {
+		t.Errorf("expected no LIMIT on SQL Server, got %s", query)
+	}
+}
+
+func TestCheck_DatasourceIDs(t *testing.T) {
+	check := &Check{DatasourceID: "ds-1", Parameters: CheckParameters{
+		Reconciliation: &ReconciliationConfig{TargetDatasourceID: "ds-2", TargetTable: "orders"},
+	}}
+	if ids := check.DatasourceIDs(); len(ids) != 2 || ids[0] != "ds-1" || ids[1] != "ds-2" {
+		t.Errorf("expected the check's and the target's datasource, got %v", ids)
+	}
+
+	check = &Check{DatasourceID: "ds-1", Parameters: CheckParameters{TableDiff: &TableDiffConfig{TargetTable: "orders"}}}
+	if ids := check.DatasourceIDs(); len(ids) != 1 || ids[0] != "ds-1" {
+		t.Errorf("expected only the check's datasource, got %v", ids)
+	}
+}
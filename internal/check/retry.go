@@ -0,0 +1,117 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// RetryPolicy controls how check runs that fail with a transient error are
+// retried. Backoff doubles after each attempt up to MaxBackoff, with jitter.
+type RetryPolicy struct {
+	MaxAttempts    int           `json:"max_attempts"`    // Attempts per run including the first, 1 disables retries
+	InitialBackoff time.Duration `json:"initial_backoff"` // Wait before the first retry
+	MaxBackoff     time.Duration `json:"max_backoff"`     // Upper bound of a wait
+}
+
+// DefaultRetryPolicy is the retry policy of a new Manager
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+}
+
+// Attempt is a failed attempt of a check run, recorded in the run's details
+type Attempt struct {
+	Attempt int                   `json:"attempt"`
+	Error   string                `json:"error"`
+	Class   datasource.ErrorClass `json:"class"`
+	Backoff time.Duration         `json:"backoff,omitempty"` // Wait before the next attempt
+}
+
+// backoff returns the wait before the retry following attempt n, counted from
+// 1: half the exponential delay plus a random part of the other half
+func (p RetryPolicy) backoff(n int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < n && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// SetRetryPolicy replaces the retry policy of check runs
+func (m *Manager) SetRetryPolicy(policy RetryPolicy) {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retry = policy
+}
+
+// GetRetryPolicy returns the retry policy of check runs
+func (m *Manager) GetRetryPolicy() RetryPolicy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.retry
+}
+
+// withRetries calls run until it succeeds, fails with a permanent error, or
+// the policy's attempts are used up, waiting between attempts. It returns the
+// failed attempts and the last error. ctx ending stops the retries.
+func (m *Manager) withRetries(ctx context.Context, run func() error) ([]Attempt, error) {
+	policy := m.GetRetryPolicy()
+	var attempts []Attempt
+	for n := 1; ; n++ {
+		err := run()
+		if err == nil {
+			return attempts, nil
+		}
+
+		attempt := Attempt{Attempt: n, Error: err.Error(), Class: datasource.ClassifyError(err)}
+		if attempt.Class != datasource.ErrorTransient || n >= policy.MaxAttempts || ctx.Err() != nil {
+			return append(attempts, attempt), err
+		}
+		attempt.Backoff = policy.backoff(n)
+		attempts = append(attempts, attempt)
+
+		timer := time.NewTimer(attempt.Backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return attempts, err
+		}
+	}
+}
+
+// recordAttempts adds the attempt history of a run to its result's details,
+// and the attempt count to the message of a run that failed after retries.
+// Runs that succeeded at once record nothing.
+func recordAttempts(result *CheckResult, attempts []Attempt, succeeded bool) {
+	if len(attempts) == 0 {
+		return
+	}
+	if result.Details == nil {
+		result.Details = make(map[string]interface{})
+	}
+	total := len(attempts)
+	if succeeded {
+		total++
+	}
+	result.Details["attempts"] = total
+	result.Details["retries"] = attempts
+	if !succeeded && total > 1 {
+		result.Message += fmt.Sprintf(" (after %d attempts)", total)
+	}
+}
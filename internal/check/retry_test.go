@@ -0,0 +1,117 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// flakyConnector fails its first queries with err, then answers like fakeConnector
+type flakyConnector struct {
+	fakeConnector
+	failures int
+	failWith error
+}
+
+func (c *flakyConnector) Query(ctx context.Context, query string, args ...interface{}) (*datasource.QueryResult, error) {
+	if c.failures > 0 {
+		c.failures--
+		c.queries = append(c.queries, query)
+		return nil, c.failWith
+	}
+	return c.fakeConnector.Query(ctx, query, args...)
+}
+
+func newRetryingManager(attempts int) *Manager {
+	m := NewManager(datasource.NewManager())
+	m.SetRetryPolicy(RetryPolicy{MaxAttempts: attempts, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+	return m
+}
+
+func batchOf(m *Manager, connector datasource.Connector) *batch {
+	checks := []*Check{
+		{ID: "a", Type: TypeNullCheck, Table: "orders", Column: "email"},
+		{ID: "b", Type: TypeMaxValue, Table: "orders", Column: "amount"},
+	}
+	batches, _ := m.planBatches(context.Background(), checks, connector)
+	return batches[0]
+}
+
+func TestRunBatch_RetriesTransientErrors(t *testing.T) {
+	connector := &flakyConnector{
+		fakeConnector: fakeConnector{rows: []map[string]interface{}{{
+			"c0_total_count": int64(10), "c0_null_count": int64(0), "c1_value": 5.0,
+		}}},
+		failures: 2,
+		failWith: errors.New("read tcp 10.0.0.1:5432: connection reset by peer"),
+	}
+	m := newRetryingManager(3)
+
+	results := m.runBatch(context.Background(), batchOf(m, connector), connector)
+
+	if len(connector.queries) != 3 {
+		t.Fatalf("expected two retries, got %d queries", len(connector.queries))
+	}
+	for _, result := range results {
+		if result.Status == StatusError {
+			t.Fatalf("expected the retried run to succeed, got %s", result.Error)
+		}
+		retries, _ := result.Details["retries"].([]Attempt)
+		if result.Details["attempts"] != 3 || len(retries) != 2 || retries[0].Class != datasource.ErrorTransient {
+			t.Errorf("expected the attempt history in the details, got %v", result.Details)
+		}
+	}
+}
+
+func TestRunBatch_RetriesExhausted(t *testing.T) {
+	connector := &flakyConnector{failures: 5, failWith: errors.New("ERROR: could not serialize access due to concurrent update (SQLSTATE 40001)")}
+	m := newRetryingManager(2)
+
+	for _, result := range m.runBatch(context.Background(), batchOf(m, connector), connector) {
+		if result.Status != StatusError || !strings.HasSuffix(result.Message, "(after 2 attempts)") {
+			t.Errorf("expected an error after 2 attempts, got %s: %s", result.Status, result.Message)
+		}
+	}
+	if len(connector.queries) != 2 {
+		t.Errorf("expected 2 attempts, got %d", len(connector.queries))
+	}
+}
+
+func TestRunBatch_PermanentErrorNotRetried(t *testing.T) {
+	connector := &flakyConnector{failures: 5, failWith: errors.New(`relation "orders" does not exist`)}
+	m := newRetryingManager(3)
+
+	results := m.runBatch(context.Background(), batchOf(m, connector), connector)
+
+	if len(connector.queries) != 1 {
+		t.Errorf("expected a permanent error not to be retried, got %d queries", len(connector.queries))
+	}
+	if results[0].Details["attempts"] != 1 {
+		t.Errorf("expected a single recorded attempt, got %v", results[0].Details)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	testCases := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 200 * time.Millisecond, 400 * time.Millisecond},
+		{6, 500 * time.Millisecond, time.Second},
+	}
+	for _, tc := range testCases {
+		for i := 0; i < 20; i++ {
+			if got := policy.backoff(tc.attempt); got < tc.min || got > tc.max {
+				t.Errorf("backoff after attempt %d = %s, expected within [%s, %s]", tc.attempt, got, tc.min, tc.max)
+			}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// confidenceZ is the z-score for the 95% confidence level reported on sampled metrics
+const confidenceZ = 1.96
+
+// supportsSampling reports whether a check type produces metrics that remain
+// meaningful when computed over a sample. Counts, extremes, sums and
+// distinct counts are not estimable from a plain sample.
+func supportsSampling(checkType Type) bool {
+	switch checkType {
+	case TypeNullCheck, TypeRegex, TypeFormat, TypeRange, TypeSetMembership, TypeReferentialIntegrity, TypeMeanValue, TypeStdDev, TypeDistribution, TypeExpression:
+		return true
+	default:
+		return false
+	}
+}
+
+// tableRef returns the FROM target for a check's table, wrapping it in a
+// dialect-specific sampling subquery when the check is configured with a
+// sample, and limiting it to the rows selected by the check's filter and the
+// run's incremental window, if any
+func tableRef(ctx context.Context, check *Check, connector datasource.Connector, alias string) (string, error) {
+	ref := check.Table
+	if sample := check.Parameters.Sample; sample != nil {
+		sampled, err := datasource.DialectFor(connector.Type()).SampledTable(check.Table, *sample)
+		if err != nil {
+			return "", fmt.Errorf("failed to build table sample: %w", err)
+		}
+		ref = sampled
+		if alias == "" {
+			alias = "_sample"
+		}
+	}
+	predicate := check.rowFilter()
+	window := windowFrom(ctx)
+	if window != nil {
+		predicate = andPredicates(predicate, window.predicate)
+	}
+	if predicate != "" {
+		if ref != check.Table {
+			ref += " _sample"
+		}
+		ref = fmt.Sprintf("(SELECT * FROM %s WHERE %s)", ref, predicate)
+		if alias == "" || alias == "_sample" {
+			alias = "_filtered"
+			if window != nil {
+				alias = "_incremental"
+			}
+		}
+	}
+	if alias != "" {
+		ref += " " + alias
+	}
+	return ref, nil
+}
+
+// recordSample records the sample configuration used to compute a result
+func recordSample(result *CheckResult, check *Check, sampledRows int64) {
+	sample := check.Parameters.Sample
+	if sample == nil {
+		return
+	}
+
+	method := sample.Method
+	if method == "" {
+		method = datasource.SampleBernoulli
+	}
+	info := map[string]interface{}{
+		"method": method,
+		"seed":   sample.Seed,
+	}
+	if sample.Percentage > 0 {
+		info["percentage"] = sample.Percentage
+	}
+	if sample.RowCount > 0 {
+		info["row_count"] = sample.RowCount
+	}
+	if sampledRows > 0 {
+		info["sampled_rows"] = sampledRows
+	}
+	result.Details["sample"] = info
+}
+
+// recordProportionBounds records a 95% Wilson score interval, in percent, for
+// a percentage metric computed as successes/total over a sample
+func recordProportionBounds(result *CheckResult, check *Check, successes, total int64) {
+	if check.Parameters.Sample == nil || total == 0 {
+		return
+	}
+	lower, upper := wilsonInterval(successes, total, confidenceZ)
+	result.Details["confidence_interval"] = map[string]interface{}{
+		"level": 0.95,
+		"lower": lower * 100,
+		"upper": upper * 100,
+	}
+}
+
+// wilsonInterval returns the Wilson score interval for a binomial proportion
+func wilsonInterval(successes, total int64, z float64) (float64, float64) {
+	n := float64(total)
+	p := float64(successes) / n
+	z2 := z * z
+
+	center := (p + z2/(2*n)) / (1 + z2/n)
+	margin := z * math.Sqrt(p*(1-p)/n+z2/(4*n*n)) / (1 + z2/n)
+
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
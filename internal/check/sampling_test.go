@@ -0,0 +1,77 @@
+package check
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+func TestRunNullCheck_Sampled(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	connector := &fakeConnector{
+		rows: []map[string]interface{}{
+			{"total_count": int64(1000), "null_count": int64(20)},
+		},
+	}
+	check := &Check{
+		Type:   TypeNullCheck,
+		Table:  "events",
+		Column: "email",
+		Parameters: CheckParameters{
+			MaxNullPercentage: 5,
+			Sample:            &datasource.Sample{Percentage: 1, Seed: 42},
+		},
+	}
+
+	result, err := m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(connector.queries[0], "TABLESAMPLE BERNOULLI (1) REPEATABLE (42)") {
+		t.Errorf("expected sampled query, got %s", connector.queries[0])
+	}
+	if _, ok := result.Details["sample"]; !ok {
+		t.Error("expected sample details to be recorded")
+	}
+	bounds, ok := result.Details["confidence_interval"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected confidence interval to be recorded")
+	}
+	lower, upper := bounds["lower"].(float64), bounds["upper"].(float64)
+	if lower >= 2 || upper <= 2 {
+		t.Errorf("expected interval around 2%%, got [%f, %f]", lower, upper)
+	}
+}
+
+func TestExecuteCheck_SamplingUnsupported(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	check := &Check{
+		Type:  TypeRowCount,
+		Table: "events",
+		Parameters: CheckParameters{
+			Sample: &datasource.Sample{Percentage: 10},
+		},
+	}
+
+	_, err := m.executeCheck(context.Background(), check, &fakeConnector{})
+	if err == nil {
+		t.Fatal("expected error for sampled row count check")
+	}
+}
+
+func TestWilsonInterval(t *testing.T) {
+	lower, upper := wilsonInterval(0, 100, confidenceZ)
+	if lower != 0 {
+		t.Errorf("expected lower bound 0 for no successes, got %f", lower)
+	}
+	if upper <= 0 || upper > 0.05 {
+		t.Errorf("expected small positive upper bound, got %f", upper)
+	}
+
+	lower, upper = wilsonInterval(50, 100, confidenceZ)
+	if lower > 0.5 || upper < 0.5 || upper-lower > 0.2 {
+		t.Errorf("expected interval around 0.5, got [%f, %f]", lower, upper)
+	}
+}
@@ -0,0 +1,252 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+const (
+	// defaultMaxSegments caps the segments listed in a segmented check's result
+	defaultMaxSegments = 100
+	// segmentLabelsInMessage is how many failing segments a message names
+	segmentLabelsInMessage = 5
+)
+
+// SegmentResult is the outcome of a segmented check for one group of rows
+type SegmentResult struct {
+	Segment       map[string]interface{} `json:"segment"` // Segment column values, e.g. {"country": "DE"}
+	Status        Status                 `json:"status"`
+	ActualValue   interface{}            `json:"actual_value"`
+	ExpectedValue interface{}            `json:"expected_value,omitempty"`
+	Message       string                 `json:"message"`
+	Details       map[string]interface{} `json:"details,omitempty"`
+}
+
+// Label renders the segment as "column=value" pairs, e.g. "country=DE"
+func (s SegmentResult) Label() string {
+	columns := make([]string, 0, len(s.Segment))
+	for col := range s.Segment {
+		columns = append(columns, col)
+	}
+	sort.Strings(columns)
+
+	parts := make([]string, len(columns))
+	for i, col := range columns {
+		value := s.Segment[col]
+		if value == nil {
+			parts[i] = col + "=NULL"
+		} else {
+			parts[i] = fmt.Sprintf("%s=%v", col, value)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FailedSegments returns the labels of the segments that failed
+func (r *CheckResult) FailedSegments() []string {
+	var labels []string
+	for _, segment := range r.Segments {
+		if segment.Status == StatusFailed || segment.Status == StatusError {
+			labels = append(labels, segment.Label())
+		}
+	}
+	return labels
+}
+
+// supportsSegments reports whether a check type computes an aggregate over
+// the rows of its table, so that it can be computed per group
+func supportsSegments(checkType Type) bool {
+	switch checkType {
+	case TypeRowCount, TypeNullCheck, TypeUniqueness, TypeMinValue, TypeMaxValue, TypeMeanValue, TypeSumValue,
+		TypeStdDev, TypeRegex, TypeFormat, TypeRange, TypeSetMembership, TypeReferentialIntegrity, TypeExpression:
+		return true
+	default:
+		return false
+	}
+}
+
+// aggregate is an aggregate expression and the column alias it is read by
+type aggregate struct {
+	expr  string
+	alias string
+}
+
+// aggregateSpec describes the single-row aggregate query of a check executor
+type aggregateSpec struct {
+	description string // e.g. "null check", used in error messages
+	aggregates  []aggregate
+	from        string // FROM clause, including any joins
+	alias       string // Alias of the checked table in from, if any
+	// evaluate turns an aggregate row into a result with its status set
+	evaluate func(row map[string]interface{}) (*CheckResult, error)
+}
+
+// selectList renders the aggregates of a spec, prefixing their aliases
+func (s aggregateSpec) selectList(prefix string) string {
+	parts := make([]string, len(s.aggregates))
+	for i, agg := range s.aggregates {
+		parts[i] = fmt.Sprintf("%s as %s%s", agg.expr, prefix, agg.alias)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// runSpec builds a check's aggregate and runs it
+func (m *Manager) runSpec(ctx context.Context, check *Check, connector datasource.Connector,
+	build func(context.Context, *Check, datasource.Connector) (aggregateSpec, error)) (*CheckResult, error) {
+	spec, err := build(ctx, check, connector)
+	if err != nil {
+		return nil, err
+	}
+	return m.runAggregate(ctx, check, connector, spec)
+}
+
+// runAggregate runs an executor's aggregate query and evaluates the row. For
+// segmented checks the query is grouped by the segment columns instead, and
+// each group is evaluated on its own.
+func (m *Manager) runAggregate(ctx context.Context, check *Check, connector datasource.Connector, spec aggregateSpec) (*CheckResult, error) {
+	if len(check.SegmentBy) == 0 {
+		query := fmt.Sprintf("SELECT %s\n\t\tFROM %s", spec.selectList(""), spec.from)
+		queryResult, err := connector.Query(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute %s query: %w", spec.description, err)
+		}
+		if len(queryResult.Rows) == 0 {
+			return nil, fmt.Errorf("%s query returned no results", spec.description)
+		}
+		return spec.evaluate(queryResult.Rows[0])
+	}
+	return m.runSegmented(ctx, check, connector, spec)
+}
+
+// runSegmented evaluates an aggregate per group of the segment columns. The
+// overall status is the worst segment's. Every group is evaluated, but at most
+// MaxSegments are listed in the result, worst first, so a high-cardinality
+// segment column cannot explode its size.
+func (m *Manager) runSegmented(ctx context.Context, check *Check, connector datasource.Connector, spec aggregateSpec) (*CheckResult, error) {
+	maxSegments := check.MaxSegments
+	if maxSegments <= 0 {
+		maxSegments = defaultMaxSegments
+	}
+
+	qualified := make([]string, len(check.SegmentBy))
+	selected := make([]string, len(check.SegmentBy))
+	for i, col := range check.SegmentBy {
+		qualified[i] = col
+		if spec.alias != "" {
+			qualified[i] = spec.alias + "." + col
+		}
+		selected[i] = fmt.Sprintf("%s as %s", qualified[i], col)
+	}
+	groupBy := strings.Join(qualified, ", ")
+
+	query := fmt.Sprintf("SELECT %s, %s\n\t\tFROM %s\n\t\tGROUP BY %s ORDER BY %s",
+		strings.Join(selected, ", "), spec.selectList(""), spec.from, groupBy, groupBy)
+
+	queryResult, err := connector.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute segmented %s query: %w", spec.description, err)
+	}
+
+	rows := queryResult.Rows
+	result := &CheckResult{
+		Status:   StatusPassed,
+		Segments: make([]SegmentResult, 0, len(rows)),
+	}
+	var worst *SegmentResult
+	var failed, warned []string
+	for _, row := range rows {
+		segmentResult, err := spec.evaluate(row)
+		if err != nil {
+			return nil, err
+		}
+		segment := SegmentResult{
+			Segment:       make(map[string]interface{}, len(check.SegmentBy)),
+			Status:        segmentResult.Status,
+			ActualValue:   segmentResult.ActualValue,
+			ExpectedValue: segmentResult.ExpectedValue,
+			Message:       segmentResult.Message,
+			Details:       segmentResult.Details,
+		}
+		for _, col := range check.SegmentBy {
+			segment.Segment[col] = row[col]
+		}
+		result.Segments = append(result.Segments, segment)
+
+		switch segment.Status {
+		case StatusFailed, StatusError:
+			failed = append(failed, segment.Label())
+		case StatusWarning:
+			warned = append(warned, segment.Label())
+		}
+		if worst == nil || statusRank(segment.Status) > statusRank(worst.Status) {
+			worst = &segment
+		}
+	}
+
+	segmentCount := len(result.Segments)
+	result.Details = map[string]interface{}{
+		"segment_by":       check.SegmentBy,
+		"segment_count":    segmentCount,
+		"failed_segments":  len(failed),
+		"warning_segments": len(warned),
+	}
+	truncated := segmentCount > maxSegments
+	if truncated {
+		sort.SliceStable(result.Segments, func(i, j int) bool {
+			return statusRank(result.Segments[i].Status) > statusRank(result.Segments[j].Status)
+		})
+		result.Segments = result.Segments[:maxSegments]
+		result.Details["segments_truncated"] = true
+		result.Details["max_segments"] = maxSegments
+	}
+
+	if worst == nil {
+		result.Message = "no segments to evaluate"
+		return result, nil
+	}
+	result.Status = worst.Status
+	result.ActualValue = worst.ActualValue
+	result.ExpectedValue = worst.ExpectedValue
+
+	switch {
+	case len(failed) > 0:
+		result.Message = fmt.Sprintf("%d of %d segments failed: %s", len(failed), segmentCount, segmentList(failed))
+	case len(warned) > 0:
+		result.Message = fmt.Sprintf("%d of %d segments at warning level: %s", len(warned), segmentCount, segmentList(warned))
+	default:
+		result.Message = fmt.Sprintf("all %d segments passed", segmentCount)
+	}
+	if truncated {
+		result.Message += fmt.Sprintf(" (the worst %d segments are listed)", maxSegments)
+	}
+	return result, nil
+}
+
+// statusRank orders statuses from best to worst
+func statusRank(status Status) int {
+	switch status {
+	case StatusError:
+		return 4
+	case StatusFailed:
+		return 3
+	case StatusWarning:
+		return 2
+	case StatusPassed:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// segmentList names the first few segments of a list, e.g. "country=DE;
+// country=FR and 3 more"
+func segmentList(labels []string) string {
+	if len(labels) <= segmentLabelsInMessage {
+		return strings.Join(labels, "; ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(labels[:segmentLabelsInMessage], "; "), len(labels)-segmentLabelsInMessage)
+}
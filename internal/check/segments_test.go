@@ -0,0 +1,124 @@
+package check
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+func TestRunSegmentedNullCheck(t *testing.T) {
+	connector := &fakeConnector{rows: []map[string]interface{}{
+		{"country": "DE", "total_count": int64(100), "null_count": int64(1)},
+		{"country": "FR", "total_count": int64(100), "null_count": int64(5)},
+		{"country": nil, "total_count": int64(10), "null_count": int64(0)},
+	}}
+	check := &Check{
+		Type:       TypeNullCheck,
+		Table:      "users",
+		Column:     "email",
+		SegmentBy:  []string{"country"},
+		Parameters: CheckParameters{MaxNullPercentage: 2},
+	}
+	m := NewManager(datasource.NewManager())
+
+	result, err := m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	query := connector.queries[0]
+	for _, want := range []string{"SELECT country as country,", "GROUP BY country ORDER BY country"} {
+		if !strings.Contains(query, want) {
+			t.Errorf("expected %q in query, got %s", want, query)
+		}
+	}
+	if result.Status != StatusFailed {
+		t.Fatalf("expected the worst segment to fail the check, got %s: %s", result.Status, result.Message)
+	}
+	if len(result.Segments) != 3 || result.Segments[0].Status != StatusPassed || result.Segments[1].Status != StatusFailed {
+		t.Fatalf("unexpected segments: %+v", result.Segments)
+	}
+	if result.ActualValue != 5.0 {
+		t.Errorf("expected actual value of the failing segment, got %v", result.ActualValue)
+	}
+	if got := result.FailedSegments(); len(got) != 1 || got[0] != "country=FR" {
+		t.Errorf("expected country=FR to fail, got %v", got)
+	}
+	if result.Message != "1 of 3 segments failed: country=FR" {
+		t.Errorf("unexpected message: %s", result.Message)
+	}
+	if result.Segments[2].Label() != "country=NULL" {
+		t.Errorf("unexpected label for NULL segment: %s", result.Segments[2].Label())
+	}
+}
+
+func TestRunSegmentedCheck_MaxSegments(t *testing.T) {
+	connector := &fakeConnector{rows: []map[string]interface{}{
+		{"region": "a", "row_count": int64(10)},
+		{"region": "b", "row_count": int64(10)},
+		{"region": "c", "row_count": int64(0)},
+	}}
+	check := &Check{
+		Type:        TypeRowCount,
+		Table:       "orders",
+		SegmentBy:   []string{"region"},
+		MaxSegments: 2,
+		Parameters:  CheckParameters{MinRows: 1},
+	}
+	m := NewManager(datasource.NewManager())
+
+	result, err := m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(connector.queries[0], "LIMIT") {
+		t.Errorf("expected every group to be evaluated, got %s", connector.queries[0])
+	}
+	if result.Status != StatusFailed || len(result.Segments) != 2 {
+		t.Fatalf("expected the segment past the cap to fail the check, got %s with %d segments", result.Status, len(result.Segments))
+	}
+	if result.Segments[0].Label() != "region=c" || result.Segments[1].Label() != "region=a" {
+		t.Errorf("expected the failing segment to be listed first, got %+v", result.Segments)
+	}
+	if result.Details["segments_truncated"] != true || result.Details["segment_count"] != 3 {
+		t.Errorf("expected 3 segments with the list truncated, got %v", result.Details)
+	}
+	if result.Message != "1 of 3 segments failed: region=c (the worst 2 segments are listed)" {
+		t.Errorf("unexpected message: %s", result.Message)
+	}
+}
+
+func TestRunSegmentedReferentialCheck_QualifiesColumns(t *testing.T) {
+	connector := &fakeConnector{rows: []map[string]interface{}{
+		{"store_id": int64(1), "total_count": int64(10), "matched_count": int64(10)},
+	}}
+	check := &Check{
+		Type:      TypeReferentialIntegrity,
+		Table:     "orders",
+		Column:    "customer_id",
+		SegmentBy: []string{"store_id"},
+		Parameters: CheckParameters{
+			ReferenceTable:  "customers",
+			ReferenceColumn: "id",
+		},
+	}
+	m := NewManager(datasource.NewManager())
+
+	if _, err := m.executeCheck(context.Background(), check, connector); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(connector.queries[0], "t.store_id as store_id") || !strings.Contains(connector.queries[0], "GROUP BY t.store_id") {
+		t.Errorf("expected segment column qualified with the table alias, got %s", connector.queries[0])
+	}
+}
+
+func TestRunSegmentedCheck_Unsupported(t *testing.T) {
+	check := &Check{Type: TypeFreshness, Table: "orders", SegmentBy: []string{"region"}}
+	m := NewManager(datasource.NewManager())
+
+	if _, err := m.executeCheck(context.Background(), check, &fakeConnector{}); err == nil {
+		t.Fatal("expected an error for a segmented freshness check")
+	}
+}
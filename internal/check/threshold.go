@@ -0,0 +1,238 @@
+package check
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Operator compares a metric to threshold values
+type Operator string
+
+const (
+	OperatorEq      Operator = "eq"
+	OperatorNe      Operator = "ne"
+	OperatorLt      Operator = "lt"
+	OperatorLte     Operator = "lte"
+	OperatorGt      Operator = "gt"
+	OperatorGte     Operator = "gte"
+	OperatorBetween Operator = "between" // Inclusive of MinValue and MaxValue
+)
+
+// Condition is a comparison that a healthy metric satisfies
+type Condition struct {
+	Operator Operator `json:"operator,omitempty"`
+	Value    float64  `json:"value"`
+	MinValue float64  `json:"min_value,omitempty"`
+	MaxValue float64  `json:"max_value,omitempty"`
+}
+
+// holds reports whether value satisfies the condition
+func (c Condition) holds(value float64) (bool, error) {
+	switch c.Operator {
+	case OperatorEq:
+		return value == c.Value, nil
+	case OperatorNe:
+		return value != c.Value, nil
+	case OperatorLt:
+		return value < c.Value, nil
+	case OperatorLte:
+		return value <= c.Value, nil
+	case OperatorGt:
+		return value > c.Value, nil
+	case OperatorGte:
+		return value >= c.Value, nil
+	case OperatorBetween:
+		if c.MinValue > c.MaxValue {
+			return false, fmt.Errorf("threshold min_value %g exceeds max_value %g", c.MinValue, c.MaxValue)
+		}
+		return value >= c.MinValue && value <= c.MaxValue, nil
+	default:
+		return false, fmt.Errorf("unsupported threshold operator: %q", c.Operator)
+	}
+}
+
+// describe renders the condition for result messages, e.g. "at most 5.00%"
+func (c Condition) describe(percent bool) string {
+	format := func(v float64) string { return formatMetric(v, percent) }
+	switch c.Operator {
+	case OperatorEq:
+		return "equal to " + format(c.Value)
+	case OperatorNe:
+		return "not equal to " + format(c.Value)
+	case OperatorLt:
+		return "below " + format(c.Value)
+	case OperatorLte:
+		return "at most " + format(c.Value)
+	case OperatorGt:
+		return "above " + format(c.Value)
+	case OperatorGte:
+		return "at least " + format(c.Value)
+	case OperatorBetween:
+		return fmt.Sprintf("between %s and %s", format(c.MinValue), format(c.MaxValue))
+	default:
+		return string(c.Operator)
+	}
+}
+
+// expected returns the condition's bound for CheckResult.ExpectedValue
+func (c Condition) expected() interface{} {
+	if c.Operator == OperatorBetween {
+		return map[string]float64{"min": c.MinValue, "max": c.MaxValue}
+	}
+	return c.Value
+}
+
+// condition returns the fail condition of a threshold. Range thresholds
+// without an operator are between MinValue and MaxValue.
+func (t Threshold) condition() Condition {
+	operator := t.Operator
+	if operator == "" && t.Type == ThresholdRange {
+		operator = OperatorBetween
+	}
+	return Condition{Operator: operator, Value: t.Value, MinValue: t.MinValue, MaxValue: t.MaxValue}
+}
+
+// explicit reports whether the threshold configures its own comparison, as
+// opposed to relying on the executor's defaults from check parameters
+func (t Threshold) explicit() bool {
+	return t.Type != ThresholdAnomaly && (t.Operator != "" || t.Type == ThresholdRange)
+}
+
+// metric is the measurement an executor hands to the threshold evaluator
+type metric struct {
+	name  string  // e.g. "null count"
+	value float64 // Compared by absolute thresholds
+	// reference is what percentage thresholds are relative to: the rows
+	// examined for row-level metrics, or the expected value for aggregates.
+	// percentName names the value as a percentage of it, e.g. "null
+	// percentage", and is empty when the metric has no reference.
+	reference   float64
+	percentName string
+	// percentNative makes percentage the semantics of thresholds without a type
+	percentNative bool
+}
+
+// rowMetric is a count of rows that is natively judged as a percentage of all rows examined
+func rowMetric(name string, count, total int64, percentName string) metric {
+	return metric{name: name, value: float64(count), reference: float64(total), percentName: percentName, percentNative: true}
+}
+
+// measure returns the metric under a threshold type's semantics
+func (m metric) measure(thresholdType ThresholdType) (string, float64, bool, error) {
+	percent := thresholdType == ThresholdPercentage || (thresholdType == "" && m.percentNative)
+	if !percent {
+		return m.name, m.value, false, nil
+	}
+	if m.percentName == "" {
+		return "", 0, false, fmt.Errorf("percentage thresholds are not supported for %s", m.name)
+	}
+	var percentage float64
+	if m.reference != 0 {
+		percentage = m.value / m.reference * 100
+	}
+	return m.percentName, percentage, true, nil
+}
+
+// evaluateThreshold sets a result's status and message by comparing a metric
+// to the check's threshold. When the threshold has no operator of its own,
+// the executor's defaults, derived from check parameters, are used instead.
+// Failing any fail condition fails the result; otherwise failing the warn
+// condition makes it a warning. ExpectedValue is set to the violated bound
+// unless the executor already reported an expected value.
+func evaluateThreshold(result *CheckResult, threshold Threshold, m metric, defaults ...Threshold) error {
+	thresholds := defaults
+	if threshold.explicit() {
+		thresholds = []Threshold{threshold}
+	}
+
+	for _, t := range thresholds {
+		name, value, percent, err := m.measure(t.Type)
+		if err != nil {
+			return err
+		}
+		cond := t.condition()
+		ok, err := cond.holds(value)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			result.Status = StatusFailed
+			result.Message = fmt.Sprintf("%s %s, expected %s", name, formatMetric(value, percent), cond.describe(percent))
+			if result.ExpectedValue == nil {
+				result.ExpectedValue = cond.expected()
+			}
+			return nil
+		}
+	}
+
+	if warn := threshold.Warn; warn != nil {
+		cond, warnType := *warn, threshold.warnType()
+		if len(thresholds) > 0 {
+			if cond.Operator == "" {
+				cond.Operator = thresholds[0].condition().Operator
+			}
+			if warnType == "" {
+				warnType = thresholds[0].Type
+			}
+		}
+		name, value, percent, err := m.measure(warnType)
+		if err != nil {
+			return err
+		}
+		ok, err := cond.holds(value)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			result.Status = StatusWarning
+			result.Message = fmt.Sprintf("%s %s, warning threshold is %s", name, formatMetric(value, percent), cond.describe(percent))
+			if result.ExpectedValue == nil {
+				result.ExpectedValue = cond.expected()
+			}
+			return nil
+		}
+	}
+
+	result.Status = StatusPassed
+	if len(thresholds) == 0 {
+		name, value, percent, err := m.measure("")
+		if err != nil {
+			return err
+		}
+		result.Message = fmt.Sprintf("%s is %s", name, formatMetric(value, percent))
+		return nil
+	}
+	name, value, percent, err := m.measure(thresholds[0].Type)
+	if err != nil {
+		return err
+	}
+	result.Message = fmt.Sprintf("%s %s is %s", name, formatMetric(value, percent), thresholds[0].condition().describe(percent))
+	return nil
+}
+
+// warnType returns the semantics of the warn condition, which follows the
+// threshold's type. Range only selects the between operator, so it compares
+// absolute values like the fail condition does. An empty type follows the
+// first fail condition.
+func (t Threshold) warnType() ThresholdType {
+	if t.Type == ThresholdAnomaly {
+		return ""
+	}
+	if t.Type == ThresholdRange {
+		return ThresholdAbsolute
+	}
+	return t.Type
+}
+
+// formatMetric renders integral values without decimals, fractional values
+// with at most four and percentages with two
+func formatMetric(value float64, percent bool) string {
+	if percent {
+		return fmt.Sprintf("%.2f%%", value)
+	}
+	if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+		return strconv.FormatFloat(value, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(math.Round(value*1e4)/1e4, 'f', -1, 64)
+}
@@ -0,0 +1,162 @@
+package check
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+func TestConditionHolds(t *testing.T) {
+	testCases := []struct {
+		cond     Condition
+		value    float64
+		expected bool
+	}{
+		{Condition{Operator: OperatorEq, Value: 5}, 5, true},
+		{Condition{Operator: OperatorNe, Value: 5}, 5, false},
+		{Condition{Operator: OperatorLt, Value: 5}, 5, false},
+		{Condition{Operator: OperatorLte, Value: 5}, 5, true},
+		{Condition{Operator: OperatorGt, Value: 5}, 6, true},
+		{Condition{Operator: OperatorGte, Value: 5}, 4, false},
+		{Condition{Operator: OperatorBetween, MinValue: 1, MaxValue: 10}, 10, true},
+		{Condition{Operator: OperatorBetween, MinValue: 1, MaxValue: 10}, 0.5, false},
+	}
+
+	for _, tc := range testCases {
+		ok, err := tc.cond.holds(tc.value)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.cond.Operator, err)
+		}
+		if ok != tc.expected {
+			t.Errorf("%s %v on %v: expected %v", tc.cond.Operator, tc.cond, tc.value, tc.expected)
+		}
+	}
+
+	if _, err := (Condition{Operator: "like"}).holds(1); err == nil {
+		t.Error("expected error for unsupported operator")
+	}
+	if _, err := (Condition{Operator: OperatorBetween, MinValue: 10, MaxValue: 1}).holds(5); err == nil {
+		t.Error("expected error for inverted between bounds")
+	}
+}
+
+func TestEvaluateThreshold_WarnAndFail(t *testing.T) {
+	threshold := Threshold{
+		Type:     ThresholdPercentage,
+		Operator: OperatorLte,
+		Value:    5,
+		Warn:     &Condition{Value: 1},
+	}
+
+	testCases := []struct {
+		nulls    int64
+		expected Status
+	}{
+		{0, StatusPassed},
+		{3, StatusWarning},
+		{8, StatusFailed},
+	}
+
+	for _, tc := range testCases {
+		result := &CheckResult{}
+		if err := evaluateThreshold(result, threshold, rowMetric("null count", tc.nulls, 100, "null percentage")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Status != tc.expected {
+			t.Errorf("%d nulls: expected %s, got %s: %s", tc.nulls, tc.expected, result.Status, result.Message)
+		}
+	}
+}
+
+func TestEvaluateThreshold_Semantics(t *testing.T) {
+	nulls := rowMetric("null count", 30, 1000, "null percentage")
+
+	// 30 rows is 3% of the table: within a 5% limit but over a 10 row limit
+	result := &CheckResult{}
+	if err := evaluateThreshold(result, Threshold{Type: ThresholdPercentage, Operator: OperatorLte, Value: 5}, nulls); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusPassed {
+		t.Errorf("expected percentage threshold to pass, got %s: %s", result.Status, result.Message)
+	}
+
+	result = &CheckResult{}
+	if err := evaluateThreshold(result, Threshold{Type: ThresholdAbsolute, Operator: OperatorLte, Value: 10}, nulls); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusFailed || result.ExpectedValue != 10.0 {
+		t.Errorf("expected absolute threshold to fail against 10, got %s: %v", result.Status, result.ExpectedValue)
+	}
+
+	if err := evaluateThreshold(&CheckResult{}, Threshold{Type: ThresholdPercentage, Operator: OperatorLte, Value: 5}, metric{name: "row count", value: 10}); err == nil {
+		t.Error("expected error for a percentage threshold on a metric without a reference")
+	}
+}
+
+func TestEvaluateThreshold_ExecutorDefaults(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	connector := &fakeConnector{rows: []map[string]interface{}{{"total_count": int64(100), "null_count": int64(4)}}}
+
+	// Parameters alone keep their meaning
+	check := &Check{Type: TypeNullCheck, Table: "users", Column: "email", Parameters: CheckParameters{MaxNullPercentage: 2}}
+	result, err := m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusFailed {
+		t.Errorf("expected failure over 2%%, got %s: %s", result.Status, result.Message)
+	}
+
+	// A warn level applies to the parameter-derived fail level
+	check.Parameters.MaxNullPercentage = 10
+	check.Threshold = Threshold{Warn: &Condition{Value: 2}}
+	result, err = m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusWarning {
+		t.Errorf("expected warning over 2%%, got %s: %s", result.Status, result.Message)
+	}
+
+	// An explicit operator replaces the parameters
+	check.Threshold = Threshold{Type: ThresholdAbsolute, Operator: OperatorEq, Value: 0}
+	result, err = m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusFailed {
+		t.Errorf("expected failure with 4 nulls, got %s: %s", result.Status, result.Message)
+	}
+}
+
+// queryResultConnector returns a fixed query result, columns included
+type queryResultConnector struct {
+	fakeConnector
+	result *datasource.QueryResult
+}
+
+func (c *queryResultConnector) Query(ctx context.Context, query string, args ...interface{}) (*datasource.QueryResult, error) {
+	return c.result, nil
+}
+
+func TestRunCustomSQLCheck_Threshold(t *testing.T) {
+	connector := &queryResultConnector{result: &datasource.QueryResult{
+		Columns:  []string{"late_orders"},
+		Rows:     []map[string]interface{}{{"late_orders": "12"}},
+		RowCount: 1,
+	}}
+	check := &Check{
+		Type:       TypeCustomSQL,
+		Parameters: CheckParameters{CustomSQL: "SELECT COUNT(*) AS late_orders FROM orders WHERE late"},
+		Threshold:  Threshold{Operator: OperatorLt, Value: 20, Warn: &Condition{Value: 10}},
+	}
+
+	result, err := NewManager(datasource.NewManager()).executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusWarning || result.ActualValue != 12.0 {
+		t.Errorf("expected warning on 12, got %s (%v): %s", result.Status, result.ActualValue, result.Message)
+	}
+}
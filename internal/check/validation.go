@@ -0,0 +1,435 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+	"github.com/vinod901/opendq-go/internal/expr"
+	"github.com/vinod901/opendq-go/internal/view"
+)
+
+// FieldError is a problem with one field of a check definition
+type FieldError struct {
+	Field   string `json:"field"` // Path of the field in the check's JSON, e.g. parameters.pattern
+	Message string `json:"message"`
+}
+
+// ValidationError lists the invalid fields of a check definition
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+// Error renders the field errors in one line
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fmt.Sprintf("%s: %s", fe.Field, fe.Message)
+	}
+	return "invalid check: " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// knownType reports whether a check type has an executor
+func knownType(checkType Type) bool {
+	switch checkType {
+	case TypeRowCount, TypeNullCheck, TypeUniqueness, TypeFreshness, TypeCustomSQL, TypeExpression,
+		TypeMinValue, TypeMaxValue, TypeMeanValue, TypeSumValue, TypeStdDev,
+		TypeRegex, TypeFormat, TypeRange, TypeSetMembership,
+		TypeReferentialIntegrity, TypeVolume, TypeDistribution, TypeReconciliation, TypeTableDiff,
+		TypeSchemaMatch, TypeColumnCount, TypeColumnType:
+		return true
+	default:
+		return false
+	}
+}
+
+// requiresColumn reports whether a check type measures the values of its column
+func requiresColumn(checkType Type) bool {
+	switch checkType {
+	case TypeNullCheck, TypeMinValue, TypeMaxValue, TypeMeanValue, TypeSumValue, TypeStdDev,
+		TypeRegex, TypeFormat, TypeRange, TypeSetMembership, TypeReferentialIntegrity, TypeDistribution:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateStored validates a check definition before it is stored and returns
+// a *ValidationError naming every invalid field. The columns the check names
+// are checked when its datasource is connected; expression checks, whose rule
+// is type-checked against the columns, require it.
+func (m *Manager) validateStored(ctx context.Context, check *Check) error {
+	v := &ValidationError{}
+	var connector datasource.Connector
+	if check.DatasourceID != "" || check.Type == TypeExpression {
+		c, err := m.datasourceManager.GetConnector(ctx, check.DatasourceID)
+		switch {
+		case err == nil:
+			connector = c
+		case check.Type == TypeExpression:
+			v.add("datasource_id", "failed to get datasource connector: %v", err)
+		}
+	}
+
+	m.validateFields(ctx, v, check, connector)
+	if len(v.Errors) > 0 {
+		return v
+	}
+	return nil
+}
+
+// validateDefinition validates a check definition to compile or run without
+// storing it, and returns the connector of its datasource, which it requires.
+// Invalid fields, such as a column missing from the table, a bad regular
+// expression or an invalid threshold, are returned as a *ValidationError.
+func (m *Manager) validateDefinition(ctx context.Context, check *Check) (datasource.Connector, error) {
+	v := &ValidationError{}
+	var connector datasource.Connector
+	if check.DatasourceID == "" {
+		v.add("datasource_id", "datasource_id is required")
+	} else {
+		var err error
+		if connector, err = m.datasourceManager.GetConnector(ctx, check.DatasourceID); err != nil {
+			v.add("datasource_id", "%v", err)
+		}
+	}
+
+	m.validateFields(ctx, v, check, connector)
+	if len(v.Errors) > 0 {
+		return nil, v
+	}
+	return connector, nil
+}
+
+// validateFields adds the errors of a check definition's fields to v. The
+// columns the check names are looked up through connector unless it is nil.
+func (m *Manager) validateFields(ctx context.Context, v *ValidationError, check *Check, connector datasource.Connector) {
+	switch {
+	case check.Type == "":
+		v.add("type", "type is required")
+	case !knownType(check.Type):
+		v.add("type", "unsupported check type: %s", check.Type)
+	}
+	if check.Table == "" && check.Type != TypeCustomSQL {
+		v.add("table", "table is required")
+	}
+	if check.Column == "" {
+		if requiresColumn(check.Type) {
+			v.add("column", "column is required for %s checks", check.Type)
+		} else if check.Type == TypeUniqueness && len(check.Parameters.UniqueColumns) == 0 {
+			v.add("column", "column or parameters.unique_columns is required for uniqueness checks")
+		}
+	}
+	if check.TimeoutSeconds < 0 {
+		v.add("timeout_seconds", "timeout_seconds must not be negative")
+	}
+	if check.MaxSegments < 0 {
+		v.add("max_segments", "max_segments must not be negative")
+	}
+
+	validateParameters(v, check)
+	validateThreshold(v, check)
+	validateRowSelection(v, check)
+
+	if len(check.DependsOn) > 0 {
+		m.mu.RLock()
+		err := m.validateDependencies(check)
+		m.mu.RUnlock()
+		if err != nil {
+			v.add("depends_on", "%v", err)
+		}
+	}
+
+	if connector != nil && check.Table != "" && check.Type != TypeCustomSQL {
+		validateColumns(ctx, v, check, connector)
+	}
+	if connector != nil && check.Type == TypeReconciliation {
+		m.validateReconciliationTarget(ctx, v, check, connector)
+	}
+	if sample := check.Parameters.Sample; connector != nil && sample != nil && supportsSampling(check.Type) && sample.Validate() == nil {
+		// The engine's dialect decides what it can sample, e.g. with a seed
+		if _, err := datasource.DialectFor(connector.Type()).SampledTable(check.Table, *sample); err != nil {
+			v.add("parameters.sample", "%v", err)
+		}
+	}
+}
+
+// validateParameters checks the parameters each check type requires
+func validateParameters(v *ValidationError, check *Check) {
+	params := check.Parameters
+	switch check.Type {
+	case TypeFreshness:
+		if params.TimestampColumn == "" {
+			v.add("parameters.timestamp_column", "timestamp_column is required for freshness checks")
+		}
+	case TypeCustomSQL:
+		if strings.TrimSpace(params.CustomSQL) == "" {
+			v.add("parameters.custom_sql", "custom_sql is required for custom_sql checks")
+		}
+	case TypeExpression:
+		switch params.ExpressionMode {
+		case "", ExpressionAuto, ExpressionSQL, ExpressionGo:
+		default:
+			v.add("parameters.expression_mode", "unsupported expression mode: %s", params.ExpressionMode)
+		}
+		if err := expr.Parse(params.Expression); err != nil {
+			v.add("parameters.expression", "%v", err)
+		}
+	case TypeRegex:
+		if params.Pattern == "" {
+			v.add("parameters.pattern", "pattern is required for regex checks")
+		} else if _, err := regexp.Compile(params.Pattern); err != nil {
+			v.add("parameters.pattern", "invalid regular expression: %v", err)
+		}
+	case TypeFormat:
+		if params.Format == "" {
+			v.add("parameters.format", "format is required for format checks")
+		} else if _, ok := Formats[params.Format]; !ok {
+			v.add("parameters.format", "unknown format: %s", params.Format)
+		}
+	case TypeSetMembership:
+		if len(params.AllowedValues) == 0 {
+			v.add("parameters.allowed_values", "allowed_values is required for set_membership checks")
+		}
+	case TypeReferentialIntegrity:
+		if params.ReferenceTable == "" {
+			v.add("parameters.reference_table", "reference_table is required for referential_integrity checks")
+		}
+		if params.ReferenceColumn == "" {
+			v.add("parameters.reference_column", "reference_column is required for referential_integrity checks")
+		}
+	case TypeReconciliation:
+		if params.Reconciliation == nil || params.Reconciliation.TargetTable == "" {
+			v.add("parameters.reconciliation.target_table", "target_table is required for reconciliation checks")
+		}
+		if config := params.Reconciliation; config != nil {
+			if err := view.ValidateFilters(config.TargetFilters); err != nil {
+				v.add("parameters.reconciliation.target_filters", "%v", err)
+			}
+			if err := datasource.ValidatePredicate(config.TargetWhere); err != nil {
+				v.add("parameters.reconciliation.target_where", "%v", err)
+			}
+		}
+	case TypeTableDiff:
+		if params.TableDiff == nil || params.TableDiff.TargetTable == "" {
+			v.add("parameters.table_diff.target_table", "target_table is required for table_diff checks")
+		}
+		if params.TableDiff == nil || params.TableDiff.KeyColumn == "" {
+			v.add("parameters.table_diff.key_column", "key_column is required for table_diff checks")
+		}
+	case TypeColumnType:
+		if len(params.ExpectedSchema) == 0 {
+			v.add("parameters.expected_schema", "expected_schema is required for column_type checks")
+		}
+	}
+
+	if params.Sample != nil {
+		if !supportsSampling(check.Type) {
+			v.add("parameters.sample", "sampling is not supported for %s checks", check.Type)
+		} else if err := params.Sample.Validate(); err != nil {
+			v.add("parameters.sample", "%v", err)
+		}
+	}
+	if params.Incremental != nil {
+		if !supportsIncremental(check.Type) {
+			v.add("parameters.incremental", "incremental mode is not supported for %s checks", check.Type)
+		} else if params.Incremental.WatermarkColumn == "" {
+			v.add("parameters.incremental.watermark_column", "watermark_column is required for incremental checks")
+		}
+	}
+	if params.FailingRows != nil && !supportsFailingRows(check.Type) {
+		v.add("parameters.failing_rows", "failing row capture is not supported for %s checks", check.Type)
+	}
+}
+
+// validateThreshold checks a check's threshold type, operators and bounds
+func validateThreshold(v *ValidationError, check *Check) {
+	t := check.Threshold
+	switch t.Type {
+	case "", ThresholdAbsolute, ThresholdPercentage, ThresholdRange:
+	case ThresholdAnomaly:
+		if len(check.SegmentBy) > 0 {
+			v.add("threshold.type", "anomaly thresholds are not supported for segmented checks")
+		}
+		switch t.AnomalyMethod {
+		case "", AnomalyZScore, AnomalyMAD, AnomalySeasonal:
+		default:
+			v.add("threshold.anomaly_method", "unsupported anomaly method: %s", t.AnomalyMethod)
+		}
+		switch t.Seasonality {
+		case "", SeasonalityDaily, SeasonalityWeekly:
+		default:
+			v.add("threshold.seasonality", "unsupported seasonality: %s", t.Seasonality)
+		}
+		if t.Sensitivity < 0 {
+			v.add("threshold.sensitivity", "sensitivity must not be negative")
+		}
+		if t.WarmupRuns < 0 {
+			v.add("threshold.warmup_runs", "warmup_runs must not be negative")
+		}
+	default:
+		v.add("threshold.type", "unsupported threshold type: %s", t.Type)
+	}
+
+	validateCondition(v, "threshold", t.condition(), t.Type == ThresholdRange)
+	if t.Warn != nil {
+		if t.Warn.Operator == "" {
+			v.add("threshold.warn.operator", "operator is required for a warning level")
+		}
+		validateCondition(v, "threshold.warn", *t.Warn, false)
+	}
+}
+
+// validateCondition checks a condition's operator, and its bounds when it
+// compares to a range
+func validateCondition(v *ValidationError, field string, c Condition, bounded bool) {
+	switch c.Operator {
+	case "", OperatorEq, OperatorNe, OperatorLt, OperatorLte, OperatorGt, OperatorGte:
+	case OperatorBetween:
+		bounded = true
+	default:
+		v.add(field+".operator", "unsupported threshold operator: %s", c.Operator)
+	}
+	if bounded && c.MinValue > c.MaxValue {
+		v.add(field+".min_value", "min_value %g exceeds max_value %g", c.MinValue, c.MaxValue)
+	}
+}
+
+// validateRowSelection checks the filters, predicate and segmentation that
+// select and group the rows a check evaluates
+func validateRowSelection(v *ValidationError, check *Check) {
+	if len(check.Filters) > 0 || check.Where != "" {
+		if !supportsFilters(check.Type) {
+			v.add("filters", "row filters are not supported for %s checks", check.Type)
+		} else {
+			if err := view.ValidateFilters(check.Filters); err != nil {
+				v.add("filters", "%v", err)
+			}
+			if err := datasource.ValidatePredicate(check.Where); err != nil {
+				v.add("where", "%v", err)
+			}
+		}
+	}
+	if len(check.SegmentBy) > 0 && !supportsSegments(check.Type) {
+		v.add("segment_by", "segmentation is not supported for %s checks", check.Type)
+	}
+}
+
+// validateColumns checks that the table exists and has the columns the check
+// names, and type-checks an expression check's rule against them. Columns are
+// not checked when the connector cannot introspect the table, e.g. lakehouse
+// tables or a metadata outage; the run reports them instead.
+func validateColumns(ctx context.Context, v *ValidationError, check *Check, connector datasource.Connector) {
+	columns, err := connector.GetColumns(ctx, check.Table)
+	if err != nil {
+		return
+	}
+	if len(columns) == 0 {
+		v.add("table", "table %s not found", check.Table)
+		return
+	}
+
+	names := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		names[strings.ToLower(col.Name)] = true
+	}
+	require := func(field, column string) {
+		if column != "" && !names[strings.ToLower(column)] {
+			v.add(field, "column %s not found in %s", column, check.Table)
+		}
+	}
+	requireAll := func(field string, columns []string) {
+		for i, column := range columns {
+			require(fmt.Sprintf("%s[%d]", field, i), column)
+		}
+	}
+
+	params := check.Parameters
+	require("column", check.Column)
+	requireAll("parameters.unique_columns", params.UniqueColumns)
+	require("parameters.timestamp_column", params.TimestampColumn)
+	requireAll("segment_by", check.SegmentBy)
+	if params.Incremental != nil {
+		require("parameters.incremental.watermark_column", params.Incremental.WatermarkColumn)
+	}
+	if params.FailingRows != nil {
+		requireAll("parameters.failing_rows.columns", params.FailingRows.Columns)
+	}
+	if params.Sample != nil {
+		require("parameters.sample.key_column", params.Sample.KeyColumn)
+	}
+	if params.TableDiff != nil {
+		require("parameters.table_diff.key_column", params.TableDiff.KeyColumn)
+		requireAll("parameters.table_diff.columns", params.TableDiff.Columns)
+	}
+
+	if check.Type == TypeExpression && expr.Parse(params.Expression) == nil {
+		if _, err := expr.Compile(params.Expression, columns); err != nil {
+			v.add("parameters.expression", "%v", err)
+		}
+	}
+}
+
+// validateReconciliationTarget checks that a reconciliation's target table has
+// the columns it is compared, grouped and filtered on. Without target filters
+// the target is read with the check's own, so their columns are checked there
+// too; raw predicates are not. Like validateColumns, it skips targets whose
+// connector is unavailable or cannot introspect the table.
+func (m *Manager) validateReconciliationTarget(ctx context.Context, v *ValidationError, check *Check, connector datasource.Connector) {
+	config := check.Parameters.Reconciliation
+	if config == nil || config.TargetTable == "" {
+		return
+	}
+	target := connector
+	if config.TargetDatasourceID != "" && config.TargetDatasourceID != check.DatasourceID {
+		c, err := m.datasourceManager.GetConnector(ctx, config.TargetDatasourceID)
+		if err != nil {
+			return
+		}
+		target = c
+	}
+	columns, err := target.GetColumns(ctx, config.TargetTable)
+	if err != nil {
+		return
+	}
+	if len(columns) == 0 {
+		v.add("parameters.reconciliation.target_table", "table %s not found", config.TargetTable)
+		return
+	}
+
+	names := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		names[strings.ToLower(col.Name)] = true
+	}
+	require := func(field, column, hint string) {
+		if column != "" && !names[strings.ToLower(column)] {
+			v.add(field, "column %s not found in %s%s", column, config.TargetTable, hint)
+		}
+	}
+
+	for i, col := range config.Columns {
+		targetColumn := col.TargetColumn
+		if targetColumn == "" {
+			targetColumn = col.Column
+		}
+		require(fmt.Sprintf("parameters.reconciliation.columns[%d]", i), targetColumn, "")
+	}
+	for i, col := range config.GroupBy {
+		require(fmt.Sprintf("parameters.reconciliation.group_by[%d]", i), col, "")
+	}
+	if config.hasTargetFilter() {
+		for i, filter := range config.TargetFilters {
+			require(fmt.Sprintf("parameters.reconciliation.target_filters[%d].column", i), filter.Column, "")
+		}
+		return
+	}
+	for i, filter := range check.Filters {
+		require(fmt.Sprintf("filters[%d].column", i), filter.Column, "; set parameters.reconciliation.target_filters to filter the target")
+	}
+}
@@ -0,0 +1,228 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+	"github.com/vinod901/opendq-go/internal/view"
+)
+
+func TestValidateFields(t *testing.T) {
+	connector := &fakeConnector{dsType: datasource.TypePostgres, columns: orderColumns}
+	m := NewManager(datasource.NewManager())
+
+	testCases := []struct {
+		name     string
+		check    *Check
+		expected []string
+	}{
+		{
+			name:  "valid",
+			check: &Check{Type: TypeNullCheck, Table: "orders", Column: "price", SegmentBy: []string{"TIER"}},
+		},
+		{
+			name:     "missing type and table",
+			check:    &Check{},
+			expected: []string{"table", "type"},
+		},
+		{
+			name:     "unknown type",
+			check:    &Check{Type: "row_cnt", Table: "orders"},
+			expected: []string{"type"},
+		},
+		{
+			name: "bad regex, columns and threshold",
+			check: &Check{
+				Type:       TypeRegex,
+				Table:      "orders",
+				Column:     "sku",
+				SegmentBy:  []string{"tier", "region"},
+				Parameters: CheckParameters{Pattern: "[a-z"},
+				Threshold:  Threshold{Type: ThresholdPercentage, Operator: OperatorBetween, MinValue: 90, MaxValue: 10, Warn: &Condition{Operator: "above"}},
+			},
+			expected: []string{"column", "parameters.pattern", "segment_by[1]", "threshold.min_value", "threshold.warn.operator"},
+		},
+		{
+			name:     "undeclared expression column",
+			check:    &Check{Type: TypeExpression, Table: "orders", Parameters: CheckParameters{Expression: `total > 0.0`}},
+			expected: []string{"parameters.expression"},
+		},
+		{
+			name: "unsupported options",
+			check: &Check{
+				Type:           TypeSchemaMatch,
+				Table:          "orders",
+				Where:          "price > 0",
+				TimeoutSeconds: -1,
+				Parameters:     CheckParameters{Sample: &datasource.Sample{Percentage: 10}},
+				Threshold:      Threshold{Type: "learned"},
+			},
+			expected: []string{"filters", "parameters.sample", "threshold.type", "timeout_seconds"},
+		},
+		{
+			name: "seed the engine cannot repeat",
+			check: &Check{
+				Type:       TypeNullCheck,
+				Table:      "orders",
+				Column:     "price",
+				Parameters: CheckParameters{Sample: &datasource.Sample{RowCount: 100, Seed: 42}},
+			},
+			expected: []string{"parameters.sample"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := &ValidationError{}
+			m.validateFields(context.Background(), v, tc.check, connector)
+			var fields []string
+			for _, fe := range v.Errors {
+				fields = append(fields, fe.Field)
+			}
+			sort.Strings(fields)
+			if strings.Join(fields, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("expected errors for %v, got %+v", tc.expected, v.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateFields_MissingTable(t *testing.T) {
+	connector := &fakeConnector{dsType: datasource.TypePostgres}
+	v := &ValidationError{}
+	NewManager(datasource.NewManager()).validateFields(context.Background(), v, &Check{Type: TypeNullCheck, Table: "orderz", Column: "price"}, connector)
+	if len(v.Errors) != 1 || v.Errors[0].Field != "table" || v.Errors[0].Message != "table orderz not found" {
+		t.Errorf("expected only the table to be reported, got %+v", v.Errors)
+	}
+}
+
+func TestValidateFields_NoIntrospection(t *testing.T) {
+	connector := &fakeConnector{dsType: datasource.TypeDeltaLake, err: errors.New("schema introspection requires format-specific implementation")}
+	v := &ValidationError{}
+	NewManager(datasource.NewManager()).validateFields(context.Background(), v, &Check{Type: TypeNullCheck, Table: "events", Column: "user_id"}, connector)
+	if len(v.Errors) != 0 {
+		t.Errorf("expected columns to go unchecked, got %+v", v.Errors)
+	}
+}
+
+func TestValidateFields_QualifiedTable(t *testing.T) {
+	connector := &fakeConnector{tables: map[string][]datasource.ColumnInfo{
+		"sales.orders": {{Name: "id", DataType: "integer"}, {Name: "price", DataType: "numeric"}},
+	}}
+	m := NewManager(datasource.NewManager())
+
+	v := &ValidationError{}
+	m.validateFields(context.Background(), v, &Check{Type: TypeNullCheck, Table: "sales.orders", Column: "price"}, connector)
+	if len(v.Errors) != 0 {
+		t.Errorf("expected a schema-qualified table to validate, got %+v", v.Errors)
+	}
+
+	v = &ValidationError{}
+	m.validateFields(context.Background(), v, &Check{Type: TypeNullCheck, Table: "sales.orders", Column: "cost"}, connector)
+	if len(v.Errors) != 1 || v.Errors[0].Field != "column" {
+		t.Errorf("expected only the column to be reported, got %+v", v.Errors)
+	}
+}
+
+func TestValidateFields_ReconciliationTarget(t *testing.T) {
+	connector := &fakeConnector{tables: map[string][]datasource.ColumnInfo{
+		"src.orders": {{Name: "amount", DataType: "numeric"}, {Name: "region", DataType: "text"}},
+		"dst.orders": {{Name: "amount_usd", DataType: "numeric"}, {Name: "market", DataType: "text"}},
+	}}
+	m := NewManager(datasource.NewManager())
+	newCheck := func(config *ReconciliationConfig) *Check {
+		return &Check{
+			Type:       TypeReconciliation,
+			Table:      "src.orders",
+			Filters:    []view.FilterDef{{Column: "region", Operator: "eq", Value: "EU"}},
+			Parameters: CheckParameters{Reconciliation: config},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		config *ReconciliationConfig
+		fields []string
+	}{
+		{"source filter missing on target", &ReconciliationConfig{TargetTable: "dst.orders"}, []string{"filters[0].column"}},
+		{"target filter", &ReconciliationConfig{
+			TargetTable:   "dst.orders",
+			TargetFilters: []view.FilterDef{{Column: "market", Operator: "eq", Value: "EU"}},
+			Columns:       []ReconciliationColumn{{Column: "amount", TargetColumn: "amount_usd"}},
+		}, nil},
+		{"target columns", &ReconciliationConfig{
+			TargetTable: "dst.orders",
+			TargetWhere: "market = 'EU'",
+			Columns:     []ReconciliationColumn{{Column: "amount"}},
+			GroupBy:     []string{"region"},
+		}, []string{"parameters.reconciliation.columns[0]", "parameters.reconciliation.group_by[0]"}},
+		{"missing target table", &ReconciliationConfig{TargetTable: "dst.missing"}, []string{"parameters.reconciliation.target_table"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := &ValidationError{}
+			m.validateFields(context.Background(), v, newCheck(tc.config), connector)
+			var fields []string
+			for _, e := range v.Errors {
+				fields = append(fields, e.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tc.fields, ",") {
+				t.Errorf("expected errors on %v, got %+v", tc.fields, v.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateDefinition(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	_, err := m.validateDefinition(context.Background(), &Check{Type: TypeRowCount, DatasourceID: "missing", Table: "orders"})
+
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) || len(invalid.Errors) != 1 || invalid.Errors[0].Field != "datasource_id" {
+		t.Fatalf("expected a datasource_id field error, got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "invalid check: datasource_id: connector not found") {
+		t.Errorf("unexpected message: %s", err)
+	}
+}
+
+func TestCreateAndUpdateCheck_Validate(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(datasource.NewManager())
+
+	err := m.CreateCheck(ctx, &Check{
+		Type:       TypeRegex,
+		Table:      "orders",
+		Column:     "sku",
+		Parameters: CheckParameters{Pattern: "[a-z"},
+		Threshold:  Threshold{Operator: OperatorBetween, MinValue: 2, MaxValue: 1},
+	})
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) || len(invalid.Errors) != 2 {
+		t.Fatalf("expected pattern and threshold field errors, got %v", err)
+	}
+
+	check := &Check{ID: "nulls", Type: TypeNullCheck, Table: "orders", Column: "email"}
+	if err := m.CreateCheck(ctx, check); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = m.UpdateCheck(ctx, "nulls", map[string]interface{}{"threshold": Threshold{Type: "learned"}, "name": "renamed"})
+	if !errors.As(err, &invalid) || invalid.Errors[0].Field != "threshold.type" {
+		t.Fatalf("expected a threshold.type field error, got %v", err)
+	}
+	if check.Name == "renamed" || check.Threshold.Type != "" {
+		t.Errorf("expected a rejected update not to apply, got %+v", check)
+	}
+
+	err = m.CreateChecks(ctx, []*Check{
+		{Type: TypeRowCount, Table: "orders"},
+		{Type: TypeFormat, Table: "orders", Column: "email", Parameters: CheckParameters{Format: "mail"}},
+	})
+	if !errors.As(err, &invalid) || len(invalid.Errors) != 1 || invalid.Errors[0].Field != "[1].parameters.format" {
+		t.Errorf("expected the second check to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// VolumeMethod is how a volume check derives its expected row count from history
+type VolumeMethod string
+
+const (
+	VolumeTrailing     VolumeMethod = "trailing"       // Median of the most recent runs
+	VolumeSameWeekday  VolumeMethod = "same_weekday"   // Median of recent runs on the same weekday
+	VolumeTrend        VolumeMethod = "trend"          // Linear trend fitted to recent runs
+	VolumeDayOverDay   VolumeMethod = "day_over_day"   // The run closest to one day earlier
+	VolumeWeekOverWeek VolumeMethod = "week_over_week" // The run closest to one week earlier
+)
+
+const (
+	defaultVolumeTolerance = 20.0 // Percent
+	// minVolumeHistory is the number of prior runs needed before learned
+	// expectations are enforced
+	minVolumeHistory = 3
+)
+
+// defaultVolumeWindows is the number of prior runs each method looks at
+var defaultVolumeWindows = map[VolumeMethod]int{
+	VolumeTrailing:    7,
+	VolumeSameWeekday: 4,
+	VolumeTrend:       14,
+}
+
+// runVolumeCheck compares a table's row count to an expected volume learned
+// from the check's previous results. ExpectedVolume, when set, overrides the
+// learned expectation.
+func (m *Manager) runVolumeCheck(ctx context.Context, check *Check, connector datasource.Connector) (*CheckResult, error) {
+	params := check.Parameters
+	method := params.VolumeMethod
+	if method == "" {
+		method = VolumeTrailing
+	}
+	tolerance := params.VolumeTolerance
+	if tolerance == 0 {
+		tolerance = defaultVolumeTolerance
+	}
+
+	count, err := rowCount(ctx, check, connector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get row count: %w", err)
+	}
+
+	now := time.Now()
+	history := m.metricHistory(check.ID)
+
+	result := &CheckResult{
+		ActualValue: count,
+		Details: map[string]interface{}{
+			"row_count":      count,
+			"method":         method,
+			"tolerance":      tolerance,
+			"history_points": len(history),
+		},
+	}
+
+	var expected float64
+	var basis int
+	if params.ExpectedVolume > 0 {
+		expected, basis = float64(params.ExpectedVolume), 1
+		result.Details["method"] = "override"
+	} else {
+		expected, basis, err = expectedVolume(method, history, now, params.VolumeWindow)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if basis == 0 {
+		result.Status = StatusPassed
+		result.Message = fmt.Sprintf("row count %d recorded; not enough history to learn the expected volume", count)
+		result.Details["learning"] = true
+		return result, nil
+	}
+
+	lower := expected * (1 - tolerance/100)
+	upper := expected * (1 + tolerance/100)
+	var deviation float64
+	if expected > 0 {
+		deviation = (float64(count) - expected) / expected * 100
+	}
+	result.ExpectedValue = expected
+	result.Details["expected_volume"] = expected
+	result.Details["lower_bound"] = lower
+	result.Details["upper_bound"] = upper
+	result.Details["deviation_percentage"] = deviation
+	result.Details["basis_points"] = basis
+
+	// Percentage thresholds compare the count as a percentage of the expected volume
+	volume := metric{name: "row count", value: float64(count), reference: expected, percentName: "row count percentage of expected"}
+	if err := evaluateThreshold(result, check.Threshold, volume, Threshold{Type: ThresholdAbsolute, Operator: OperatorBetween, MinValue: lower, MaxValue: upper}); err != nil {
+		return nil, err
+	}
+	if result.Status != StatusPassed {
+		result.Message += fmt.Sprintf(" (%.1f%% from expected %.0f)", deviation, expected)
+	}
+
+	return result, nil
+}
+
+// expectedVolume derives the expected row count at now from history. It
+// returns the number of runs the expectation is based on, zero when there is
+// not enough history.
+func expectedVolume(method VolumeMethod, history []metricPoint, now time.Time, window int) (float64, int, error) {
+	switch method {
+	case VolumeDayOverDay:
+		return previousVolume(history, now, 24*time.Hour)
+	case VolumeWeekOverWeek:
+		return previousVolume(history, now, 7*24*time.Hour)
+	}
+
+	defaultWindow, ok := defaultVolumeWindows[method]
+	if !ok {
+		return 0, 0, fmt.Errorf("unsupported volume method: %s", method)
+	}
+	if window <= 0 {
+		window = defaultWindow
+	}
+
+	points := history
+	if method == VolumeSameWeekday {
+		points = nil
+		for _, p := range history {
+			if p.at.Weekday() == now.Weekday() {
+				points = append(points, p)
+			}
+		}
+	}
+	if len(points) > window {
+		points = points[len(points)-window:]
+	}
+
+	minimum := minVolumeHistory
+	if method == VolumeSameWeekday {
+		minimum = 2
+	}
+	if len(points) < minimum {
+		return 0, 0, nil
+	}
+
+	if method == VolumeTrend {
+		return linearTrend(points, now), len(points), nil
+	}
+	return medianVolume(points), len(points), nil
+}
+
+// previousVolume returns the count of the run closest to now minus period,
+// accepting runs within a quarter period of that time
+func previousVolume(history []metricPoint, now time.Time, period time.Duration) (float64, int, error) {
+	target := now.Add(-period)
+	best, found := metricPoint{}, false
+	for _, p := range history {
+		if diff := absDuration(p.at.Sub(target)); diff <= period/4 && (!found || diff < absDuration(best.at.Sub(target))) {
+			best, found = p, true
+		}
+	}
+	if !found {
+		return 0, 0, nil
+	}
+	return best.value, 1, nil
+}
+
+// medianVolume is robust to the occasional failed or partial load in history
+func medianVolume(points []metricPoint) float64 {
+	counts := make([]float64, len(points))
+	for i, p := range points {
+		counts[i] = p.value
+	}
+	return median(counts)
+}
+
+// linearTrend fits a least-squares line to the points and extrapolates it to now
+func linearTrend(points []metricPoint, now time.Time) float64 {
+	origin := points[0].at
+	var sumX, sumY, sumXY, sumXX float64
+	for _, p := range points {
+		x := p.at.Sub(origin).Hours()
+		sumX += x
+		sumY += p.value
+		sumXY += x * p.value
+		sumXX += x * x
+	}
+	n := float64(len(points))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return sumY / n
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+	return math.Max(0, intercept+slope*now.Sub(origin).Hours())
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
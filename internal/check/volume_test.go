@@ -0,0 +1,121 @@
+package check
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// seedVolumeHistory stores prior volume results for a check, counts[i] being
+// observed len(counts)-i days ago
+func seedVolumeHistory(m *Manager, checkID string, now time.Time, counts ...int64) {
+	for i, count := range counts {
+		m.results[checkID] = append(m.results[checkID], &CheckResult{
+			CheckID:     checkID,
+			Status:      StatusPassed,
+			ActualValue: count,
+			Timestamp:   now.AddDate(0, 0, -(len(counts) - i)),
+		})
+	}
+}
+
+func runVolume(t *testing.T, m *Manager, check *Check, count int64) *CheckResult {
+	t.Helper()
+	connector := &fakeConnector{rows: []map[string]interface{}{{"count": count}}}
+	result, err := m.executeCheck(context.Background(), check, connector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return result
+}
+
+func TestRunVolumeCheck_Learning(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	check := &Check{ID: "vol", Type: TypeVolume, Table: "events"}
+	seedVolumeHistory(m, "vol", time.Now(), 1000)
+
+	result := runVolume(t, m, check, 10)
+	if result.Status != StatusPassed || result.Details["learning"] != true {
+		t.Errorf("expected learning pass with little history, got %s: %v", result.Status, result.Details)
+	}
+}
+
+func TestRunVolumeCheck_Trailing(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	check := &Check{ID: "vol", Type: TypeVolume, Table: "events"}
+	// A failed load in history does not move the median
+	seedVolumeHistory(m, "vol", time.Now(), 1000, 1100, 0, 900, 1000)
+
+	if result := runVolume(t, m, check, 1050); result.Status != StatusPassed {
+		t.Errorf("expected pass, got %s: %s", result.Status, result.Message)
+	}
+	result := runVolume(t, m, check, 500)
+	if result.Status != StatusFailed || result.ExpectedValue != 1000.0 {
+		t.Errorf("expected failure against 1000, got %s: %v", result.Status, result.ExpectedValue)
+	}
+}
+
+func TestRunVolumeCheck_Override(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	check := &Check{ID: "vol", Type: TypeVolume, Table: "events", Parameters: CheckParameters{ExpectedVolume: 500, VolumeTolerance: 10}}
+
+	if result := runVolume(t, m, check, 540); result.Status != StatusPassed {
+		t.Errorf("expected pass, got %s: %s", result.Status, result.Message)
+	}
+	if result := runVolume(t, m, check, 560); result.Status != StatusFailed {
+		t.Errorf("expected failure, got %s: %s", result.Status, result.Message)
+	}
+}
+
+func TestExpectedVolume(t *testing.T) {
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC) // A Monday
+	var history []metricPoint
+	for day := 21; day >= 1; day-- {
+		at := now.AddDate(0, 0, -day)
+		count := 1000.0
+		if at.Weekday() == time.Monday {
+			count = 5000
+		}
+		history = append(history, metricPoint{at: at, value: count})
+	}
+
+	testCases := []struct {
+		method   VolumeMethod
+		expected float64
+	}{
+		{VolumeTrailing, 1000},
+		{VolumeSameWeekday, 5000},
+		{VolumeDayOverDay, 1000},
+		{VolumeWeekOverWeek, 5000},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.method), func(t *testing.T) {
+			got, basis, err := expectedVolume(tc.method, history, now, 0)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if basis == 0 || got != tc.expected {
+				t.Errorf("expected %v, got %v (basis %d)", tc.expected, got, basis)
+			}
+		})
+	}
+
+	if _, _, err := expectedVolume("seasonal", history, now, 0); err == nil {
+		t.Error("expected error for unsupported method")
+	}
+}
+
+func TestLinearTrend(t *testing.T) {
+	now := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	var points []metricPoint
+	for day := 5; day >= 1; day-- {
+		points = append(points, metricPoint{at: now.AddDate(0, 0, -day), value: float64(1000 + (5-day)*100)})
+	}
+
+	if got := linearTrend(points, now); math.Abs(got-1500) > 1e-6 {
+		t.Errorf("expected trend to extrapolate to 1500, got %v", got)
+	}
+}
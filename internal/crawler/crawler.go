@@ -0,0 +1,403 @@
+// Package crawler periodically snapshots datasource schemas and detects
+// schema drift between consecutive snapshots.
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// ChangeType represents the kind of schema change
+type ChangeType string
+
+const (
+	ChangeTableAdded         ChangeType = "table_added"
+	ChangeTableRemoved       ChangeType = "table_removed"
+	ChangeColumnAdded        ChangeType = "column_added"
+	ChangeColumnRemoved      ChangeType = "column_removed"
+	ChangeColumnRetyped      ChangeType = "column_retyped"
+	ChangeNullabilityChanged ChangeType = "nullability_changed"
+)
+
+// TableSchema is the captured schema of a single table
+type TableSchema struct {
+	Schema  string                  `json:"schema,omitempty"`
+	Name    string                  `json:"name"`
+	Columns []datasource.ColumnInfo `json:"columns"`
+}
+
+// Snapshot is a versioned capture of every table schema in a datasource
+type Snapshot struct {
+	ID           string                 `json:"id"`
+	DatasourceID string                 `json:"datasource_id"`
+	TenantID     string                 `json:"tenant_id"`
+	Version      int                    `json:"version"`
+	Tables       map[string]TableSchema `json:"tables"`           // Keyed by qualified table name
+	Errors       map[string]string      `json:"errors,omitempty"` // Tables whose columns could not be read
+	CapturedAt   time.Time              `json:"captured_at"`
+	CheckedAt    time.Time              `json:"checked_at"` // Last crawl that found this schema unchanged
+}
+
+// Change is a single difference between two snapshots
+type Change struct {
+	Type        ChangeType `json:"type"`
+	Table       string     `json:"table"`
+	Column      string     `json:"column,omitempty"`
+	OldType     string     `json:"old_type,omitempty"`
+	NewType     string     `json:"new_type,omitempty"`
+	OldNullable *bool      `json:"old_nullable,omitempty"`
+	NewNullable *bool      `json:"new_nullable,omitempty"`
+}
+
+// Breaking reports whether a change can break queries against the table
+func (c Change) Breaking() bool {
+	switch c.Type {
+	case ChangeTableRemoved, ChangeColumnRemoved, ChangeColumnRetyped:
+		return true
+	case ChangeNullabilityChanged:
+		return c.NewNullable != nil && *c.NewNullable
+	default:
+		return false
+	}
+}
+
+// DriftEvent describes the schema changes found between two snapshot versions
+type DriftEvent struct {
+	ID           string    `json:"id"`
+	DatasourceID string    `json:"datasource_id"`
+	TenantID     string    `json:"tenant_id"`
+	FromVersion  int       `json:"from_version"`
+	ToVersion    int       `json:"to_version"`
+	Changes      []Change  `json:"changes"`
+	Breaking     bool      `json:"breaking"`
+	DetectedAt   time.Time `json:"detected_at"`
+}
+
+// Summary returns a one-line description of the event's changes
+func (e *DriftEvent) Summary() string {
+	counts := make(map[ChangeType]int)
+	for _, change := range e.Changes {
+		counts[change.Type]++
+	}
+	var parts []string
+	for _, changeType := range []ChangeType{
+		ChangeTableAdded, ChangeTableRemoved, ChangeColumnAdded,
+		ChangeColumnRemoved, ChangeColumnRetyped, ChangeNullabilityChanged,
+	} {
+		if n := counts[changeType]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, strings.ReplaceAll(string(changeType), "_", " ")))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// DriftHandler is called for every drift event the crawler detects
+type DriftHandler func(ctx context.Context, event *DriftEvent)
+
+// Manager crawls datasource schemas and stores snapshot versions
+type Manager struct {
+	datasourceManager *datasource.Manager
+	interval          time.Duration
+	snapshots         map[string][]*Snapshot   // Keyed by datasource ID, oldest first
+	events            map[string][]*DriftEvent // Keyed by datasource ID, oldest first
+	handlers          []DriftHandler
+	mu                sync.RWMutex
+	stopChan          chan struct{}
+}
+
+// NewManager creates a new crawler that snapshots every datasource each interval
+func NewManager(dsManager *datasource.Manager, interval time.Duration) *Manager {
+	return &Manager{
+		datasourceManager: dsManager,
+		interval:          interval,
+		snapshots:         make(map[string][]*Snapshot),
+		events:            make(map[string][]*DriftEvent),
+		stopChan:          make(chan struct{}),
+	}
+}
+
+// OnDrift registers a handler for drift events
+func (m *Manager) OnDrift(handler DriftHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers = append(m.handlers, handler)
+}
+
+// Start starts crawling all datasources in the background
+func (m *Manager) Start(ctx context.Context) error {
+	if m.interval <= 0 {
+		return fmt.Errorf("crawl interval must be positive")
+	}
+	go m.crawlLoop(ctx)
+	return nil
+}
+
+// Stop stops the background crawl
+func (m *Manager) Stop() {
+	close(m.stopChan)
+}
+
+func (m *Manager) crawlLoop(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.CrawlAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CrawlAll snapshots every active datasource. Failures are logged and do not
+// stop the remaining datasources from being crawled.
+func (m *Manager) CrawlAll(ctx context.Context) {
+	datasources, err := m.datasourceManager.ListDatasources(ctx, "")
+	if err != nil {
+		log.Printf("crawler: failed to list datasources: %v", err)
+		return
+	}
+
+	for _, ds := range datasources {
+		if !ds.Active {
+			continue
+		}
+		if _, _, err := m.Crawl(ctx, ds.ID); err != nil {
+			log.Printf("crawler: failed to crawl datasource %s: %v", ds.ID, err)
+		}
+	}
+}
+
+// Crawl captures the current schema of a datasource. A new snapshot version is
+// stored only when the schema differs from the latest one, in which case the
+// returned drift event is non-nil and published to the registered handlers.
+func (m *Manager) Crawl(ctx context.Context, datasourceID string) (*Snapshot, *DriftEvent, error) {
+	ds, err := m.datasourceManager.GetDatasource(ctx, datasourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	connector, err := m.datasourceManager.GetConnector(ctx, datasourceID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get datasource connector: %w", err)
+	}
+
+	tables, err := connector.GetTables(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	latest := m.latestSnapshot(datasourceID)
+	now := time.Now()
+	snapshot := &Snapshot{
+		ID:           uuid.New().String(),
+		DatasourceID: datasourceID,
+		TenantID:     ds.TenantID,
+		CapturedAt:   now,
+		CheckedAt:    now,
+	}
+	snapshot.Tables, snapshot.Errors = captureTables(ctx, connector, tables, latest)
+
+	m.mu.Lock()
+	if snapshots := m.snapshots[datasourceID]; len(snapshots) > 0 {
+		latest = snapshots[len(snapshots)-1]
+	}
+	if latest != nil {
+		changes := Diff(latest, snapshot)
+		if len(changes) == 0 {
+			latest.CheckedAt = now
+			latest.Errors = snapshot.Errors
+			m.mu.Unlock()
+			return latest, nil, nil
+		}
+		snapshot.Version = latest.Version + 1
+		m.snapshots[datasourceID] = append(m.snapshots[datasourceID], snapshot)
+
+		event := &DriftEvent{
+			ID:           uuid.New().String(),
+			DatasourceID: datasourceID,
+			TenantID:     ds.TenantID,
+			FromVersion:  latest.Version,
+			ToVersion:    snapshot.Version,
+			Changes:      changes,
+			DetectedAt:   now,
+		}
+		for _, change := range changes {
+			if change.Breaking() {
+				event.Breaking = true
+				break
+			}
+		}
+		m.events[datasourceID] = append(m.events[datasourceID], event)
+		handlers := append([]DriftHandler(nil), m.handlers...)
+		m.mu.Unlock()
+
+		for _, handler := range handlers {
+			handler(ctx, event)
+		}
+		return snapshot, event, nil
+	}
+
+	// First crawl establishes the baseline
+	snapshot.Version = 1
+	m.snapshots[datasourceID] = append(m.snapshots[datasourceID], snapshot)
+	m.mu.Unlock()
+	return snapshot, nil, nil
+}
+
+// captureTables reads the columns of every table, keyed by qualified name.
+// Columns are looked up by qualified name too, so that same-named tables in
+// different schemas are captured separately. Tables whose columns cannot be
+// read are returned as errors, and keep their schema from the latest snapshot
+// so a transient failure is not reported as every column being removed.
+func captureTables(ctx context.Context, connector datasource.Connector, tables []datasource.TableInfo, latest *Snapshot) (map[string]TableSchema, map[string]string) {
+	captured := make(map[string]TableSchema, len(tables))
+	var errs map[string]string
+	for _, table := range tables {
+		key := qualifiedName(table.Schema, table.Name)
+		columns, err := connector.GetColumns(ctx, key)
+		if err != nil {
+			if errs == nil {
+				errs = make(map[string]string)
+			}
+			errs[key] = err.Error()
+			if latest != nil {
+				if previous, ok := latest.Tables[key]; ok {
+					captured[key] = previous
+				}
+			}
+			continue
+		}
+		captured[key] = TableSchema{Schema: table.Schema, Name: table.Name, Columns: columns}
+	}
+	return captured, errs
+}
+
+// ListSnapshots returns the snapshot versions of a datasource, oldest first
+func (m *Manager) ListSnapshots(ctx context.Context, datasourceID string) ([]*Snapshot, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]*Snapshot(nil), m.snapshots[datasourceID]...), nil
+}
+
+// GetSnapshot returns a specific snapshot version of a datasource
+func (m *Manager) GetSnapshot(ctx context.Context, datasourceID string, version int) (*Snapshot, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, snapshot := range m.snapshots[datasourceID] {
+		if snapshot.Version == version {
+			return snapshot, nil
+		}
+	}
+	return nil, fmt.Errorf("snapshot version %d not found for datasource: %s", version, datasourceID)
+}
+
+// ListDriftEvents returns drift events for a datasource, oldest first
+func (m *Manager) ListDriftEvents(ctx context.Context, datasourceID string) ([]*DriftEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]*DriftEvent(nil), m.events[datasourceID]...), nil
+}
+
+func (m *Manager) latestSnapshot(datasourceID string) *Snapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	snapshots := m.snapshots[datasourceID]
+	if len(snapshots) == 0 {
+		return nil
+	}
+	return snapshots[len(snapshots)-1]
+}
+
+// Diff returns the changes from one snapshot to the next, ordered by table and column
+func Diff(from, to *Snapshot) []Change {
+	var changes []Change
+
+	for key, oldTable := range from.Tables {
+		newTable, ok := to.Tables[key]
+		if !ok {
+			changes = append(changes, Change{Type: ChangeTableRemoved, Table: key})
+			continue
+		}
+		changes = append(changes, diffColumns(key, oldTable.Columns, newTable.Columns)...)
+	}
+	for key := range to.Tables {
+		if _, ok := from.Tables[key]; !ok {
+			changes = append(changes, Change{Type: ChangeTableAdded, Table: key})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Table != changes[j].Table {
+			return changes[i].Table < changes[j].Table
+		}
+		return changes[i].Column < changes[j].Column
+	})
+	return changes
+}
+
+func diffColumns(table string, from, to []datasource.ColumnInfo) []Change {
+	var changes []Change
+
+	newColumns := make(map[string]datasource.ColumnInfo, len(to))
+	for _, col := range to {
+		newColumns[col.Name] = col
+	}
+	oldColumns := make(map[string]datasource.ColumnInfo, len(from))
+	for _, col := range from {
+		oldColumns[col.Name] = col
+	}
+
+	for _, oldCol := range from {
+		newCol, ok := newColumns[oldCol.Name]
+		if !ok {
+			changes = append(changes, Change{Type: ChangeColumnRemoved, Table: table, Column: oldCol.Name, OldType: oldCol.DataType})
+			continue
+		}
+		if oldCol.DataType != newCol.DataType {
+			changes = append(changes, Change{
+				Type:    ChangeColumnRetyped,
+				Table:   table,
+				Column:  oldCol.Name,
+				OldType: oldCol.DataType,
+				NewType: newCol.DataType,
+			})
+		}
+		if oldCol.Nullable != newCol.Nullable {
+			oldNullable, newNullable := oldCol.Nullable, newCol.Nullable
+			changes = append(changes, Change{
+				Type:        ChangeNullabilityChanged,
+				Table:       table,
+				Column:      oldCol.Name,
+				OldNullable: &oldNullable,
+				NewNullable: &newNullable,
+			})
+		}
+	}
+	for _, newCol := range to {
+		if _, ok := oldColumns[newCol.Name]; !ok {
+			changes = append(changes, Change{Type: ChangeColumnAdded, Table: table, Column: newCol.Name, NewType: newCol.DataType})
+		}
+	}
+
+	return changes
+}
+
+func qualifiedName(schema, name string) string {
+	if schema == "" {
+		return name
+	}
+	return schema + "." + name
+}
@@ -0,0 +1,192 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+func snapshotOf(tables map[string][]datasource.ColumnInfo) *Snapshot {
+	snapshot := &Snapshot{Tables: make(map[string]TableSchema)}
+	for name, columns := range tables {
+		snapshot.Tables[name] = TableSchema{Name: name, Columns: columns}
+	}
+	return snapshot
+}
+
+func TestDiff(t *testing.T) {
+	from := snapshotOf(map[string][]datasource.ColumnInfo{
+		"public.users": {
+			{Name: "id", DataType: "integer"},
+			{Name: "email", DataType: "text", Nullable: false},
+			{Name: "age", DataType: "integer", Nullable: true},
+			{Name: "legacy", DataType: "text", Nullable: true},
+		},
+		"public.sessions": {{Name: "id", DataType: "integer"}},
+	})
+	to := snapshotOf(map[string][]datasource.ColumnInfo{
+		"public.users": {
+			{Name: "id", DataType: "bigint"},
+			{Name: "email", DataType: "text", Nullable: true},
+			{Name: "age", DataType: "integer", Nullable: true},
+			{Name: "created_at", DataType: "timestamp", Nullable: true},
+		},
+		"public.orders": {{Name: "id", DataType: "integer"}},
+	})
+
+	changes := Diff(from, to)
+
+	expected := []struct {
+		changeType ChangeType
+		table      string
+		column     string
+	}{
+		{ChangeTableAdded, "public.orders", ""},
+		{ChangeTableRemoved, "public.sessions", ""},
+		{ChangeColumnAdded, "public.users", "created_at"},
+		{ChangeNullabilityChanged, "public.users", "email"},
+		{ChangeColumnRetyped, "public.users", "id"},
+		{ChangeColumnRemoved, "public.users", "legacy"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %d: %+v", len(expected), len(changes), changes)
+	}
+	for i, e := range expected {
+		c := changes[i]
+		if c.Type != e.changeType || c.Table != e.table || c.Column != e.column {
+			t.Errorf("change %d: expected %s %s.%s, got %s %s.%s", i, e.changeType, e.table, e.column, c.Type, c.Table, c.Column)
+		}
+	}
+
+	retyped := changes[4]
+	if retyped.OldType != "integer" || retyped.NewType != "bigint" {
+		t.Errorf("expected integer -> bigint, got %s -> %s", retyped.OldType, retyped.NewType)
+	}
+	nullability := changes[3]
+	if *nullability.OldNullable || !*nullability.NewNullable {
+		t.Error("expected email to become nullable")
+	}
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	columns := map[string][]datasource.ColumnInfo{
+		"users": {{Name: "id", DataType: "integer"}},
+	}
+	if changes := Diff(snapshotOf(columns), snapshotOf(columns)); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestChange_Breaking(t *testing.T) {
+	yes, no := true, false
+	testCases := []struct {
+		name     string
+		change   Change
+		breaking bool
+	}{
+		{"column added", Change{Type: ChangeColumnAdded}, false},
+		{"table added", Change{Type: ChangeTableAdded}, false},
+		{"column removed", Change{Type: ChangeColumnRemoved}, true},
+		{"column retyped", Change{Type: ChangeColumnRetyped}, true},
+		{"became nullable", Change{Type: ChangeNullabilityChanged, OldNullable: &no, NewNullable: &yes}, true},
+		{"became not null", Change{Type: ChangeNullabilityChanged, OldNullable: &yes, NewNullable: &no}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.change.Breaking() != tc.breaking {
+				t.Errorf("expected breaking=%v", tc.breaking)
+			}
+		})
+	}
+}
+
+func TestDriftEvent_Summary(t *testing.T) {
+	event := &DriftEvent{Changes: []Change{
+		{Type: ChangeColumnAdded},
+		{Type: ChangeColumnAdded},
+		{Type: ChangeColumnRetyped},
+	}}
+	if got := event.Summary(); got != "2 column added, 1 column retyped" {
+		t.Errorf("unexpected summary: %q", got)
+	}
+}
+
+func TestManager_Crawl_NotFound(t *testing.T) {
+	m := NewManager(datasource.NewManager(), time.Hour)
+	if _, _, err := m.Crawl(context.Background(), "nonexistent"); err == nil {
+		t.Fatal("expected error for nonexistent datasource")
+	}
+}
+
+// columnsConnector answers GetColumns from per-table columns; tables it does
+// not know fail
+type columnsConnector struct {
+	datasource.Connector
+	tables map[string][]datasource.ColumnInfo
+}
+
+func (c *columnsConnector) GetColumns(ctx context.Context, table string) ([]datasource.ColumnInfo, error) {
+	if columns, ok := c.tables[table]; ok {
+		return columns, nil
+	}
+	return nil, errors.New("connection reset")
+}
+
+func TestCaptureTables(t *testing.T) {
+	connector := &columnsConnector{tables: map[string][]datasource.ColumnInfo{
+		"sales.orders":   {{Name: "id", DataType: "integer"}, {Name: "total", DataType: "numeric"}},
+		"archive.orders": {{Name: "id", DataType: "integer"}},
+	}}
+	latest := snapshotOf(map[string][]datasource.ColumnInfo{
+		"sales.customers": {{Name: "id", DataType: "integer"}},
+	})
+
+	tables, errs := captureTables(context.Background(), connector, []datasource.TableInfo{
+		{Schema: "sales", Name: "orders"},
+		{Schema: "archive", Name: "orders"},
+		{Schema: "sales", Name: "customers"},
+	}, latest)
+
+	if got := len(tables["sales.orders"].Columns); got != 2 {
+		t.Errorf("expected 2 columns in sales.orders, got %d", got)
+	}
+	if got := len(tables["archive.orders"].Columns); got != 1 {
+		t.Errorf("expected 1 column in archive.orders, got %d", got)
+	}
+	if len(errs) != 1 || errs["sales.customers"] == "" {
+		t.Errorf("expected only sales.customers to fail, got %v", errs)
+	}
+	if got := len(tables["sales.customers"].Columns); got != 1 {
+		t.Errorf("expected the failed table to keep its last known schema, got %d columns", got)
+	}
+}
+
+func TestManager_CrawlAll_ConcurrentUpdates(t *testing.T) {
+	ctx := context.Background()
+	dsManager := datasource.NewManager()
+	m := NewManager(dsManager, time.Hour)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			ds := &datasource.Datasource{ID: fmt.Sprintf("ds-%d", i), Type: datasource.TypeS3}
+			if err := dsManager.CreateDatasource(ctx, ds); err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			dsManager.UpdateDatasource(ctx, ds.ID, map[string]interface{}{"active": i%2 == 0})
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		m.CrawlAll(ctx)
+	}
+	wg.Wait()
+}
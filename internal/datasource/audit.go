@@ -0,0 +1,268 @@
+package datasource
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OriginType identifies what kind of component issued a query
+type OriginType string
+
+const (
+	OriginCheck   OriginType = "check"
+	OriginView    OriginType = "view"
+	OriginUser    OriginType = "user"
+	OriginProfile OriginType = "profile"
+	OriginDiff    OriginType = "diff"
+	OriginSystem  OriginType = "system"
+)
+
+// defaultQueryLogSize is the number of audit entries retained in memory
+const defaultQueryLogSize = 10000
+
+// minRedactedSecretLength is the shortest configured secret that is redacted
+// verbatim from SQL text; shorter values would mangle unrelated identifiers
+const minRedactedSecretLength = 4
+
+// QueryOrigin identifies the check, view or user that caused a query
+type QueryOrigin struct {
+	Type   OriginType `json:"type"`
+	ID     string     `json:"id,omitempty"` // Check or view ID
+	UserID string     `json:"user_id,omitempty"`
+}
+
+type queryOriginKey struct{}
+
+// WithQueryOrigin returns a context that attributes queries to origin. The
+// user of an enclosing origin is kept when origin does not name one, so a
+// check run triggered from the API is attributed to both the check and the user.
+func WithQueryOrigin(ctx context.Context, origin QueryOrigin) context.Context {
+	if origin.UserID == "" {
+		origin.UserID = QueryOriginFromContext(ctx).UserID
+	}
+	return context.WithValue(ctx, queryOriginKey{}, origin)
+}
+
+// QueryOriginFromContext returns the query origin stored in ctx, defaulting to system
+func QueryOriginFromContext(ctx context.Context) QueryOrigin {
+	if origin, ok := ctx.Value(queryOriginKey{}).(QueryOrigin); ok {
+		return origin
+	}
+	return QueryOrigin{Type: OriginSystem}
+}
+
+// QueryLogEntry records a single statement sent to a datasource
+type QueryLogEntry struct {
+	ID           string        `json:"id"`
+	TenantID     string        `json:"tenant_id"`
+	DatasourceID string        `json:"datasource_id"`
+	Origin       QueryOrigin   `json:"origin"`
+	SQL          string        `json:"sql"` // Secrets redacted
+	StartedAt    time.Time     `json:"started_at"`
+	Duration     time.Duration `json:"duration"`
+	RowsReturned int64         `json:"rows_returned"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// QueryLogFilter selects entries from the query log. Zero values match everything.
+type QueryLogFilter struct {
+	DatasourceID string
+	Since        time.Time
+	Until        time.Time
+	OriginType   OriginType
+	OriginID     string
+	UserID       string
+	Limit        int
+}
+
+// QueryLog is a bounded in-memory audit log of datasource queries
+type QueryLog struct {
+	mu         sync.RWMutex
+	entries    []*QueryLogEntry
+	maxEntries int
+}
+
+// NewQueryLog creates a query log retaining at most maxEntries entries
+func NewQueryLog(maxEntries int) *QueryLog {
+	if maxEntries <= 0 {
+		maxEntries = defaultQueryLogSize
+	}
+	return &QueryLog{maxEntries: maxEntries}
+}
+
+// Record appends an entry, evicting the oldest entries once the log is full
+func (l *QueryLog) Record(entry *QueryLogEntry) {
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, entry)
+	if overflow := len(l.entries) - l.maxEntries; overflow > 0 {
+		l.entries = append(l.entries[:0:0], l.entries[overflow:]...)
+	}
+}
+
+// List returns the entries matching filter, newest first
+func (l *QueryLog) List(filter QueryLogFilter) []*QueryLogEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var result []*QueryLogEntry
+	for _, entry := range l.entries {
+		if filter.DatasourceID != "" && entry.DatasourceID != filter.DatasourceID {
+			continue
+		}
+		if !filter.Since.IsZero() && entry.StartedAt.Before(filter.Since) {
+			continue
+		}
+		if !filter.Until.IsZero() && entry.StartedAt.After(filter.Until) {
+			continue
+		}
+		if filter.OriginType != "" && entry.Origin.Type != filter.OriginType {
+			continue
+		}
+		if filter.OriginID != "" && entry.Origin.ID != filter.OriginID {
+			continue
+		}
+		if filter.UserID != "" && entry.Origin.UserID != filter.UserID {
+			continue
+		}
+		result = append(result, entry)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].StartedAt.After(result[j].StartedAt)
+	})
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result
+}
+
+// secretPatterns match credentials embedded in SQL literals, e.g.
+// CREATE USER ... PASSWORD 'x', IDENTIFIED BY 'x', CREDENTIALS=(AWS_SECRET_KEY='x')
+// and user:password@ connection URLs
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b((?:password|passwd|pwd|secret|secret_key|access_key|access_key_id|aws_key_id|aws_secret_key|aws_token|azure_sas_token|token|api_key|private_key|key_id)\s*(?:=>|=|:)?\s*)'(?:[^']|'')*'`),
+	regexp.MustCompile(`(?i)\b(identified\s+by\s+)(?:'(?:[^']|'')*'|"[^"]*"|[^\s;]+)`),
+	regexp.MustCompile(`(://[^:/@\s'"]+:)[^@\s'"]+(@)`),
+}
+
+// RedactSQL removes secrets from SQL text. Every occurrence of the given
+// secret values is replaced, as are credential literals matched by pattern.
+func RedactSQL(sql string, secrets ...string) string {
+	for _, secret := range secrets {
+		if len(secret) >= minRedactedSecretLength {
+			sql = strings.ReplaceAll(sql, secret, "[REDACTED]")
+		}
+	}
+	sql = secretPatterns[0].ReplaceAllString(sql, "${1}'[REDACTED]'")
+	sql = secretPatterns[1].ReplaceAllString(sql, "${1}'[REDACTED]'")
+	sql = secretPatterns[2].ReplaceAllString(sql, "${1}[REDACTED]${2}")
+	return sql
+}
+
+// secrets returns the credential values configured for a connection
+func (c ConnectionConfig) secrets() []string {
+	secrets := []string{c.Password, c.Token, c.PrivateKey, c.SecretKey, c.AccessKey}
+	if c.ConnectionURL != "" {
+		// Passwords embedded in the URL are caught by the URL pattern; the full URL
+		// is redacted as well in case it is echoed into a statement.
+		secrets = append(secrets, c.ConnectionURL)
+	}
+	return secrets
+}
+
+type queryRecorderKey struct{}
+
+// queryRecorder records a statement executed on behalf of a wrapped connector
+type queryRecorder func(ctx context.Context, query string, startedAt time.Time, result *QueryResult, err error)
+
+// recordQuery passes an executed statement to the recorder installed in ctx,
+// if any. Connectors call it so metadata queries issued internally by
+// GetTables, GetColumns and the like are audited too.
+func recordQuery(ctx context.Context, query string, startedAt time.Time, result *QueryResult, err error) {
+	if record, ok := ctx.Value(queryRecorderKey{}).(queryRecorder); ok {
+		record(ctx, query, startedAt, result, err)
+	}
+}
+
+// auditedConnector wraps a connector and records every statement it sends
+type auditedConnector struct {
+	Connector
+	ds  *Datasource
+	log *QueryLog
+}
+
+func newAuditedConnector(connector Connector, ds *Datasource, log *QueryLog) *auditedConnector {
+	return &auditedConnector{Connector: connector, ds: ds, log: log}
+}
+
+// Query executes and records a query
+func (c *auditedConnector) Query(ctx context.Context, query string, args ...interface{}) (*QueryResult, error) {
+	startedAt := time.Now()
+	result, err := c.Connector.Query(ctx, query, args...)
+	c.record(ctx, query, startedAt, result, err)
+	return result, err
+}
+
+// GetTables returns tables, recording the metadata queries issued
+func (c *auditedConnector) GetTables(ctx context.Context) ([]TableInfo, error) {
+	return c.Connector.GetTables(c.recording(ctx))
+}
+
+// GetColumns returns columns, recording the metadata queries issued
+func (c *auditedConnector) GetColumns(ctx context.Context, table string) ([]ColumnInfo, error) {
+	return c.Connector.GetColumns(c.recording(ctx), table)
+}
+
+// GetRowCount returns the row count, recording the queries issued
+func (c *auditedConnector) GetRowCount(ctx context.Context, table string) (int64, error) {
+	return c.Connector.GetRowCount(c.recording(ctx), table)
+}
+
+// GetPartitions returns partitions, recording the metadata queries issued
+func (c *auditedConnector) GetPartitions(ctx context.Context, table string) ([]PartitionInfo, error) {
+	return c.Connector.GetPartitions(c.recording(ctx), table)
+}
+
+// EstimateCost forwards to the wrapped connector's estimator, recording the
+// EXPLAIN or dry-run statements it issues
+func (c *auditedConnector) EstimateCost(ctx context.Context, query string, args ...interface{}) (*CostEstimate, error) {
+	estimator, ok := c.Connector.(CostEstimator)
+	if !ok {
+		return nil, ErrCostEstimationUnsupported
+	}
+	return estimator.EstimateCost(c.recording(ctx), query, args...)
+}
+
+func (c *auditedConnector) recording(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryRecorderKey{}, queryRecorder(c.record))
+}
+
+func (c *auditedConnector) record(ctx context.Context, query string, startedAt time.Time, result *QueryResult, err error) {
+	entry := &QueryLogEntry{
+		TenantID:     c.ds.TenantID,
+		DatasourceID: c.ds.ID,
+		Origin:       QueryOriginFromContext(ctx),
+		SQL:          RedactSQL(query, c.ds.Connection.secrets()...),
+		StartedAt:    startedAt,
+		Duration:     time.Since(startedAt),
+	}
+	if result != nil {
+		entry.RowsReturned = result.RowCount
+	}
+	if err != nil {
+		entry.Error = RedactSQL(err.Error(), c.ds.Connection.secrets()...)
+	}
+	c.log.Record(entry)
+}
@@ -3,6 +3,8 @@ package datasource
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 )
 
 // SnowflakeConnector implements Connector for Snowflake
@@ -192,24 +194,89 @@ func (c *BigQueryConnector) GetTables(ctx context.Context) ([]TableInfo, error)
 		return nil, err
 	}
 
+	keyResult, err := c.Query(ctx, fmt.Sprintf(`
+		SELECT table_name, column_name
+		FROM %s.INFORMATION_SCHEMA.COLUMNS
+		WHERE is_partitioning_column = 'YES'`, c.config.Dataset))
+	if err != nil {
+		return nil, err
+	}
+	partitionKeys := make(map[string][]string)
+	for _, row := range keyResult.Rows {
+		name := fmt.Sprintf("%v", row["table_name"])
+		partitionKeys[name] = append(partitionKeys[name], fmt.Sprintf("%v", row["column_name"]))
+	}
+
 	var tables []TableInfo
 	for _, row := range result.Rows {
+		name := fmt.Sprintf("%v", row["table_name"])
 		tables = append(tables, TableInfo{
-			Schema: fmt.Sprintf("%v", row["table_schema"]),
-			Name:   fmt.Sprintf("%v", row["table_name"]),
-			Type:   fmt.Sprintf("%v", row["table_type"]),
+			Schema:        fmt.Sprintf("%v", row["table_schema"]),
+			Name:          name,
+			Type:          fmt.Sprintf("%v", row["table_type"]),
+			PartitionKeys: partitionKeys[name],
 		})
 	}
 	return tables, nil
 }
 
-// GetColumns returns columns for a BigQuery table
+// GetPartitions returns partitions of a BigQuery table from INFORMATION_SCHEMA.PARTITIONS.
+// Ingestion-time and time-unit partition IDs are reported as the partition column value.
+func (c *BigQueryConnector) GetPartitions(ctx context.Context, table string) ([]PartitionInfo, error) {
+	keyResult, err := c.Query(ctx, fmt.Sprintf(`
+		SELECT column_name
+		FROM %s.INFORMATION_SCHEMA.COLUMNS
+		WHERE table_name = '%s' AND is_partitioning_column = 'YES'`, c.config.Dataset, table))
+	if err != nil {
+		return nil, err
+	}
+	var partitionKey string
+	if len(keyResult.Rows) > 0 {
+		partitionKey = fmt.Sprintf("%v", keyResult.Rows[0]["column_name"])
+	}
+
+	query := fmt.Sprintf(`
+		SELECT partition_id, total_rows, total_logical_bytes, last_modified_time
+		FROM %s.INFORMATION_SCHEMA.PARTITIONS
+		WHERE table_name = '%s' AND partition_id != '__UNPARTITIONED__'
+		ORDER BY partition_id`, c.config.Dataset, table)
+
+	result, err := c.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	partitions := make([]PartitionInfo, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		partitionID := rowString(row["partition_id"])
+		partition := PartitionInfo{
+			Name:      partitionID,
+			RowCount:  rowInt64(row["total_rows"]),
+			SizeBytes: rowInt64(row["total_logical_bytes"]),
+		}
+		if partitionKey != "" {
+			partition.Values = map[string]string{partitionKey: partitionID}
+		}
+		if modified, ok := row["last_modified_time"].(time.Time); ok {
+			partition.LastModified = &modified
+		}
+		partitions = append(partitions, partition)
+	}
+	return partitions, nil
+}
+
+// GetColumns returns columns for a BigQuery table, named as dataset.table or
+// in the configured dataset
 func (c *BigQueryConnector) GetColumns(ctx context.Context, table string) ([]ColumnInfo, error) {
+	dataset, name := splitQualifiedName(strings.ReplaceAll(table, "`", ""))
+	if dataset == "" {
+		dataset = c.config.Dataset
+	}
 	query := fmt.Sprintf(`
 		SELECT column_name, data_type, is_nullable
 		FROM %s.INFORMATION_SCHEMA.COLUMNS
 		WHERE table_name = '%s'
-		ORDER BY ordinal_position`, c.config.Dataset, table)
+		ORDER BY ordinal_position`, dataset, name)
 
 	result, err := c.Query(ctx, query)
 	if err != nil {
@@ -445,13 +512,15 @@ func (c *ClickHouseConnector) GetTables(ctx context.Context) ([]TableInfo, error
 	return tables, nil
 }
 
-// GetColumns returns columns for a ClickHouse table
+// GetColumns returns columns for a ClickHouse table, named as database.table
+// or in the current database
 func (c *ClickHouseConnector) GetColumns(ctx context.Context, table string) ([]ColumnInfo, error) {
+	database, name := splitQualifiedName(strings.ReplaceAll(table, "`", ""))
 	query := fmt.Sprintf(`
 		SELECT name, type, default_kind, default_expression
 		FROM system.columns
-		WHERE table = '%s' AND database = currentDatabase()
-		ORDER BY position`, table)
+		WHERE table = '%s' AND database = if('%s' = '', currentDatabase(), '%s')
+		ORDER BY position`, name, database, database)
 
 	result, err := c.Query(ctx, query)
 	if err != nil {
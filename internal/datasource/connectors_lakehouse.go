@@ -85,6 +85,27 @@ func (c *LakehouseConnector) GetRowCount(ctx context.Context, table string) (int
 	return 0, nil
 }
 
+// GetPartitions returns partition metadata for a lakehouse table, aggregated
+// from file-level entries in the table format's metadata
+func (c *LakehouseConnector) GetPartitions(ctx context.Context, table string) ([]PartitionInfo, error) {
+	var files []partitionFile
+	var err error
+	switch c.dsType {
+	case TypeDeltaLake:
+		files, err = c.getDeltaFiles(ctx, table)
+	case TypeIceberg:
+		files, err = c.getIcebergFiles(ctx, table)
+	case TypeHudi, TypeHDFS:
+		files, err = c.getHivePathFiles(ctx, table)
+	default:
+		return nil, fmt.Errorf("unsupported lakehouse type: %s", c.dsType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return groupPartitionFiles(files), nil
+}
+
 // Type returns the datasource type
 func (c *LakehouseConnector) Type() Type {
 	return c.dsType
@@ -115,6 +136,30 @@ func (c *LakehouseConnector) getHDFSPaths(ctx context.Context) ([]TableInfo, err
 	return []TableInfo{}, nil
 }
 
+// getDeltaFiles retrieves live data files of a Delta Lake table
+func (c *LakehouseConnector) getDeltaFiles(ctx context.Context, table string) ([]partitionFile, error) {
+	// In production: Replay the _delta_log to the latest checkpoint and collect
+	// add actions; each carries partitionValues, size, modificationTime and
+	// stats.numRecords, with partition columns from the metaData action
+	return []partitionFile{}, nil
+}
+
+// getIcebergFiles retrieves data files of the current Iceberg snapshot
+func (c *LakehouseConnector) getIcebergFiles(ctx context.Context, table string) ([]partitionFile, error) {
+	// In production: Load the current snapshot's manifest list from the catalog and
+	// read manifest entries; each carries the partition tuple, record_count and
+	// file_size_in_bytes, with field names from the table's partition spec
+	return []partitionFile{}, nil
+}
+
+// getHivePathFiles retrieves data files under a table path with Hive-style
+// key=value partition directories
+func (c *LakehouseConnector) getHivePathFiles(ctx context.Context, table string) ([]partitionFile, error) {
+	// In production: List files under the table path and parse each path
+	// with ParseHivePartitionPath
+	return []partitionFile{}, nil
+}
+
 // LakehouseTableMetadata contains format-specific metadata
 type LakehouseTableMetadata struct {
 	Format       string                 `json:"format"`        // delta, iceberg, hudi, parquet
@@ -3,6 +3,7 @@ package datasource
 import (
 	"context"
 	"fmt"
+	"strings"
 )
 
 // PostgresConnector implements Connector for PostgreSQL
@@ -32,10 +33,13 @@ func (c *PostgresConnector) Connect(ctx context.Context) error {
 // GetTables returns tables in PostgreSQL database
 func (c *PostgresConnector) GetTables(ctx context.Context) ([]TableInfo, error) {
 	query := `
-		SELECT table_schema, table_name, table_type 
-		FROM information_schema.tables 
-		WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
-		ORDER BY table_schema, table_name`
+		SELECT t.table_schema, t.table_name, t.table_type,
+			pg_get_partkeydef(cl.oid) as partition_key
+		FROM information_schema.tables t
+		LEFT JOIN pg_namespace ns ON ns.nspname = t.table_schema
+		LEFT JOIN pg_class cl ON cl.relname = t.table_name AND cl.relnamespace = ns.oid
+		WHERE t.table_schema NOT IN ('pg_catalog', 'information_schema')
+		ORDER BY t.table_schema, t.table_name`
 
 	result, err := c.Query(ctx, query)
 	if err != nil {
@@ -45,23 +49,91 @@ func (c *PostgresConnector) GetTables(ctx context.Context) ([]TableInfo, error)
 	var tables []TableInfo
 	for _, row := range result.Rows {
 		tables = append(tables, TableInfo{
-			Schema: fmt.Sprintf("%v", row["table_schema"]),
-			Name:   fmt.Sprintf("%v", row["table_name"]),
-			Type:   fmt.Sprintf("%v", row["table_type"]),
+			Schema:        fmt.Sprintf("%v", row["table_schema"]),
+			Name:          fmt.Sprintf("%v", row["table_name"]),
+			Type:          fmt.Sprintf("%v", row["table_type"]),
+			PartitionKeys: parsePostgresPartitionKey(rowString(row["partition_key"])),
 		})
 	}
 	return tables, nil
 }
 
-// GetColumns returns columns for a PostgreSQL table
-func (c *PostgresConnector) GetColumns(ctx context.Context, table string) ([]ColumnInfo, error) {
+// GetPartitions returns the child partitions of a declaratively partitioned
+// PostgreSQL table, named as schema.table or resolved through the search
+// path. Row counts are planner estimates from pg_class.
+func (c *PostgresConnector) GetPartitions(ctx context.Context, table string) ([]PartitionInfo, error) {
+	schema, name := splitQualifiedName(table)
+	keyResult, err := c.Query(ctx, `
+		SELECT pg_get_partkeydef(cl.oid) as partition_key
+		FROM pg_class cl
+		JOIN pg_namespace ns ON ns.oid = cl.relnamespace
+		WHERE cl.relname = $1 AND cl.relkind = 'p'
+			AND (ns.nspname = $2 OR ($2 = '' AND pg_table_is_visible(cl.oid)))`, name, schema)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyResult.Rows) == 0 {
+		return []PartitionInfo{}, nil
+	}
+	keys := parsePostgresPartitionKey(rowString(keyResult.Rows[0]["partition_key"]))
+
 	query := `
-		SELECT column_name, data_type, is_nullable, column_default
-		FROM information_schema.columns
-		WHERE table_name = $1
-		ORDER BY ordinal_position`
+		SELECT child.relname as partition_name,
+			pg_get_expr(child.relpartbound, child.oid) as partition_bound,
+			child.reltuples::bigint as row_count,
+			pg_total_relation_size(child.oid) as size_bytes
+		FROM pg_inherits inh
+		JOIN pg_class parent ON parent.oid = inh.inhparent
+		JOIN pg_class child ON child.oid = inh.inhrelid
+		JOIN pg_namespace ns ON ns.oid = parent.relnamespace
+		WHERE parent.relname = $1
+			AND (ns.nspname = $2 OR ($2 = '' AND pg_table_is_visible(parent.oid)))
+		ORDER BY child.relname`
+
+	result, err := c.Query(ctx, query, name, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	partitions := make([]PartitionInfo, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		bound := rowString(row["partition_bound"])
+		partition := PartitionInfo{
+			Name:       rowString(row["partition_name"]),
+			Expression: bound,
+			RowCount:   rowInt64(row["row_count"]),
+			SizeBytes:  rowInt64(row["size_bytes"]),
+		}
+		if value, ok := parsePostgresListBound(bound); ok && len(keys) == 1 {
+			partition.Values = map[string]string{keys[0]: value}
+		}
+		partitions = append(partitions, partition)
+	}
+	return partitions, nil
+}
 
-	result, err := c.Query(ctx, query, table)
+// GetColumns returns columns for a PostgreSQL table, named as schema.table
+// or resolved through the search path
+func (c *PostgresConnector) GetColumns(ctx context.Context, table string) ([]ColumnInfo, error) {
+	schema, name := splitQualifiedName(table)
+	query := `
+		SELECT col.column_name, col.data_type, col.is_nullable, col.column_default,
+			EXISTS (
+				SELECT 1
+				FROM information_schema.table_constraints tc
+				JOIN information_schema.key_column_usage kcu
+					ON kcu.constraint_schema = tc.constraint_schema AND kcu.constraint_name = tc.constraint_name
+				WHERE tc.constraint_type = 'PRIMARY KEY'
+					AND tc.table_schema = col.table_schema AND tc.table_name = col.table_name
+					AND kcu.column_name = col.column_name
+			) as is_primary_key
+		FROM information_schema.columns col
+		WHERE col.table_name = $1
+			AND (col.table_schema = $2 OR ($2 = '' AND
+				pg_table_is_visible(format('%I.%I', col.table_schema, col.table_name)::regclass)))
+		ORDER BY col.ordinal_position`
+
+	result, err := c.Query(ctx, query, name, schema)
 	if err != nil {
 		return nil, err
 	}
@@ -73,6 +145,7 @@ func (c *PostgresConnector) GetColumns(ctx context.Context, table string) ([]Col
 			DataType:     fmt.Sprintf("%v", row["data_type"]),
 			Nullable:     fmt.Sprintf("%v", row["is_nullable"]) == "YES",
 			DefaultValue: fmt.Sprintf("%v", row["column_default"]),
+			IsPrimaryKey: row["is_primary_key"] == true,
 		})
 	}
 	return columns, nil
@@ -140,15 +213,17 @@ func (c *MySQLConnector) GetTables(ctx context.Context) ([]TableInfo, error) {
 	return tables, nil
 }
 
-// GetColumns returns columns for a MySQL table
+// GetColumns returns columns for a MySQL table, named as database.table or
+// in the current database
 func (c *MySQLConnector) GetColumns(ctx context.Context, table string) ([]ColumnInfo, error) {
+	schema, name := splitQualifiedName(strings.ReplaceAll(table, "`", ""))
 	query := `
 		SELECT column_name, data_type, is_nullable, column_default, column_key
 		FROM information_schema.columns
-		WHERE table_name = ? AND table_schema = DATABASE()
+		WHERE table_name = ? AND table_schema = COALESCE(NULLIF(?, ''), DATABASE())
 		ORDER BY ordinal_position`
 
-	result, err := c.Query(ctx, query, table)
+	result, err := c.Query(ctx, query, name, schema)
 	if err != nil {
 		return nil, err
 	}
@@ -228,15 +303,17 @@ func (c *SQLServerConnector) GetTables(ctx context.Context) ([]TableInfo, error)
 	return tables, nil
 }
 
-// GetColumns returns columns for a SQL Server table
+// GetColumns returns columns for a SQL Server table, named as schema.table
+// or in the default schema
 func (c *SQLServerConnector) GetColumns(ctx context.Context, table string) ([]ColumnInfo, error) {
+	schema, name := splitQualifiedName(strings.NewReplacer("[", "", "]", "").Replace(table))
 	query := `
 		SELECT COLUMN_NAME, DATA_TYPE, IS_NULLABLE, COLUMN_DEFAULT
 		FROM INFORMATION_SCHEMA.COLUMNS
-		WHERE TABLE_NAME = @p1
+		WHERE TABLE_NAME = @p1 AND TABLE_SCHEMA = COALESCE(NULLIF(@p2, ''), SCHEMA_NAME())
 		ORDER BY ORDINAL_POSITION`
 
-	result, err := c.Query(ctx, query, table)
+	result, err := c.Query(ctx, query, name, schema)
 	if err != nil {
 		return nil, err
 	}
@@ -315,15 +392,17 @@ func (c *OracleConnector) GetTables(ctx context.Context) ([]TableInfo, error) {
 	return tables, nil
 }
 
-// GetColumns returns columns for an Oracle table
+// GetColumns returns columns for an Oracle table, named as owner.table or
+// in the current schema
 func (c *OracleConnector) GetColumns(ctx context.Context, table string) ([]ColumnInfo, error) {
+	schema, name := splitQualifiedName(table)
 	query := `
 		SELECT column_name, data_type, nullable, data_default
 		FROM all_tab_columns
-		WHERE table_name = :1
+		WHERE table_name = :1 AND owner = COALESCE(:2, SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA'))
 		ORDER BY column_id`
 
-	result, err := c.Query(ctx, query, table)
+	result, err := c.Query(ctx, query, name, schema)
 	if err != nil {
 		return nil, err
 	}
@@ -62,7 +62,35 @@ func (c *StorageConnector) Query(ctx context.Context, query string, args ...inte
 // GetTables lists files/objects in the storage as datasets
 func (c *StorageConnector) GetTables(ctx context.Context) ([]TableInfo, error) {
 	// In storage context, "tables" are files that can be observed
-	return c.ListFiles(ctx, "", true)
+	tables, err := c.ListFiles(ctx, "", true)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tables {
+		tables[i].PartitionKeys, _ = ParseHivePartitionPath(tables[i].Name)
+	}
+	return tables, nil
+}
+
+// GetPartitions groups files under a dataset prefix by their Hive-style
+// key=value path segments (e.g. events/dt=2024-01-01/part-0000.parquet)
+func (c *StorageConnector) GetPartitions(ctx context.Context, prefix string) ([]PartitionInfo, error) {
+	objects, err := c.ListFiles(ctx, prefix, true)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]partitionFile, 0, len(objects))
+	for _, obj := range objects {
+		keys, values := ParseHivePartitionPath(strings.TrimPrefix(obj.Name, prefix))
+		files = append(files, partitionFile{
+			Keys:      keys,
+			Values:    values,
+			RowCount:  obj.RowCount,
+			SizeBytes: obj.SizeBytes,
+		})
+	}
+	return groupPartitionFiles(files), nil
 }
 
 // GetColumns returns schema for supported file formats
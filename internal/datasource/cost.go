@@ -0,0 +1,181 @@
+package datasource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// bigQueryUSDPerTiB is the BigQuery on-demand analysis price, overridable
+// per datasource with the "price_per_tib" connection option
+const bigQueryUSDPerTiB = 6.25
+
+// ErrCostEstimationUnsupported is returned by connectors that cannot estimate query cost
+var ErrCostEstimationUnsupported = errors.New("cost estimation not supported")
+
+// ErrQueryBudgetExceeded is returned when a query's estimated cost exceeds its budget
+var ErrQueryBudgetExceeded = errors.New("query budget exceeded")
+
+// CostEstimate is the estimated cost of a query, obtained without running it
+type CostEstimate struct {
+	BytesScanned int64   `json:"bytes_scanned"`
+	CostUSD      float64 `json:"cost_usd,omitempty"`     // Engines with on-demand pricing
+	PlannerCost  float64 `json:"planner_cost,omitempty"` // Engine-specific planner units
+	Method       string  `json:"method"`                 // bigquery_dry_run, snowflake_explain, postgres_explain
+}
+
+// CostEstimator is implemented by connectors that can estimate the cost of a
+// query before executing it
+type CostEstimator interface {
+	EstimateCost(ctx context.Context, query string, args ...interface{}) (*CostEstimate, error)
+}
+
+// QueryBudget limits the estimated cost of the queries a check run may send.
+// Zero fields are unlimited.
+type QueryBudget struct {
+	MaxBytesScanned int64   `json:"max_bytes_scanned,omitempty"`
+	MaxCostUSD      float64 `json:"max_cost_usd,omitempty"`
+}
+
+// IsZero reports whether the budget sets no limits
+func (b QueryBudget) IsZero() bool {
+	return b.MaxBytesScanned <= 0 && b.MaxCostUSD <= 0
+}
+
+// Merge returns the tighter of two budgets, limit by limit
+func (b QueryBudget) Merge(other QueryBudget) QueryBudget {
+	if other.MaxBytesScanned > 0 && (b.MaxBytesScanned <= 0 || other.MaxBytesScanned < b.MaxBytesScanned) {
+		b.MaxBytesScanned = other.MaxBytesScanned
+	}
+	if other.MaxCostUSD > 0 && (b.MaxCostUSD <= 0 || other.MaxCostUSD < b.MaxCostUSD) {
+		b.MaxCostUSD = other.MaxCostUSD
+	}
+	return b
+}
+
+// Check returns ErrQueryBudgetExceeded if the estimate exceeds the budget
+func (b QueryBudget) Check(estimate CostEstimate) error {
+	if b.MaxBytesScanned > 0 && estimate.BytesScanned > b.MaxBytesScanned {
+		return fmt.Errorf("%w: estimated %d bytes scanned exceeds limit of %d", ErrQueryBudgetExceeded, estimate.BytesScanned, b.MaxBytesScanned)
+	}
+	if b.MaxCostUSD > 0 && estimate.CostUSD > b.MaxCostUSD {
+		return fmt.Errorf("%w: estimated cost $%.4f exceeds limit of $%.4f", ErrQueryBudgetExceeded, estimate.CostUSD, b.MaxCostUSD)
+	}
+	return nil
+}
+
+// EstimateCost estimates a query with EXPLAIN (FORMAT JSON). Bytes scanned is
+// approximated from the planned rows and row width of every scan node.
+func (c *PostgresConnector) EstimateCost(ctx context.Context, query string, args ...interface{}) (*CostEstimate, error) {
+	result, err := c.Query(ctx, "EXPLAIN (FORMAT JSON) "+query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("explain failed: %w", err)
+	}
+	if len(result.Rows) == 0 {
+		return nil, fmt.Errorf("explain returned no plan")
+	}
+	return parsePostgresPlan(rowString(result.Rows[0]["QUERY PLAN"]))
+}
+
+// EstimateCost estimates bytes processed with a BigQuery dry run, which
+// validates and plans the query without running or billing it
+func (c *BigQueryConnector) EstimateCost(ctx context.Context, query string, args ...interface{}) (*CostEstimate, error) {
+	bytes, err := c.dryRun(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("dry run failed: %w", err)
+	}
+
+	price := bigQueryUSDPerTiB
+	if v, err := strconv.ParseFloat(c.config.Options["price_per_tib"], 64); err == nil {
+		price = v
+	}
+	return &CostEstimate{
+		BytesScanned: bytes,
+		CostUSD:      float64(bytes) / (1 << 40) * price,
+		Method:       "bigquery_dry_run",
+	}, nil
+}
+
+// dryRun returns the number of bytes a query would process. Until the client
+// is wired it reports estimation as unsupported, so that budgets do not fail
+// every BigQuery check.
+func (c *BigQueryConnector) dryRun(ctx context.Context, query string) (int64, error) {
+	// In production: use cloud.google.com/go/bigquery
+	// q := client.Query(query)
+	// q.DryRun = true
+	// job, err := q.Run(ctx)
+	// return job.LastStatus().Statistics.TotalBytesProcessed, nil
+	return 0, ErrCostEstimationUnsupported
+}
+
+// EstimateCost estimates a query with EXPLAIN USING JSON, which reports the
+// micro-partitions and bytes assigned to the scan after pruning
+func (c *SnowflakeConnector) EstimateCost(ctx context.Context, query string, args ...interface{}) (*CostEstimate, error) {
+	result, err := c.Query(ctx, "EXPLAIN USING JSON "+query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("explain failed: %w", err)
+	}
+	if len(result.Rows) == 0 {
+		return nil, fmt.Errorf("explain returned no plan")
+	}
+	return parseSnowflakeExplain(rowString(result.Rows[0]["content"]))
+}
+
+// postgresPlanNode is a node of a Postgres JSON query plan
+type postgresPlanNode struct {
+	NodeType  string             `json:"Node Type"`
+	TotalCost float64            `json:"Total Cost"`
+	PlanRows  float64            `json:"Plan Rows"`
+	PlanWidth float64            `json:"Plan Width"`
+	Plans     []postgresPlanNode `json:"Plans"`
+}
+
+// parsePostgresPlan parses the output of EXPLAIN (FORMAT JSON)
+func parsePostgresPlan(plan string) (*CostEstimate, error) {
+	var explain []struct {
+		Plan postgresPlanNode `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(plan), &explain); err != nil {
+		return nil, fmt.Errorf("failed to parse query plan: %w", err)
+	}
+	if len(explain) == 0 {
+		return nil, fmt.Errorf("explain returned no plan")
+	}
+
+	root := explain[0].Plan
+	return &CostEstimate{
+		BytesScanned: int64(scannedBytes(root)),
+		PlannerCost:  root.TotalCost,
+		Method:       "postgres_explain",
+	}, nil
+}
+
+func scannedBytes(node postgresPlanNode) float64 {
+	var total float64
+	if strings.Contains(node.NodeType, "Scan") {
+		total += node.PlanRows * node.PlanWidth
+	}
+	for _, child := range node.Plans {
+		total += scannedBytes(child)
+	}
+	return total
+}
+
+// parseSnowflakeExplain parses the output of EXPLAIN USING JSON
+func parseSnowflakeExplain(plan string) (*CostEstimate, error) {
+	var explain struct {
+		GlobalStats struct {
+			BytesAssigned int64 `json:"bytesAssigned"`
+		} `json:"GlobalStats"`
+	}
+	if err := json.Unmarshal([]byte(plan), &explain); err != nil {
+		return nil, fmt.Errorf("failed to parse query plan: %w", err)
+	}
+	return &CostEstimate{
+		BytesScanned: explain.GlobalStats.BytesAssigned,
+		Method:       "snowflake_explain",
+	}, nil
+}
@@ -6,6 +6,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -40,6 +42,10 @@ const (
 	TypeView Type = "view"
 )
 
+// TagPII marks a column holding personally identifiable information. Values of
+// PII columns are masked wherever row data leaves the datasource.
+const TagPII = "pii"
+
 // Datasource represents a data source configuration
 type Datasource struct {
 	ID          string                 `json:"id"`
@@ -49,11 +55,23 @@ type Datasource struct {
 	Type        Type                   `json:"type"`
 	Connection  ConnectionConfig       `json:"connection"`
 	Metadata    map[string]interface{} `json:"metadata"`
+	QueryBudget *QueryBudget           `json:"query_budget,omitempty"` // Limits the estimated cost of each check run
+	ColumnTags  map[string][]string    `json:"column_tags,omitempty"`  // Tags by "table.column", e.g. pii
 	Active      bool                   `json:"active"`
 	CreatedAt   time.Time              `json:"created_at"`
 	UpdatedAt   time.Time              `json:"updated_at"`
 }
 
+// HasColumnTag reports whether a column of a table carries a tag
+func (ds *Datasource) HasColumnTag(table, column, tag string) bool {
+	for _, t := range ds.ColumnTags[table+"."+column] {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
 // ConnectionConfig holds connection configuration for a datasource
 type ConnectionConfig struct {
 	// Common database fields
@@ -111,6 +129,10 @@ type Connector interface {
 	// GetRowCount returns the row count for a table
 	GetRowCount(ctx context.Context, table string) (int64, error)
 
+	// GetPartitions returns partition metadata for a table.
+	// Returns an empty list for unpartitioned tables or engines without partitions.
+	GetPartitions(ctx context.Context, table string) ([]PartitionInfo, error)
+
 	// Type returns the datasource type
 	Type() Type
 }
@@ -124,11 +146,23 @@ type QueryResult struct {
 
 // TableInfo contains information about a table
 type TableInfo struct {
-	Schema    string `json:"schema"`
-	Name      string `json:"name"`
-	Type      string `json:"type"` // table, view, materialized_view
-	RowCount  int64  `json:"row_count,omitempty"`
-	SizeBytes int64  `json:"size_bytes,omitempty"`
+	Schema        string   `json:"schema"`
+	Name          string   `json:"name"`
+	Type          string   `json:"type"` // table, view, materialized_view
+	RowCount      int64    `json:"row_count,omitempty"`
+	SizeBytes     int64    `json:"size_bytes,omitempty"`
+	PartitionKeys []string `json:"partition_keys,omitempty"`
+}
+
+// PartitionInfo contains information about a single table partition
+type PartitionInfo struct {
+	Name         string            `json:"name"`
+	Values       map[string]string `json:"values,omitempty"`     // Partition key -> value
+	Expression   string            `json:"expression,omitempty"` // Engine-native bound, e.g. FOR VALUES FROM (...) TO (...)
+	RowCount     int64             `json:"row_count,omitempty"`
+	SizeBytes    int64             `json:"size_bytes,omitempty"`
+	FileCount    int64             `json:"file_count,omitempty"`
+	LastModified *time.Time        `json:"last_modified,omitempty"`
 }
 
 // ColumnInfo contains information about a column
@@ -141,10 +175,47 @@ type ColumnInfo struct {
 	Description  string `json:"description,omitempty"`
 }
 
-// Manager handles datasource operations
+// TypeKind is the broad category of a column's data type
+type TypeKind string
+
+const (
+	KindNumeric  TypeKind = "numeric"
+	KindString   TypeKind = "string"
+	KindTemporal TypeKind = "temporal"
+	KindBoolean  TypeKind = "boolean"
+	KindOther    TypeKind = "other"
+)
+
+// ClassifyType returns the kind of an engine data type name
+func ClassifyType(dataType string) TypeKind {
+	t := strings.ToLower(dataType)
+	switch {
+	case strings.Contains(t, "interval") || strings.Contains(t, "point"):
+		return KindOther
+	case strings.Contains(t, "bool") || t == "bit":
+		return KindBoolean
+	case strings.Contains(t, "time") || strings.Contains(t, "date"):
+		return KindTemporal
+	case strings.Contains(t, "int") || strings.Contains(t, "numeric") || strings.Contains(t, "decimal") ||
+		strings.Contains(t, "float") || strings.Contains(t, "double") || strings.Contains(t, "real") ||
+		strings.Contains(t, "number") || strings.Contains(t, "money"):
+		return KindNumeric
+	case strings.Contains(t, "char") || strings.Contains(t, "text") || strings.Contains(t, "string") ||
+		strings.Contains(t, "uuid") || strings.Contains(t, "enum"):
+		return KindString
+	default:
+		return KindOther
+	}
+}
+
+// Manager handles datasource operations. A stored datasource is never
+// modified in place: updates replace it, so callers may read the datasources
+// they get while others are updated.
 type Manager struct {
+	mu          sync.RWMutex
 	datasources map[string]*Datasource
 	connectors  map[string]Connector
+	queryLog    *QueryLog
 }
 
 // NewManager creates a new datasource manager
@@ -152,6 +223,7 @@ func NewManager() *Manager {
 	return &Manager{
 		datasources: make(map[string]*Datasource),
 		connectors:  make(map[string]Connector),
+		queryLog:    NewQueryLog(defaultQueryLogSize),
 	}
 }
 
@@ -179,13 +251,17 @@ func (m *Manager) CreateDatasource(ctx context.Context, ds *Datasource) error {
 		return fmt.Errorf("failed to ping datasource: %w", err)
 	}
 
+	m.mu.Lock()
 	m.datasources[ds.ID] = ds
-	m.connectors[ds.ID] = connector
+	m.connectors[ds.ID] = newAuditedConnector(connector, ds, m.queryLog)
+	m.mu.Unlock()
 	return nil
 }
 
 // GetDatasource retrieves a datasource by ID
 func (m *Manager) GetDatasource(ctx context.Context, id string) (*Datasource, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	ds, exists := m.datasources[id]
 	if !exists {
 		return nil, fmt.Errorf("datasource not found: %s", id)
@@ -195,10 +271,14 @@ func (m *Manager) GetDatasource(ctx context.Context, id string) (*Datasource, er
 
 // UpdateDatasource updates a datasource
 func (m *Manager) UpdateDatasource(ctx context.Context, id string, updates map[string]interface{}) error {
-	ds, exists := m.datasources[id]
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, exists := m.datasources[id]
 	if !exists {
 		return fmt.Errorf("datasource not found: %s", id)
 	}
+	updated := *stored
+	ds := &updated
 
 	if name, ok := updates["name"].(string); ok {
 		ds.Name = name
@@ -212,13 +292,36 @@ func (m *Manager) UpdateDatasource(ctx context.Context, id string, updates map[s
 	if active, ok := updates["active"].(bool); ok {
 		ds.Active = active
 	}
+	if budget, ok := updates["query_budget"].(map[string]interface{}); ok {
+		ds.QueryBudget = &QueryBudget{}
+		if v, ok := budget["max_bytes_scanned"].(float64); ok {
+			ds.QueryBudget.MaxBytesScanned = int64(v)
+		}
+		if v, ok := budget["max_cost_usd"].(float64); ok {
+			ds.QueryBudget.MaxCostUSD = v
+		}
+	}
+	if tags, ok := updates["column_tags"].(map[string]interface{}); ok {
+		ds.ColumnTags = make(map[string][]string, len(tags))
+		for column, values := range tags {
+			list, _ := values.([]interface{})
+			for _, v := range list {
+				if tag, ok := v.(string); ok {
+					ds.ColumnTags[column] = append(ds.ColumnTags[column], tag)
+				}
+			}
+		}
+	}
 
 	ds.UpdatedAt = time.Now()
+	m.datasources[id] = ds
 	return nil
 }
 
 // DeleteDatasource deletes a datasource
 func (m *Manager) DeleteDatasource(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if _, exists := m.datasources[id]; !exists {
 		return fmt.Errorf("datasource not found: %s", id)
 	}
@@ -234,6 +337,8 @@ func (m *Manager) DeleteDatasource(ctx context.Context, id string) error {
 
 // ListDatasources lists datasources for a tenant
 func (m *Manager) ListDatasources(ctx context.Context, tenantID string) ([]*Datasource, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	var result []*Datasource
 	for _, ds := range m.datasources {
 		if tenantID == "" || ds.TenantID == tenantID {
@@ -245,6 +350,8 @@ func (m *Manager) ListDatasources(ctx context.Context, tenantID string) ([]*Data
 
 // GetConnector returns the connector for a datasource
 func (m *Manager) GetConnector(ctx context.Context, id string) (Connector, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	connector, exists := m.connectors[id]
 	if !exists {
 		return nil, fmt.Errorf("connector not found for datasource: %s", id)
@@ -252,6 +359,19 @@ func (m *Manager) GetConnector(ctx context.Context, id string) (Connector, error
 	return connector, nil
 }
 
+// ListQueries returns audited queries sent to datasources, newest first
+func (m *Manager) ListQueries(ctx context.Context, filter QueryLogFilter) ([]*QueryLogEntry, error) {
+	if filter.DatasourceID != "" {
+		m.mu.RLock()
+		_, exists := m.datasources[filter.DatasourceID]
+		m.mu.RUnlock()
+		if !exists {
+			return nil, fmt.Errorf("datasource not found: %s", filter.DatasourceID)
+		}
+	}
+	return m.queryLog.List(filter), nil
+}
+
 // TestConnection tests a datasource connection without storing it
 func (m *Manager) TestConnection(ctx context.Context, ds *Datasource) error {
 	connector, err := m.createConnector(ds)
@@ -330,6 +450,14 @@ func (c *BaseConnector) Ping(ctx context.Context) error {
 
 // Query executes a query
 func (c *BaseConnector) Query(ctx context.Context, query string, args ...interface{}) (*QueryResult, error) {
+	startedAt := time.Now()
+	result, err := c.query(ctx, query, args...)
+	recordQuery(ctx, query, startedAt, result, err)
+	return result, err
+}
+
+// query runs a query against the underlying database
+func (c *BaseConnector) query(ctx context.Context, query string, args ...interface{}) (*QueryResult, error) {
 	if c.db == nil {
 		return nil, fmt.Errorf("database connection not established")
 	}
@@ -372,6 +500,12 @@ func (c *BaseConnector) Query(ctx context.Context, query string, args ...interfa
 	return result, nil
 }
 
+// GetPartitions returns no partitions by default; connectors for engines
+// with partitioning support override it
+func (c *BaseConnector) GetPartitions(ctx context.Context, table string) ([]PartitionInfo, error) {
+	return []PartitionInfo{}, nil
+}
+
 // Type returns the datasource type
 func (c *BaseConnector) Type() Type {
 	return c.dsType
@@ -2,7 +2,14 @@ package datasource
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewManager(t *testing.T) {
@@ -184,3 +191,577 @@ func TestDetectFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestParseHivePartitionPath(t *testing.T) {
+	keys, values := ParseHivePartitionPath("events/dt=2024-01-01/hour=03/part-0000.parquet")
+	if len(keys) != 2 || keys[0] != "dt" || keys[1] != "hour" {
+		t.Fatalf("expected keys [dt hour], got %v", keys)
+	}
+	if values["dt"] != "2024-01-01" || values["hour"] != "03" {
+		t.Errorf("unexpected values: %v", values)
+	}
+
+	keys, _ = ParseHivePartitionPath("events/part-0000.parquet")
+	if len(keys) != 0 {
+		t.Errorf("expected no keys for unpartitioned path, got %v", keys)
+	}
+
+	_, values = ParseHivePartitionPath("events/city=New%20York/part-0000.parquet")
+	if values["city"] != "New York" {
+		t.Errorf("expected unescaped value 'New York', got '%s'", values["city"])
+	}
+}
+
+func TestGroupPartitionFiles(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	keys := []string{"dt"}
+	files := []partitionFile{
+		{Keys: keys, Values: map[string]string{"dt": "2024-01-02"}, RowCount: 10, SizeBytes: 100, LastModified: older},
+		{Keys: keys, Values: map[string]string{"dt": "2024-01-01"}, RowCount: 5, SizeBytes: 50, LastModified: older},
+		{Keys: keys, Values: map[string]string{"dt": "2024-01-02"}, RowCount: 20, SizeBytes: 200, LastModified: newer},
+		{RowCount: 1},
+	}
+
+	partitions := groupPartitionFiles(files)
+	if len(partitions) != 2 {
+		t.Fatalf("expected 2 partitions, got %d", len(partitions))
+	}
+	if partitions[0].Name != "dt=2024-01-01" {
+		t.Errorf("expected partitions sorted by name, got %s first", partitions[0].Name)
+	}
+	latest := partitions[1]
+	if latest.RowCount != 30 || latest.SizeBytes != 300 || latest.FileCount != 2 {
+		t.Errorf("unexpected totals: rows=%d size=%d files=%d", latest.RowCount, latest.SizeBytes, latest.FileCount)
+	}
+	if latest.LastModified == nil || !latest.LastModified.Equal(newer) {
+		t.Errorf("expected last modified %v, got %v", newer, latest.LastModified)
+	}
+}
+
+func TestParsePostgresPartitionKey(t *testing.T) {
+	testCases := []struct {
+		def      string
+		expected []string
+	}{
+		{"RANGE (created_at)", []string{"created_at"}},
+		{"LIST (region, country)", []string{"region", "country"}},
+		{"RANGE (date_trunc('day'::text, created_at))", []string{"date_trunc('day'::text, created_at)"}},
+		{"", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.def, func(t *testing.T) {
+			keys := parsePostgresPartitionKey(tc.def)
+			if len(keys) != len(tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, keys)
+			}
+			for i := range keys {
+				if keys[i] != tc.expected[i] {
+					t.Errorf("expected %v, got %v", tc.expected, keys)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitQualifiedName(t *testing.T) {
+	testCases := []struct {
+		table, schema, name string
+	}{
+		{"orders", "", "orders"},
+		{"sales.orders", "sales", "orders"},
+		{`"Sales"."Orders"`, "Sales", "Orders"},
+	}
+	for _, tc := range testCases {
+		if schema, name := splitQualifiedName(tc.table); schema != tc.schema || name != tc.name {
+			t.Errorf("%s: expected %q, %q, got %q, %q", tc.table, tc.schema, tc.name, schema, name)
+		}
+	}
+}
+
+func TestBaseConnector_GetPartitions(t *testing.T) {
+	connector := NewMySQLConnector(ConnectionConfig{})
+
+	partitions, err := connector.GetPartitions(context.Background(), "orders")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(partitions) != 0 {
+		t.Errorf("expected no partitions, got %d", len(partitions))
+	}
+}
+
+func TestSample_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		sample  Sample
+		wantErr bool
+	}{
+		{"percentage", Sample{Percentage: 10}, false},
+		{"row count", Sample{RowCount: 1000}, false},
+		{"hash", Sample{Percentage: 5, Method: SampleHash, KeyColumn: "id"}, false},
+		{"neither", Sample{}, true},
+		{"both", Sample{Percentage: 10, RowCount: 100}, true},
+		{"percentage too large", Sample{Percentage: 150}, true},
+		{"hash without key", Sample{Percentage: 5, Method: SampleHash}, true},
+		{"hash with row count", Sample{RowCount: 5, Method: SampleHash, KeyColumn: "id"}, true},
+		{"unknown method", Sample{Percentage: 5, Method: "reservoir"}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.sample.Validate()
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestDialect_SampledTable(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dsType   Type
+		sample   Sample
+		expected string
+	}{
+		{"postgres bernoulli", TypePostgres, Sample{Percentage: 10, Seed: 42},
+			"(SELECT * FROM events TABLESAMPLE BERNOULLI (10) REPEATABLE (42))"},
+		{"postgres system", TypePostgres, Sample{Percentage: 1.5, Method: SampleSystem},
+			"(SELECT * FROM events TABLESAMPLE SYSTEM (1.5))"},
+		{"postgres rows", TypePostgres, Sample{RowCount: 500},
+			"(SELECT * FROM events ORDER BY RANDOM() LIMIT 500)"},
+		{"snowflake seed", TypeSnowflake, Sample{Percentage: 10, Seed: 7},
+			"(SELECT * FROM events SAMPLE BERNOULLI (10) SEED (7))"},
+		{"snowflake rows", TypeSnowflake, Sample{RowCount: 500},
+			"(SELECT * FROM events SAMPLE (500 ROWS))"},
+		{"bigquery system", TypeBigQuery, Sample{Percentage: 10, Method: SampleSystem},
+			"(SELECT * FROM events TABLESAMPLE SYSTEM (10 PERCENT))"},
+		{"duckdb", TypeDuckDB, Sample{Percentage: 10, Seed: 3},
+			"(SELECT * FROM events USING SAMPLE 10 PERCENT (BERNOULLI, 3))"},
+		{"postgres hash", TypePostgres, Sample{Percentage: 5, Method: SampleHash, KeyColumn: "user_id"},
+			"(SELECT * FROM events WHERE MOD(MOD(hashtext(CAST(user_id AS TEXT)), 10000) + 10000, 10000) < 500)"},
+		{"sqlserver hash", TypeSQLServer, Sample{Percentage: 5, Method: SampleHash, KeyColumn: "user_id", Seed: 9},
+			"(SELECT * FROM events WHERE ((CHECKSUM(user_id)) % 10000 + 10009) % 10000 < 500)"},
+		{"hash with a large seed", TypeSnowflake, Sample{Percentage: 5, Method: SampleHash, KeyColumn: "user_id", Seed: -10003},
+			"(SELECT * FROM events WHERE MOD(MOD(HASH(user_id), 10000) + 19997, 10000) < 500)"},
+		{"duckdb seeded rows", TypeDuckDB, Sample{RowCount: 500, Seed: 3},
+			"(SELECT * FROM events USING SAMPLE 500 ROWS (reservoir, 3))"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sql, err := DialectFor(tc.dsType).SampledTable("events", tc.sample)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, sql)
+			}
+		})
+	}
+}
+
+func TestDialect_SampledTable_Unsupported(t *testing.T) {
+	_, err := DialectFor(TypeS3).SampledTable("events", Sample{Percentage: 10})
+	if err == nil {
+		t.Fatal("expected error for storage datasource")
+	}
+
+	// Seeds are rejected where the sample would not repeat
+	for _, tc := range []struct {
+		dsType Type
+		sample Sample
+	}{
+		{TypePostgres, Sample{RowCount: 500, Seed: 1}},
+		{TypeSnowflake, Sample{RowCount: 500, Seed: 1}},
+		{TypeTrino, Sample{Percentage: 10, Seed: 1}},
+		{TypeBigQuery, Sample{Percentage: 10, Seed: 1}},
+		{TypeSQLServer, Sample{Percentage: 10, Seed: 1}},
+	} {
+		if _, err := DialectFor(tc.dsType).SampledTable("events", tc.sample); err == nil || !strings.Contains(err.Error(), "seed is not supported") {
+			t.Errorf("%s %+v: expected the seed to be rejected, got %v", tc.dsType, tc.sample, err)
+		}
+	}
+}
+
+func TestDialect_Repeatable(t *testing.T) {
+	testCases := []struct {
+		name       string
+		dsType     Type
+		sample     Sample
+		seedable   bool
+		repeatable bool
+	}{
+		{"unseeded bernoulli", TypePostgres, Sample{Percentage: 10}, true, false},
+		{"seeded bernoulli", TypePostgres, Sample{Percentage: 10, Seed: 7}, true, true},
+		{"row count", TypePostgres, Sample{RowCount: 100}, false, false},
+		{"hash", TypeBigQuery, Sample{Percentage: 10, Method: SampleHash, KeyColumn: "id"}, false, true},
+		{"random predicate", TypeBigQuery, Sample{Percentage: 10}, false, false},
+		{"sampling key", TypeClickHouse, Sample{Percentage: 10}, false, true},
+	}
+	for _, tc := range testCases {
+		d := DialectFor(tc.dsType)
+		if got := d.Seedable(tc.sample); got != tc.seedable {
+			t.Errorf("%s: expected seedable=%v, got %v", tc.name, tc.seedable, got)
+		}
+		if got := d.Repeatable(tc.sample); got != tc.repeatable {
+			t.Errorf("%s: expected repeatable=%v, got %v", tc.name, tc.repeatable, got)
+		}
+	}
+}
+
+func TestDialect_RegexMatch(t *testing.T) {
+	testCases := []struct {
+		dsType   Type
+		expected string
+	}{
+		{TypePostgres, "email ~ '^a''b$'"},
+		{TypeMySQL, "email REGEXP '^a''b$'"},
+		{TypeSnowflake, "REGEXP_LIKE(email, '^a''b$')"},
+		{TypeBigQuery, `REGEXP_CONTAINS(email, '^a\'b$')`},
+		{TypeClickHouse, "match(email, '^a''b$')"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.dsType), func(t *testing.T) {
+			got, err := DialectFor(tc.dsType).RegexMatch("email", "^a'b$")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+
+	if _, err := DialectFor(TypeSQLServer).RegexMatch("email", "^a$"); err == nil {
+		t.Error("expected error for SQL Server")
+	}
+}
+
+func TestDialect_StringLiteral(t *testing.T) {
+	testCases := []struct {
+		dsType   Type
+		expected string
+	}{
+		{TypePostgres, `'it''s a\b'`},
+		{TypeMySQL, `'it''s a\\b'`},
+		{TypeBigQuery, `'it\'s a\\b'`},
+	}
+
+	for _, tc := range testCases {
+		if got := DialectFor(tc.dsType).StringLiteral(`it's a\b`); got != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.dsType, tc.expected, got)
+		}
+	}
+}
+
+func TestDialect_TimestampLiteral(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 30, 0, 500000000, time.FixedZone("CET", 3600))
+	testCases := []struct {
+		dsType   Type
+		expected string
+	}{
+		{TypePostgres, "TIMESTAMP '2024-03-01 11:30:00.500000'"},
+		{TypeSQLServer, "CAST('2024-03-01 11:30:00.500000' AS DATETIME2)"},
+		{TypeClickHouse, "toDateTime64('2024-03-01 11:30:00.500000', 6)"},
+	}
+
+	for _, tc := range testCases {
+		if got := DialectFor(tc.dsType).TimestampLiteral(at); got != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.dsType, tc.expected, got)
+		}
+	}
+}
+
+func TestDialect_RowChecksum(t *testing.T) {
+	got, err := DialectFor(TypePostgres).RowChecksum([]string{"id", "status"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "('x' || SUBSTR(MD5(COALESCE(CAST(id AS TEXT), '<NULL>') || '|' || COALESCE(CAST(status AS TEXT), '<NULL>')), 1, 8))::BIT(32)::BIGINT"
+	if got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	for _, dsType := range []Type{TypeMySQL, TypeSnowflake, TypeOracle, TypeBigQuery, TypeTrino, TypeDatabricks, TypeSQLServer} {
+		if _, err := DialectFor(dsType).RowChecksum([]string{"id"}); err != nil {
+			t.Errorf("%s: unexpected error: %v", dsType, err)
+		}
+	}
+	if _, err := DialectFor(TypeClickHouse).RowChecksum([]string{"id"}); err == nil {
+		t.Error("expected error for ClickHouse")
+	}
+}
+
+func TestRedactSQL(t *testing.T) {
+	testCases := []struct {
+		name     string
+		sql      string
+		secrets  []string
+		expected string
+	}{
+		{"configured secret", "SELECT * FROM t WHERE note = 's3cr3t!'", []string{"s3cr3t!"}, "SELECT * FROM t WHERE note = '[REDACTED]'"},
+		{"short secret ignored", "SELECT a FROM t", []string{"a"}, "SELECT a FROM t"},
+		{"password literal", "CREATE USER bob WITH PASSWORD 'hunter2'", nil, "CREATE USER bob WITH PASSWORD '[REDACTED]'"},
+		{"identified by", "ALTER USER bob IDENTIFIED BY hunter2", nil, "ALTER USER bob IDENTIFIED BY '[REDACTED]'"},
+		{"stage credentials", "COPY INTO t FROM 's3://b' CREDENTIALS=(AWS_KEY_ID='AKIA' AWS_SECRET_KEY='xyz')", nil, "COPY INTO t FROM 's3://b' CREDENTIALS=(AWS_KEY_ID='[REDACTED]' AWS_SECRET_KEY='[REDACTED]')"},
+		{"url credentials", "ATTACH 'postgres://bob:hunter2@db/app'", nil, "ATTACH 'postgres://bob:[REDACTED]@db/app'"},
+		{"no secrets", "SELECT COUNT(*) FROM users", nil, "SELECT COUNT(*) FROM users"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := RedactSQL(tc.sql, tc.secrets...); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestWithQueryOrigin(t *testing.T) {
+	ctx := context.Background()
+	if origin := QueryOriginFromContext(ctx); origin.Type != OriginSystem {
+		t.Errorf("expected default origin %s, got %s", OriginSystem, origin.Type)
+	}
+
+	ctx = WithQueryOrigin(ctx, QueryOrigin{Type: OriginUser, UserID: "alice"})
+	ctx = WithQueryOrigin(ctx, QueryOrigin{Type: OriginCheck, ID: "check-1"})
+
+	origin := QueryOriginFromContext(ctx)
+	if origin.Type != OriginCheck || origin.ID != "check-1" {
+		t.Errorf("expected check origin, got %+v", origin)
+	}
+	if origin.UserID != "alice" {
+		t.Errorf("expected user to be kept, got %q", origin.UserID)
+	}
+}
+
+func TestQueryLog_List(t *testing.T) {
+	log := NewQueryLog(3)
+	base := time.Date(2024, 1, 15, 3, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		log.Record(&QueryLogEntry{
+			DatasourceID: "ds-1",
+			Origin:       QueryOrigin{Type: OriginCheck, ID: "check-1"},
+			StartedAt:    base.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	entries := log.List(QueryLogFilter{})
+	if len(entries) != 3 {
+		t.Fatalf("expected oldest entry to be evicted, got %d entries", len(entries))
+	}
+	if !entries[0].StartedAt.Equal(base.Add(3 * time.Minute)) {
+		t.Errorf("expected newest entry first, got %v", entries[0].StartedAt)
+	}
+
+	entries = log.List(QueryLogFilter{Since: base.Add(2 * time.Minute), Until: base.Add(2 * time.Minute)})
+	if len(entries) != 1 {
+		t.Errorf("expected 1 entry in time window, got %d", len(entries))
+	}
+
+	if entries := log.List(QueryLogFilter{OriginType: OriginView}); len(entries) != 0 {
+		t.Errorf("expected no view entries, got %d", len(entries))
+	}
+	if entries := log.List(QueryLogFilter{DatasourceID: "ds-1", OriginID: "check-1", Limit: 2}); len(entries) != 2 {
+		t.Errorf("expected limit to apply, got %d entries", len(entries))
+	}
+}
+
+func TestAuditedConnector(t *testing.T) {
+	ds := &Datasource{
+		ID:         "ds-1",
+		TenantID:   "tenant-1",
+		Connection: ConnectionConfig{Password: "hunter2"},
+	}
+	log := NewQueryLog(10)
+	connector := newAuditedConnector(NewPostgresConnector(ds.Connection), ds, log)
+	ctx := WithQueryOrigin(context.Background(), QueryOrigin{Type: OriginView, ID: "view-1"})
+
+	if _, err := connector.Query(ctx, "SELECT 'hunter2'"); err == nil {
+		t.Fatal("expected error without a database connection")
+	}
+	// Metadata queries issued inside the connector are recorded as well
+	connector.GetColumns(ctx, "users")
+
+	entries := log.List(QueryLogFilter{DatasourceID: "ds-1"})
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audited queries, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		if entry.TenantID != "tenant-1" || entry.Origin.ID != "view-1" {
+			t.Errorf("unexpected attribution: %+v", entry)
+		}
+		if entry.Error == "" {
+			t.Error("expected error to be recorded")
+		}
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.SQL, "hunter2") {
+			t.Errorf("expected redacted SQL, got %q", entry.SQL)
+		}
+	}
+}
+
+func TestQueryBudget(t *testing.T) {
+	datasourceBudget := QueryBudget{MaxBytesScanned: 1000}
+	tenantBudget := QueryBudget{MaxBytesScanned: 5000, MaxCostUSD: 2}
+
+	budget := datasourceBudget.Merge(tenantBudget)
+	if budget.MaxBytesScanned != 1000 || budget.MaxCostUSD != 2 {
+		t.Errorf("expected tighter limits, got %+v", budget)
+	}
+	if !(QueryBudget{}).IsZero() {
+		t.Error("expected empty budget to be zero")
+	}
+
+	if err := budget.Check(CostEstimate{BytesScanned: 1000, CostUSD: 1}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := budget.Check(CostEstimate{BytesScanned: 1001}); !errors.Is(err, ErrQueryBudgetExceeded) {
+		t.Errorf("expected bytes limit to be exceeded, got %v", err)
+	}
+	if err := budget.Check(CostEstimate{CostUSD: 2.5}); !errors.Is(err, ErrQueryBudgetExceeded) {
+		t.Errorf("expected cost limit to be exceeded, got %v", err)
+	}
+}
+
+func TestParsePostgresPlan(t *testing.T) {
+	plan := `[{"Plan": {"Node Type": "Aggregate", "Total Cost": 1693.5, "Plan Rows": 1, "Plan Width": 8,
+		"Plans": [{"Node Type": "Seq Scan", "Total Cost": 1443.0, "Plan Rows": 100000, "Plan Width": 4}]}}]`
+
+	estimate, err := parsePostgresPlan(plan)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if estimate.PlannerCost != 1693.5 {
+		t.Errorf("expected planner cost 1693.5, got %f", estimate.PlannerCost)
+	}
+	if estimate.BytesScanned != 400000 {
+		t.Errorf("expected 400000 bytes scanned, got %d", estimate.BytesScanned)
+	}
+
+	if _, err := parsePostgresPlan("not json"); err == nil {
+		t.Error("expected error for invalid plan")
+	}
+}
+
+func TestParseSnowflakeExplain(t *testing.T) {
+	plan := `{"GlobalStats": {"partitionsTotal": 120, "partitionsAssigned": 12, "bytesAssigned": 52428800}, "Operations": [[]]}`
+
+	estimate, err := parseSnowflakeExplain(plan)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if estimate.BytesScanned != 52428800 {
+		t.Errorf("expected 52428800 bytes, got %d", estimate.BytesScanned)
+	}
+}
+
+func TestBigQueryEstimateCost_Unsupported(t *testing.T) {
+	_, err := NewBigQueryConnector(ConnectionConfig{}).EstimateCost(context.Background(), "SELECT 1")
+	if !errors.Is(err, ErrCostEstimationUnsupported) {
+		t.Errorf("expected budgets to be skipped until the dry run is wired, got %v", err)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected ErrorClass
+	}{
+		{errors.New("read tcp 10.0.0.1:5432: connection reset by peer"), ErrorTransient},
+		{errors.New("ERROR: could not serialize access due to concurrent update (SQLSTATE 40001)"), ErrorTransient},
+		{errors.New("googleapi: Error 429: Rate limit exceeded"), ErrorTransient},
+		{fmt.Errorf("failed to execute query: %w", context.DeadlineExceeded), ErrorTransient},
+		{errors.New(`pq: relation "orders" does not exist`), ErrorPermanent},
+		{errors.New("scanned 1080001 rows"), ErrorPermanent},
+		{context.Canceled, ErrorPermanent},
+		{fmt.Errorf("%w: 2048 bytes", ErrQueryBudgetExceeded), ErrorPermanent},
+	}
+
+	for _, tc := range testCases {
+		if got := ClassifyError(tc.err); got != tc.expected {
+			t.Errorf("ClassifyError(%q) = %s, expected %s", tc.err, got, tc.expected)
+		}
+	}
+}
+
+// recordingDriver is a database/sql driver that records the arguments of
+// every query and answers with a fixed set of rows
+type recordingDriver struct {
+	columns []string
+	rows    [][]driver.Value
+	args    [][]driver.Value
+}
+
+func (d *recordingDriver) Open(name string) (driver.Conn, error) { return recordingConn{d}, nil }
+
+type recordingConn struct{ driver *recordingDriver }
+
+func (c recordingConn) Prepare(query string) (driver.Stmt, error) { return recordingStmt(c), nil }
+func (c recordingConn) Close() error                              { return nil }
+func (c recordingConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+type recordingStmt struct{ driver *recordingDriver }
+
+func (s recordingStmt) Close() error  { return nil }
+func (s recordingStmt) NumInput() int { return -1 }
+func (s recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (s recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.driver.args = append(s.driver.args, args)
+	return &recordingRows{columns: s.driver.columns, rows: s.driver.rows}, nil
+}
+
+type recordingRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *recordingRows) Columns() []string { return r.columns }
+func (r *recordingRows) Close() error      { return nil }
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestPostgresConnector_GetColumns_Qualified(t *testing.T) {
+	recorder := &recordingDriver{
+		columns: []string{"column_name", "data_type", "is_nullable", "column_default", "is_primary_key"},
+		rows:    [][]driver.Value{{"id", "integer", "NO", nil, true}, {"amount", "numeric", "YES", nil, false}},
+	}
+	sql.Register("recording-postgres", recorder)
+	db, err := sql.Open("recording-postgres", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	connector := NewPostgresConnector(ConnectionConfig{})
+	connector.db = db
+
+	for _, tc := range []struct{ table, name, schema string }{
+		{"orders", "orders", ""},
+		{"sales.orders", "orders", "sales"},
+		{`"Sales"."Orders"`, "Orders", "Sales"},
+	} {
+		recorder.args = nil
+		columns, err := connector.GetColumns(context.Background(), tc.table)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.table, err)
+		}
+		if len(recorder.args) != 1 || recorder.args[0][0] != tc.name || recorder.args[0][1] != tc.schema {
+			t.Errorf("%s: expected table %q in schema %q, got %v", tc.table, tc.name, tc.schema, recorder.args)
+		}
+		if len(columns) != 2 || !columns[0].IsPrimaryKey || columns[1].IsPrimaryKey {
+			t.Errorf("%s: expected id as the only primary key column, got %+v", tc.table, columns)
+		}
+	}
+}
@@ -0,0 +1,452 @@
+package datasource
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Dialect generates engine-specific SQL fragments for a datasource type
+type Dialect struct {
+	Type Type
+}
+
+// DialectFor returns the SQL dialect for a datasource type
+func DialectFor(dsType Type) Dialect {
+	return Dialect{Type: dsType}
+}
+
+// SampleMethod represents a table sampling method
+type SampleMethod string
+
+const (
+	SampleSystem    SampleMethod = "system"    // Block-level sampling: fastest, least uniform
+	SampleBernoulli SampleMethod = "bernoulli" // Row-level random sampling
+	SampleHash      SampleMethod = "hash"      // Deterministic sampling on a hashed key column
+)
+
+// Sample configures table sampling for queries on very large tables.
+// Exactly one of Percentage or RowCount must be set.
+type Sample struct {
+	Percentage float64      `json:"percentage,omitempty"` // 0 < percentage <= 100
+	RowCount   int64        `json:"row_count,omitempty"`
+	Method     SampleMethod `json:"method,omitempty"` // Defaults to bernoulli
+	Seed       int64        `json:"seed,omitempty"`
+	KeyColumn  string       `json:"key_column,omitempty"` // Required for hash sampling
+}
+
+// Validate validates the sample configuration
+func (s *Sample) Validate() error {
+	if (s.Percentage > 0) == (s.RowCount > 0) {
+		return fmt.Errorf("sample requires exactly one of percentage or row_count")
+	}
+	if s.Percentage < 0 || s.Percentage > 100 {
+		return fmt.Errorf("sample percentage must be between 0 and 100")
+	}
+	switch s.Method {
+	case "", SampleSystem, SampleBernoulli:
+	case SampleHash:
+		if s.KeyColumn == "" {
+			return fmt.Errorf("hash sampling requires a key column")
+		}
+		if s.RowCount > 0 {
+			return fmt.Errorf("hash sampling supports percentage only")
+		}
+	default:
+		return fmt.Errorf("unsupported sample method: %s", s.Method)
+	}
+	return nil
+}
+
+// SampledTable returns a subquery selecting a sample of a table. The result
+// is parenthesized and must be given an alias by the caller.
+func (d Dialect) SampledTable(table string, s Sample) (string, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+	method := s.Method
+	if method == "" {
+		method = SampleBernoulli
+	}
+
+	if method == SampleHash {
+		return d.hashSample(table, s)
+	}
+	if s.Seed != 0 && !d.seeded(method, s.RowCount > 0) {
+		kind := fmt.Sprintf("%s samples", method)
+		if s.RowCount > 0 {
+			kind = "row count samples"
+		}
+		return "", fmt.Errorf("seed is not supported for %s on %s", kind, d.Type)
+	}
+	if s.RowCount > 0 {
+		return d.rowSample(table, s)
+	}
+
+	p := formatPercentage(s.Percentage)
+	switch d.Type {
+	case TypePostgres:
+		clause := fmt.Sprintf("TABLESAMPLE %s (%s)", sampleKeyword(method), p)
+		if s.Seed != 0 {
+			clause += fmt.Sprintf(" REPEATABLE (%d)", s.Seed)
+		}
+		return fmt.Sprintf("(SELECT * FROM %s %s)", table, clause), nil
+	case TypeTrino:
+		return fmt.Sprintf("(SELECT * FROM %s TABLESAMPLE %s (%s))", table, sampleKeyword(method), p), nil
+	case TypeSnowflake:
+		clause := fmt.Sprintf("SAMPLE %s (%s)", sampleKeyword(method), p)
+		if s.Seed != 0 {
+			clause += fmt.Sprintf(" SEED (%d)", s.Seed)
+		}
+		return fmt.Sprintf("(SELECT * FROM %s %s)", table, clause), nil
+	case TypeOracle:
+		keyword := "SAMPLE"
+		if method == SampleSystem {
+			keyword = "SAMPLE BLOCK"
+		}
+		clause := fmt.Sprintf("%s (%s)", keyword, p)
+		if s.Seed != 0 {
+			clause += fmt.Sprintf(" SEED (%d)", s.Seed)
+		}
+		return fmt.Sprintf("(SELECT * FROM %s %s)", table, clause), nil
+	case TypeDuckDB:
+		options := sampleKeyword(method)
+		if s.Seed != 0 {
+			options += fmt.Sprintf(", %d", s.Seed)
+		}
+		return fmt.Sprintf("(SELECT * FROM %s USING SAMPLE %s PERCENT (%s))", table, p, options), nil
+	case TypeDatabricks:
+		clause := fmt.Sprintf("TABLESAMPLE (%s PERCENT)", p)
+		if s.Seed != 0 {
+			clause += fmt.Sprintf(" REPEATABLE (%d)", s.Seed)
+		}
+		return fmt.Sprintf("(SELECT * FROM %s %s)", table, clause), nil
+	case TypeClickHouse:
+		return fmt.Sprintf("(SELECT * FROM %s SAMPLE %g)", table, s.Percentage/100), nil
+	case TypeBigQuery:
+		if method == SampleSystem {
+			return fmt.Sprintf("(SELECT * FROM %s TABLESAMPLE SYSTEM (%s PERCENT))", table, p), nil
+		}
+		return fmt.Sprintf("(SELECT * FROM %s WHERE RAND() < %g)", table, s.Percentage/100), nil
+	case TypeSQLServer:
+		if method == SampleSystem {
+			clause := fmt.Sprintf("TABLESAMPLE SYSTEM (%s PERCENT)", p)
+			if s.Seed != 0 {
+				clause += fmt.Sprintf(" REPEATABLE (%d)", s.Seed)
+			}
+			return fmt.Sprintf("(SELECT * FROM %s %s)", table, clause), nil
+		}
+		return fmt.Sprintf("(SELECT * FROM %s WHERE RAND(CHECKSUM(NEWID())) < %g)", table, s.Percentage/100), nil
+	case TypeMySQL:
+		random := "RAND()"
+		if s.Seed != 0 {
+			random = fmt.Sprintf("RAND(%d)", s.Seed)
+		}
+		return fmt.Sprintf("(SELECT * FROM %s WHERE %s < %g)", table, random, s.Percentage/100), nil
+	default:
+		return "", fmt.Errorf("sampling not supported for datasource type: %s", d.Type)
+	}
+}
+
+// Seedable reports whether the engine repeats a random sample given a seed
+func (d Dialect) Seedable(s Sample) bool {
+	method := s.Method
+	if method == "" {
+		method = SampleBernoulli
+	}
+	return method != SampleHash && d.seeded(method, s.RowCount > 0)
+}
+
+// Repeatable reports whether every evaluation of a sample selects the same
+// rows: hash samples, seeded samples the engine can repeat, and ClickHouse
+// percentage samples, which follow the table's sampling key
+func (d Dialect) Repeatable(s Sample) bool {
+	switch {
+	case s.Method == SampleHash:
+		return true
+	case s.Seed != 0:
+		return d.Seedable(s)
+	default:
+		return d.Type == TypeClickHouse && s.RowCount == 0
+	}
+}
+
+// seeded reports whether the dialect repeats a random sample given a seed
+func (d Dialect) seeded(method SampleMethod, rowCount bool) bool {
+	switch d.Type {
+	case TypeDuckDB:
+		return true
+	case TypePostgres, TypeSnowflake, TypeOracle, TypeDatabricks, TypeMySQL:
+		return !rowCount
+	case TypeSQLServer:
+		return !rowCount && method == SampleSystem
+	default:
+		return false
+	}
+}
+
+// rowSample samples a fixed number of rows
+func (d Dialect) rowSample(table string, s Sample) (string, error) {
+	switch d.Type {
+	case TypeSnowflake:
+		return fmt.Sprintf("(SELECT * FROM %s SAMPLE (%d ROWS))", table, s.RowCount), nil
+	case TypeDatabricks:
+		return fmt.Sprintf("(SELECT * FROM %s TABLESAMPLE (%d ROWS))", table, s.RowCount), nil
+	case TypeSQLServer:
+		return fmt.Sprintf("(SELECT * FROM %s TABLESAMPLE (%d ROWS))", table, s.RowCount), nil
+	case TypeDuckDB:
+		if s.Seed != 0 {
+			return fmt.Sprintf("(SELECT * FROM %s USING SAMPLE %d ROWS (reservoir, %d))", table, s.RowCount, s.Seed), nil
+		}
+		return fmt.Sprintf("(SELECT * FROM %s USING SAMPLE %d ROWS)", table, s.RowCount), nil
+	case TypeOracle:
+		return fmt.Sprintf("(SELECT * FROM %s ORDER BY DBMS_RANDOM.VALUE FETCH FIRST %d ROWS ONLY)", table, s.RowCount), nil
+	}
+
+	random, err := d.randomFunc()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("(SELECT * FROM %s ORDER BY %s LIMIT %d)", table, random, s.RowCount), nil
+}
+
+// hashSample deterministically samples rows whose hashed key falls in the
+// first percentage of 10000 buckets; the seed rotates the selected buckets.
+// The hash is reduced before the seed is added, so that neither overflows.
+func (d Dialect) hashSample(table string, s Sample) (string, error) {
+	hash, err := d.HashExpr(s.KeyColumn)
+	if err != nil {
+		return "", err
+	}
+	buckets := int64(math.Round(s.Percentage * 100))
+	seed := (s.Seed%10000 + 10000) % 10000
+	bucket := d.Mod(fmt.Sprintf("%s + %d", d.Mod(hash, 10000), 10000+seed), 10000)
+	return fmt.Sprintf("(SELECT * FROM %s WHERE %s < %d)", table, bucket, buckets), nil
+}
+
+// HashExpr returns an integer hash of a column expression
+func (d Dialect) HashExpr(expr string) (string, error) {
+	switch d.Type {
+	case TypePostgres:
+		return fmt.Sprintf("hashtext(CAST(%s AS TEXT))", expr), nil
+	case TypeMySQL:
+		return fmt.Sprintf("CRC32(%s)", expr), nil
+	case TypeSQLServer:
+		return fmt.Sprintf("CHECKSUM(%s)", expr), nil
+	case TypeOracle:
+		return fmt.Sprintf("ORA_HASH(%s)", expr), nil
+	case TypeSnowflake:
+		return fmt.Sprintf("HASH(%s)", expr), nil
+	case TypeBigQuery:
+		return fmt.Sprintf("FARM_FINGERPRINT(CAST(%s AS STRING))", expr), nil
+	case TypeTrino:
+		return fmt.Sprintf("from_big_endian_64(xxhash64(to_utf8(CAST(%s AS VARCHAR))))", expr), nil
+	case TypeDuckDB, TypeDatabricks:
+		return fmt.Sprintf("hash(%s)", expr), nil
+	case TypeClickHouse:
+		return fmt.Sprintf("cityHash64(%s)", expr), nil
+	default:
+		return "", fmt.Errorf("hashing not supported for datasource type: %s", d.Type)
+	}
+}
+
+// RowChecksum returns an integer hash of the text of columns that engines
+// compute alike: the first 8 hex digits of the MD5 of the columns' text,
+// joined by '|' with NULL as '<NULL>', as an unsigned 32-bit value. Rows of
+// two engines hash equal when their columns render to the same text.
+func (d Dialect) RowChecksum(columns []string) (string, error) {
+	parts := make([]string, len(columns))
+	for i, col := range columns {
+		parts[i] = fmt.Sprintf("COALESCE(%s, '<NULL>')", d.CastText(col))
+	}
+
+	switch d.Type {
+	case TypePostgres:
+		return fmt.Sprintf("('x' || SUBSTR(MD5(%s), 1, 8))::BIT(32)::BIGINT", strings.Join(parts, " || '|' || ")), nil
+	case TypeMySQL:
+		return fmt.Sprintf("CAST(CONV(SUBSTRING(MD5(CONCAT_WS('|', %s)), 1, 8), 16, 10) AS UNSIGNED)", strings.Join(parts, ", ")), nil
+	case TypeSnowflake:
+		return fmt.Sprintf("TO_NUMBER(SUBSTR(MD5(%s), 1, 8), 'XXXXXXXX')", strings.Join(parts, " || '|' || ")), nil
+	case TypeOracle:
+		return fmt.Sprintf("TO_NUMBER(SUBSTR(LOWER(RAWTOHEX(STANDARD_HASH(%s, 'MD5'))), 1, 8), 'xxxxxxxx')", strings.Join(parts, " || '|' || ")), nil
+	case TypeBigQuery:
+		return fmt.Sprintf("CAST(CONCAT('0x', SUBSTR(TO_HEX(MD5(CONCAT(%s))), 1, 8)) AS INT64)", strings.Join(parts, ", '|', ")), nil
+	case TypeTrino:
+		return fmt.Sprintf("from_base(substr(lower(to_hex(md5(to_utf8(%s)))), 1, 8), 16)", strings.Join(parts, " || '|' || ")), nil
+	case TypeDatabricks:
+		return fmt.Sprintf("CAST(CONV(SUBSTR(MD5(CONCAT_WS('|', %s)), 1, 8), 16, 10) AS BIGINT)", strings.Join(parts, ", ")), nil
+	case TypeSQLServer:
+		return fmt.Sprintf("CAST(CAST(SUBSTRING(HASHBYTES('MD5', CAST(CONCAT_WS('|', %s) AS VARCHAR(MAX))), 1, 4) AS BINARY(4)) AS BIGINT)", strings.Join(parts, ", ")), nil
+	default:
+		return "", fmt.Errorf("row checksums not supported for datasource type: %s", d.Type)
+	}
+}
+
+// Mod returns the remainder of expr divided by n
+func (d Dialect) Mod(expr string, n int64) string {
+	switch d.Type {
+	case TypeSQLServer, TypeClickHouse:
+		return fmt.Sprintf("(%s) %% %d", expr, n)
+	default:
+		return fmt.Sprintf("MOD(%s, %d)", expr, n)
+	}
+}
+
+// CountDistinct returns an expression counting distinct values of expr. When
+// approximate is set and the engine has a HyperLogLog-based function it is
+// used instead; the second result reports whether the count is approximate.
+func (d Dialect) CountDistinct(expr string, approximate bool) (string, bool) {
+	if approximate {
+		switch d.Type {
+		case TypeSnowflake, TypeBigQuery, TypeDatabricks, TypeDuckDB, TypeOracle, TypeSQLServer:
+			return fmt.Sprintf("APPROX_COUNT_DISTINCT(%s)", expr), true
+		case TypeTrino:
+			return fmt.Sprintf("approx_distinct(%s)", expr), true
+		case TypeClickHouse:
+			return fmt.Sprintf("uniq(%s)", expr), true
+		}
+	}
+	return fmt.Sprintf("COUNT(DISTINCT %s)", expr), false
+}
+
+// StdDev returns the sample standard deviation of expr
+func (d Dialect) StdDev(expr string) string {
+	switch d.Type {
+	case TypeSQLServer:
+		return fmt.Sprintf("STDEV(%s)", expr)
+	case TypeClickHouse:
+		return fmt.Sprintf("stddevSamp(%s)", expr)
+	default:
+		return fmt.Sprintf("STDDEV_SAMP(%s)", expr)
+	}
+}
+
+// Length returns the character length of a string expression
+func (d Dialect) Length(expr string) string {
+	switch d.Type {
+	case TypeSQLServer:
+		return fmt.Sprintf("LEN(%s)", expr)
+	case TypeMySQL:
+		return fmt.Sprintf("CHAR_LENGTH(%s)", expr)
+	case TypeClickHouse:
+		return fmt.Sprintf("lengthUTF8(%s)", expr)
+	default:
+		return fmt.Sprintf("LENGTH(%s)", expr)
+	}
+}
+
+// StringLiteral returns s as a quoted string literal. Backslashes are escaped
+// too for engines that process backslash escapes in literals.
+func (d Dialect) StringLiteral(s string) string {
+	switch d.Type {
+	case TypeBigQuery, TypeDatabricks:
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+	case TypeMySQL, TypeClickHouse:
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(s) + "'"
+	default:
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	}
+}
+
+// CastText casts expr to the engine's string type
+func (d Dialect) CastText(expr string) string {
+	switch d.Type {
+	case TypePostgres:
+		return fmt.Sprintf("CAST(%s AS TEXT)", expr)
+	case TypeBigQuery, TypeDatabricks:
+		return fmt.Sprintf("CAST(%s AS STRING)", expr)
+	case TypeMySQL:
+		return fmt.Sprintf("CAST(%s AS CHAR)", expr)
+	case TypeSQLServer:
+		return fmt.Sprintf("CAST(%s AS NVARCHAR(MAX))", expr)
+	case TypeOracle:
+		return fmt.Sprintf("TO_CHAR(%s)", expr)
+	case TypeClickHouse:
+		return fmt.Sprintf("toString(%s)", expr)
+	default:
+		return fmt.Sprintf("CAST(%s AS VARCHAR)", expr)
+	}
+}
+
+// RegexMatch returns a boolean condition that is true when expr matches a
+// POSIX-style regular expression. Quotes in the pattern are escaped; engines
+// that process backslash escapes in literals may alter patterns using them,
+// so portable patterns avoid backslashes.
+func (d Dialect) RegexMatch(expr, pattern string) (string, error) {
+	quoted := strings.ReplaceAll(pattern, "'", "''")
+	switch d.Type {
+	case TypePostgres:
+		return fmt.Sprintf("%s ~ '%s'", expr, quoted), nil
+	case TypeMySQL:
+		return fmt.Sprintf("%s REGEXP '%s'", expr, quoted), nil
+	case TypeDatabricks:
+		return fmt.Sprintf("%s RLIKE '%s'", expr, quoted), nil
+	case TypeSnowflake, TypeOracle:
+		return fmt.Sprintf("REGEXP_LIKE(%s, '%s')", expr, quoted), nil
+	case TypeTrino:
+		return fmt.Sprintf("regexp_like(%s, '%s')", expr, quoted), nil
+	case TypeBigQuery:
+		escaped := strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(pattern)
+		return fmt.Sprintf("REGEXP_CONTAINS(%s, '%s')", expr, escaped), nil
+	case TypeDuckDB:
+		return fmt.Sprintf("regexp_matches(%s, '%s')", expr, quoted), nil
+	case TypeClickHouse:
+		return fmt.Sprintf("match(%s, '%s')", expr, quoted), nil
+	default:
+		return "", fmt.Errorf("regex matching not supported for datasource type: %s", d.Type)
+	}
+}
+
+// LimitClause returns the clause limiting a query to n rows. On SQL Server it
+// requires the query to have an ORDER BY.
+func (d Dialect) LimitClause(n int) string {
+	switch d.Type {
+	case TypeOracle:
+		return fmt.Sprintf("FETCH FIRST %d ROWS ONLY", n)
+	case TypeSQLServer:
+		return fmt.Sprintf("OFFSET 0 ROWS FETCH NEXT %d ROWS ONLY", n)
+	default:
+		return fmt.Sprintf("LIMIT %d", n)
+	}
+}
+
+// TimestampLiteral returns a literal for t in UTC with microsecond precision
+func (d Dialect) TimestampLiteral(t time.Time) string {
+	formatted := t.UTC().Format("2006-01-02 15:04:05.000000")
+	switch d.Type {
+	case TypeSQLServer:
+		return fmt.Sprintf("CAST('%s' AS DATETIME2)", formatted)
+	case TypeClickHouse:
+		return fmt.Sprintf("toDateTime64('%s', 6)", formatted)
+	default:
+		return fmt.Sprintf("TIMESTAMP '%s'", formatted)
+	}
+}
+
+// randomFunc returns the engine's uniform random number function
+func (d Dialect) randomFunc() (string, error) {
+	switch d.Type {
+	case TypePostgres, TypeDuckDB:
+		return "RANDOM()", nil
+	case TypeMySQL, TypeBigQuery, TypeDatabricks:
+		return "RAND()", nil
+	case TypeTrino:
+		return "random()", nil
+	case TypeClickHouse:
+		return "rand()", nil
+	default:
+		return "", fmt.Errorf("random sampling not supported for datasource type: %s", d.Type)
+	}
+}
+
+func sampleKeyword(method SampleMethod) string {
+	if method == SampleSystem {
+		return "SYSTEM"
+	}
+	return "BERNOULLI"
+}
+
+func formatPercentage(p float64) string {
+	return fmt.Sprintf("%g", p)
+}
@@ -0,0 +1,95 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// ErrorClass tells whether a failed query is worth retrying
+type ErrorClass string
+
+const (
+	ErrorTransient ErrorClass = "transient" // May succeed when retried: network blips, timeouts, serialization failures, rate limits
+	ErrorPermanent ErrorClass = "permanent" // Fails again when retried: bad SQL, missing tables, permissions, budgets
+)
+
+// transientSQLStates are the SQLSTATE codes of failures that a retry may clear
+var transientSQLStates = []string{
+	"40001", // serialization_failure
+	"40P01", // deadlock_detected
+	"08000", // connection_exception
+	"08003", // connection_does_not_exist
+	"08006", // connection_failure
+	"57P01", // admin_shutdown
+	"53300", // too_many_connections
+}
+
+// transientMessages are fragments of the messages drivers and warehouse APIs
+// return for transient failures, lower case
+var transientMessages = []string{
+	"connection reset",
+	"connection refused",
+	"broken pipe",
+	"bad connection",
+	"i/o timeout",
+	"timeout exceeded",
+	"timed out",
+	"could not serialize access",
+	"serialization failure",
+	"deadlock",
+	"rate limit",
+	"ratelimit",
+	"too many requests",
+	"too many connections",
+	"throttl",
+	"status 429",
+	"status 503",
+	"service unavailable",
+	"temporarily unavailable",
+	"try again",
+}
+
+// ClassifyError tells whether a query error is transient or permanent.
+// Cancellation and budget errors are permanent: retrying them cannot succeed
+// within the same run.
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ErrorPermanent
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrQueryBudgetExceeded) {
+		return ErrorPermanent
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, net.ErrClosed) {
+		return ErrorTransient
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorTransient
+	}
+
+	message := strings.ToLower(err.Error())
+	if strings.Contains(message, "sqlstate") {
+		for _, state := range transientSQLStates {
+			if strings.Contains(message, strings.ToLower(state)) {
+				return ErrorTransient
+			}
+		}
+	}
+	for _, fragment := range transientMessages {
+		if strings.Contains(message, fragment) {
+			return ErrorTransient
+		}
+	}
+	return ErrorPermanent
+}
+
+// IsTransient reports whether a query error may succeed when retried
+func IsTransient(err error) bool {
+	return ClassifyError(err) == ErrorTransient
+}
@@ -0,0 +1,179 @@
+package datasource
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// partitionFile is a data file together with the partition it belongs to,
+// as recorded in table format metadata (Delta add actions, Iceberg manifest
+// entries) or encoded in a Hive-style storage path
+type partitionFile struct {
+	Keys         []string
+	Values       map[string]string
+	RowCount     int64
+	SizeBytes    int64
+	LastModified time.Time
+}
+
+// groupPartitionFiles aggregates file-level metadata into per-partition totals
+func groupPartitionFiles(files []partitionFile) []PartitionInfo {
+	byName := make(map[string]*PartitionInfo)
+	for _, f := range files {
+		if len(f.Keys) == 0 {
+			continue
+		}
+		name := hivePartitionName(f.Keys, f.Values)
+		p, exists := byName[name]
+		if !exists {
+			p = &PartitionInfo{
+				Name:   name,
+				Values: f.Values,
+			}
+			byName[name] = p
+		}
+		p.RowCount += f.RowCount
+		p.SizeBytes += f.SizeBytes
+		p.FileCount++
+		if !f.LastModified.IsZero() && (p.LastModified == nil || f.LastModified.After(*p.LastModified)) {
+			modified := f.LastModified
+			p.LastModified = &modified
+		}
+	}
+
+	partitions := make([]PartitionInfo, 0, len(byName))
+	for _, p := range byName {
+		partitions = append(partitions, *p)
+	}
+	sort.Slice(partitions, func(i, j int) bool {
+		return partitions[i].Name < partitions[j].Name
+	})
+	return partitions
+}
+
+// splitQualifiedName splits a schema.table name into its unquoted schema,
+// empty when unqualified, and table
+func splitQualifiedName(table string) (string, string) {
+	schema, name := "", table
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		schema, name = table[:i], table[i+1:]
+	}
+	return strings.Trim(schema, `"`), strings.Trim(name, `"`)
+}
+
+// hivePartitionName builds a Hive-style partition name such as dt=2024-01-01/hour=03
+func hivePartitionName(keys []string, values map[string]string) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + "=" + values[key]
+	}
+	return strings.Join(parts, "/")
+}
+
+// ParseHivePartitionPath extracts partition keys and values from a Hive-style
+// storage path such as events/dt=2024-01-01/hour=03/part-0000.parquet.
+// Keys are returned in path order.
+func ParseHivePartitionPath(path string) ([]string, map[string]string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	// The last segment is the file name
+	if len(segments) > 0 {
+		segments = segments[:len(segments)-1]
+	}
+
+	var keys []string
+	values := make(map[string]string)
+	for _, segment := range segments {
+		idx := strings.Index(segment, "=")
+		if idx <= 0 {
+			continue
+		}
+		key := segment[:idx]
+		value := segment[idx+1:]
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			value = unescaped
+		}
+		if _, seen := values[key]; !seen {
+			keys = append(keys, key)
+		}
+		values[key] = value
+	}
+	return keys, values
+}
+
+// parsePostgresPartitionKey extracts key columns from pg_get_partkeydef output
+// such as "RANGE (created_at)" or "LIST (region, country)"
+func parsePostgresPartitionKey(def string) []string {
+	start := strings.Index(def, "(")
+	end := strings.LastIndex(def, ")")
+	if start < 0 || end <= start {
+		return nil
+	}
+
+	var keys []string
+	depth := 0
+	current := ""
+	for _, r := range def[start+1 : end] {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			keys = append(keys, strings.TrimSpace(current))
+			current = ""
+			continue
+		}
+		current += string(r)
+	}
+	if strings.TrimSpace(current) != "" {
+		keys = append(keys, strings.TrimSpace(current))
+	}
+	return keys
+}
+
+// parsePostgresListBound extracts the value of a single-value list partition
+// bound such as "FOR VALUES IN ('eu')"
+func parsePostgresListBound(bound string) (string, bool) {
+	const prefix = "FOR VALUES IN ("
+	if !strings.HasPrefix(bound, prefix) || !strings.HasSuffix(bound, ")") {
+		return "", false
+	}
+	inner := strings.TrimSuffix(strings.TrimPrefix(bound, prefix), ")")
+	if strings.Contains(inner, ",") {
+		return "", false
+	}
+	return strings.Trim(inner, "'"), true
+}
+
+// rowString returns a query result value as a string, treating NULL as empty
+func rowString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(val)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// rowInt64 returns a numeric query result value as int64
+func rowInt64(v interface{}) int64 {
+	switch val := v.(type) {
+	case int64:
+		return val
+	case int:
+		return int64(val)
+	case int32:
+		return int64(val)
+	case float64:
+		return int64(val)
+	case float32:
+		return int64(val)
+	default:
+		return 0
+	}
+}
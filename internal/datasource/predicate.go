@@ -0,0 +1,53 @@
+package datasource
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidatePredicate rejects raw predicates that could end the statement or
+// escape the WHERE clause they are placed in
+func ValidatePredicate(predicate string) error {
+	if predicate == "" {
+		return nil
+	}
+	if strings.TrimSpace(predicate) == "" {
+		return fmt.Errorf("where predicate is blank")
+	}
+
+	depth := 0
+	inString := false
+	for i := 0; i < len(predicate); i++ {
+		c := predicate[i]
+		if inString {
+			if c == '\'' {
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '\'':
+			inString = true
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("where predicate has an unmatched closing parenthesis")
+			}
+		case ';':
+			return fmt.Errorf("where predicate must not contain ';'")
+		case '-', '/':
+			if i+1 < len(predicate) && (predicate[i:i+2] == "--" || predicate[i:i+2] == "/*") {
+				return fmt.Errorf("where predicate must not contain comments")
+			}
+		}
+	}
+	if inString {
+		return fmt.Errorf("where predicate has an unterminated string literal")
+	}
+	if depth != 0 {
+		return fmt.Errorf("where predicate has an unmatched opening parenthesis")
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+// Package expr compiles row-level rules written in CEL, the Common Expression
+// Language, against the columns of a table. A compiled rule is evaluated on
+// rows in Go, or translated to a SQL condition when every operator it uses
+// has an equivalent in the engine's dialect.
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	celast "github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types"
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// ErrNoSQL is returned by SQL for expressions using an operator, function or
+// type that has no SQL translation in the dialect
+var ErrNoSQL = errors.New("expression cannot be translated to SQL")
+
+// Expression is a rule compiled and type-checked against a table's columns
+type Expression struct {
+	source  string
+	ast     *celast.AST
+	program cel.Program
+	types   map[string]*cel.Type // Declared column types by name
+	columns []string             // Referenced columns, sorted
+}
+
+// Parse checks the syntax of an expression without resolving its columns
+func Parse(source string) error {
+	if strings.TrimSpace(source) == "" {
+		return fmt.Errorf("expression is empty")
+	}
+	env, err := cel.NewEnv()
+	if err != nil {
+		return err
+	}
+	if _, iss := env.Parse(source); iss.Err() != nil {
+		return issuesError(iss)
+	}
+	return nil
+}
+
+// Compile parses and type-checks an expression against a table's columns.
+// Columns are nullable and typed from their data type (see ColumnType); the
+// expression must reference declared columns only and yield a bool.
+func Compile(source string, columns []datasource.ColumnInfo) (*Expression, error) {
+	if err := Parse(source); err != nil {
+		return nil, err
+	}
+
+	e := &Expression{source: source, types: make(map[string]*cel.Type, len(columns))}
+	opts := []cel.EnvOption{cel.CrossTypeNumericComparisons(true)}
+	for _, col := range columns {
+		t := ColumnType(col.DataType)
+		e.types[col.Name] = t
+		opts = append(opts, cel.Variable(col.Name, cel.NullableType(t)))
+	}
+	env, err := cel.NewEnv(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid columns: %w", err)
+	}
+
+	checked, iss := env.Compile(source)
+	if iss.Err() != nil {
+		return nil, issuesError(iss)
+	}
+	if out := checked.OutputType(); out.Kind() != types.BoolKind {
+		return nil, fmt.Errorf("expression must yield a bool, not %s", out)
+	}
+	if e.program, err = env.Program(checked); err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	e.ast = checked.NativeRep()
+
+	seen := make(map[string]bool)
+	celast.PreOrderVisit(e.ast.Expr(), celast.NewExprVisitor(func(node celast.Expr) {
+		if node.Kind() == celast.IdentKind {
+			if name := node.AsIdent(); e.types[name] != nil && !seen[name] {
+				seen[name] = true
+				e.columns = append(e.columns, name)
+			}
+		}
+	}))
+	sort.Strings(e.columns)
+	return e, nil
+}
+
+// ColumnType maps an engine data type name to the CEL type of its values:
+// int for integer types, double for other numeric types, bool, timestamp
+// for date and time types, and string for everything else
+func ColumnType(dataType string) *cel.Type {
+	t := strings.ToLower(dataType)
+	switch {
+	case strings.Contains(t, "interval") || strings.Contains(t, "point"):
+		return cel.StringType
+	case strings.Contains(t, "bool") || t == "bit":
+		return cel.BoolType
+	case strings.Contains(t, "time") || strings.Contains(t, "date"):
+		return cel.TimestampType
+	case strings.Contains(t, "int"):
+		return cel.IntType
+	case strings.Contains(t, "numeric") || strings.Contains(t, "decimal") || strings.Contains(t, "float") ||
+		strings.Contains(t, "double") || strings.Contains(t, "real") || strings.Contains(t, "number") ||
+		strings.Contains(t, "money"):
+		return cel.DoubleType
+	default:
+		return cel.StringType
+	}
+}
+
+// issuesError renders CEL compile issues as one error with 1-based positions
+func issuesError(iss *cel.Issues) error {
+	var msgs []string
+	for _, e := range iss.Errors() {
+		msg := strings.TrimSuffix(e.Message, " (in container '')")
+		if loc := e.Location; loc != nil && loc.Line() > 0 {
+			msg = fmt.Sprintf("%s at %d:%d", msg, loc.Line(), loc.Column()+1)
+		}
+		msgs = append(msgs, msg)
+	}
+	return fmt.Errorf("invalid expression: %s", strings.Join(msgs, "; "))
+}
+
+// Source returns the expression's text
+func (e *Expression) Source() string {
+	return e.source
+}
+
+// Columns returns the columns the expression references, sorted
+func (e *Expression) Columns() []string {
+	return e.columns
+}
+
+// Eval evaluates the expression on a row, keyed by column name in any case.
+// Values are converted from driver types to the columns' CEL types; NULLs
+// compare equal to null and make arithmetic and ordering fail with an error.
+func (e *Expression) Eval(row map[string]interface{}) (bool, error) {
+	vars := make(map[string]interface{}, len(e.columns))
+	for _, col := range e.columns {
+		v, ok := row[col]
+		if !ok {
+			for k, rv := range row {
+				if strings.EqualFold(k, col) {
+					v = rv
+					break
+				}
+			}
+		}
+		converted, err := convert(v, e.types[col])
+		if err != nil {
+			return false, fmt.Errorf("column %s: %w", col, err)
+		}
+		vars[col] = converted
+	}
+
+	out, _, err := e.program.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := out.Value().(bool)
+	return ok && b, nil
+}
+
+// convert converts a driver value to a CEL type, or to null
+func convert(v interface{}, t *cel.Type) (interface{}, error) {
+	if v == nil {
+		return types.NullValue, nil
+	}
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+
+	switch t.Kind() {
+	case types.IntKind:
+		switch val := v.(type) {
+		case int64:
+			return val, nil
+		case int:
+			return int64(val), nil
+		case int32:
+			return int64(val), nil
+		case int16:
+			return int64(val), nil
+		case int8:
+			return int64(val), nil
+		case uint64:
+			return int64(val), nil
+		case uint32:
+			return int64(val), nil
+		case float64:
+			if val == float64(int64(val)) {
+				return int64(val), nil
+			}
+		case string:
+			if n, err := strconv.ParseInt(strings.TrimSpace(val), 10, 64); err == nil {
+				return n, nil
+			}
+		}
+		return nil, fmt.Errorf("%v is not an integer", v)
+	case types.DoubleKind:
+		switch val := v.(type) {
+		case float64:
+			return val, nil
+		case float32:
+			return float64(val), nil
+		case int64:
+			return float64(val), nil
+		case int:
+			return float64(val), nil
+		case int32:
+			return float64(val), nil
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+				return f, nil
+			}
+		}
+		return nil, fmt.Errorf("%v is not a number", v)
+	case types.BoolKind:
+		switch val := v.(type) {
+		case bool:
+			return val, nil
+		case int64:
+			return val != 0, nil
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(val)); err == nil {
+				return b, nil
+			}
+		}
+		return nil, fmt.Errorf("%v is not a bool", v)
+	case types.TimestampKind:
+		switch val := v.(type) {
+		case time.Time:
+			return val.UTC(), nil
+		case string:
+			for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02"} {
+				if ts, err := time.Parse(layout, val); err == nil {
+					return ts.UTC(), nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("%v is not a timestamp", v)
+	default:
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		if ts, ok := v.(time.Time); ok {
+			return ts.UTC().Format(time.RFC3339Nano), nil
+		}
+		return fmt.Sprint(v), nil
+	}
+}
@@ -0,0 +1,146 @@
+package expr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+var orderColumns = []datasource.ColumnInfo{
+	{Name: "id", DataType: "bigint"},
+	{Name: "price", DataType: "numeric(10,2)"},
+	{Name: "discount", DataType: "double precision"},
+	{Name: "quantity", DataType: "integer"},
+	{Name: "tier", DataType: "varchar(20)"},
+	{Name: "paid", DataType: "boolean"},
+	{Name: "created_at", DataType: "timestamp with time zone"},
+}
+
+func TestCompile(t *testing.T) {
+	e, err := Compile(`discount <= price * 0.5 || tier == "vip"`, orderColumns)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(e.Columns(), ","); got != "discount,price,tier" {
+		t.Errorf("expected referenced columns discount,price,tier, got %s", got)
+	}
+
+	testCases := []struct {
+		source   string
+		expected string
+	}{
+		{`missing > 1`, "undeclared reference to 'missing' at 1:1"},
+		{`tier > 1`, "found no matching overload for '_>_'"},
+		{`price * 2.0`, "must yield a bool"},
+		{`price >`, "Syntax error"},
+		{` `, "expression is empty"},
+	}
+	for _, tc := range testCases {
+		if _, err := Compile(tc.source, orderColumns); err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("%q: expected error containing %q, got %v", tc.source, tc.expected, err)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	e, err := Compile(`discount <= price * 0.5 || tier == "vip"`, orderColumns)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		row      map[string]interface{}
+		expected bool
+		wantErr  bool
+	}{
+		{"within limit", map[string]interface{}{"discount": 4.0, "price": []byte("10.00"), "tier": "basic"}, true, false},
+		{"over limit", map[string]interface{}{"discount": 6.0, "price": []byte("10.00"), "tier": "basic"}, false, false},
+		{"vip over limit", map[string]interface{}{"DISCOUNT": 6.0, "PRICE": 10.0, "TIER": "vip"}, true, false},
+		{"null discount", map[string]interface{}{"discount": nil, "price": 10.0, "tier": "basic"}, false, true},
+		{"null discount for vip", map[string]interface{}{"discount": nil, "price": 10.0, "tier": "vip"}, true, false},
+		{"bad number", map[string]interface{}{"discount": 1.0, "price": "n/a", "tier": "basic"}, false, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := e.Eval(tc.row)
+			if (err != nil) != tc.wantErr || got != tc.expected {
+				t.Errorf("expected %v (error %v), got %v, %v", tc.expected, tc.wantErr, got, err)
+			}
+		})
+	}
+
+	nullable, err := Compile(`discount == null || created_at < timestamp("2024-01-01T00:00:00Z")`, orderColumns)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for row, expected := range map[*map[string]interface{}]bool{
+		{"discount": nil}: true,
+		{"discount": 1.0, "created_at": time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)}: true,
+		{"discount": 1.0, "created_at": "2024-05-01 10:00:00"}:                        false,
+	} {
+		if got, _ := nullable.Eval(*row); got != expected {
+			t.Errorf("%v: expected %v, got %v", *row, expected, got)
+		}
+	}
+}
+
+func TestSQL(t *testing.T) {
+	testCases := []struct {
+		source   string
+		dsType   datasource.Type
+		expected string
+	}{
+		{`discount <= price * 0.5 || tier == "vip"`, datasource.TypePostgres, "((discount <= (price * 0.5)) OR ((tier = 'vip') AND tier IS NOT NULL))"},
+		{`discount == null || !(tier in ["a", "b"])`, datasource.TypePostgres, "((discount IS NULL) OR (NOT (tier IN ('a', 'b') AND tier IS NOT NULL)))"},
+		{`tier.startsWith("v") && size(tier) > 2`, datasource.TypeSQLServer, "((tier LIKE 'v%') AND (LEN(tier) > 2))"},
+		{`tier.matches("^[a-z]+$")`, datasource.TypeMySQL, "(tier REGEXP '^[a-z]+$')"},
+		{`created_at >= timestamp("2024-01-01T00:00:00Z")`, datasource.TypePostgres, "(created_at >= TIMESTAMP '2024-01-01 00:00:00.000000')"},
+		{`(quantity > 10 ? price * 0.9 : price) > 5.0`, datasource.TypePostgres, "((CASE WHEN (quantity > 10) THEN (price * 0.9) WHEN NOT (quantity > 10) THEN price END) > 5)"},
+		{`paid && quantity >= 1`, datasource.TypePostgres, "(paid AND (quantity >= 1))"},
+		{`tier != "it's"`, datasource.TypeBigQuery, `((tier <> 'it\'s') OR tier IS NULL)`},
+		{`!(quantity == 1)`, datasource.TypePostgres, "(NOT ((quantity = 1) AND quantity IS NOT NULL))"},
+		{`discount == price`, datasource.TypePostgres, "(((discount = price) AND discount IS NOT NULL AND price IS NOT NULL) OR (discount IS NULL AND price IS NULL))"},
+		{`discount == price * 0.5`, datasource.TypePostgres, "(((discount = (price * 0.5)) AND discount IS NOT NULL) OR (discount IS NULL AND (price * 0.5) <> (price * 0.5)))"},
+	}
+	for _, tc := range testCases {
+		e, err := Compile(tc.source, orderColumns)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.source, err)
+		}
+		got, err := e.SQL(datasource.DialectFor(tc.dsType))
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.source, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("%q: expected %s, got %s", tc.source, tc.expected, got)
+		}
+	}
+
+	for _, tc := range []struct {
+		source string
+		dsType datasource.Type
+	}{
+		{`quantity / 2 > 1`, datasource.TypePostgres},
+		{`quantity % 2 == 0`, datasource.TypePostgres},
+		{`tier + "x" == "vipx"`, datasource.TypePostgres},
+		{`tier.contains("5%")`, datasource.TypePostgres},
+		{`paid && quantity >= 1`, datasource.TypeSQLServer},
+		{`tier.matches("^v")`, datasource.TypeSQLServer},
+		{`[1, 2].exists(x, x == quantity)`, datasource.TypePostgres},
+		{`(paid ? price : discount) == 1.0`, datasource.TypePostgres},
+		{`quantity in [1, null]`, datasource.TypePostgres},
+	} {
+		e, err := Compile(tc.source, orderColumns)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.source, err)
+		}
+		if _, err := e.SQL(datasource.DialectFor(tc.dsType)); !errors.Is(err, ErrNoSQL) {
+			t.Errorf("%q on %s: expected ErrNoSQL, got %v", tc.source, tc.dsType, err)
+		}
+	}
+}
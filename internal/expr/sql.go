@@ -0,0 +1,366 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	celast "github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/overloads"
+	"github.com/google/cel-go/common/types"
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// sqlOperators maps CEL comparison and arithmetic operators to SQL
+var sqlOperators = map[string]string{
+	operators.Equals:        "=",
+	operators.NotEquals:     "<>",
+	operators.Less:          "<",
+	operators.LessEquals:    "<=",
+	operators.Greater:       ">",
+	operators.GreaterEquals: ">=",
+	operators.Add:           "+",
+	operators.Subtract:      "-",
+	operators.Multiply:      "*",
+	operators.Divide:        "/",
+}
+
+// SQL translates the expression to a SQL condition in a dialect. Column
+// names are rendered as written, unqualified. The condition holds on the same
+// rows as Eval: equality and membership treat a NULL column as a value, as Go
+// evaluation does, so !(a == 1) holds where a is NULL; ordering, arithmetic
+// and functions on NULL are unknown in SQL where Eval fails, and both count
+// as not holding. Expressions without a translation return an error wrapping
+// ErrNoSQL.
+func (e *Expression) SQL(dialect datasource.Dialect) (string, error) {
+	t := &translator{ast: e.ast, dialect: dialect}
+	return t.translate(e.ast.Expr())
+}
+
+// translator renders a checked CEL AST as SQL
+type translator struct {
+	ast     *celast.AST
+	dialect datasource.Dialect
+}
+
+func noSQL(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrNoSQL, fmt.Sprintf(format, args...))
+}
+
+// booleans reports whether the dialect has boolean values, so that boolean
+// columns, literals and results can appear outside conditions
+func (t *translator) booleans() bool {
+	switch t.dialect.Type {
+	case datasource.TypeSQLServer, datasource.TypeOracle:
+		return false
+	default:
+		return true
+	}
+}
+
+func (t *translator) kind(node celast.Expr) types.Kind {
+	return t.ast.GetType(node.ID()).Kind()
+}
+
+func (t *translator) translate(node celast.Expr) (string, error) {
+	switch node.Kind() {
+	case celast.IdentKind:
+		if t.kind(node) == types.BoolKind && !t.booleans() {
+			return "", noSQL("boolean column %s in %s", node.AsIdent(), t.dialect.Type)
+		}
+		return node.AsIdent(), nil
+	case celast.LiteralKind:
+		return t.literal(node)
+	case celast.CallKind:
+		return t.call(node)
+	default:
+		return "", noSQL("unsupported expression")
+	}
+}
+
+func (t *translator) literal(node celast.Expr) (string, error) {
+	switch v := node.AsLiteral().(type) {
+	case types.Int:
+		return strconv.FormatInt(int64(v), 10), nil
+	case types.Uint:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case types.Double:
+		return strconv.FormatFloat(float64(v), 'f', -1, 64), nil
+	case types.String:
+		return t.dialect.StringLiteral(string(v)), nil
+	case types.Bool:
+		if !t.booleans() {
+			return "", noSQL("boolean literal in %s", t.dialect.Type)
+		}
+		if v {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case types.Null:
+		return "NULL", nil
+	default:
+		return "", noSQL("unsupported literal %v", v)
+	}
+}
+
+func (t *translator) call(node celast.Expr) (string, error) {
+	call := node.AsCall()
+	fn, args := call.FunctionName(), call.Args()
+	if call.IsMemberFunction() {
+		args = append([]celast.Expr{call.Target()}, args...)
+	}
+
+	switch fn {
+	case operators.LogicalAnd, operators.LogicalOr:
+		sqlOp := "AND"
+		if fn == operators.LogicalOr {
+			sqlOp = "OR"
+		}
+		return t.binary(args[0], sqlOp, args[1])
+	case operators.LogicalNot:
+		operand, err := t.translate(args[0])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(NOT %s)", operand), nil
+	case operators.Equals, operators.NotEquals:
+		for i, operand := range args {
+			if operand.Kind() == celast.LiteralKind && operand.AsLiteral() == types.NullValue {
+				other, err := t.translate(args[1-i])
+				if err != nil {
+					return "", err
+				}
+				if fn == operators.Equals {
+					return fmt.Sprintf("(%s IS NULL)", other), nil
+				}
+				return fmt.Sprintf("(%s IS NOT NULL)", other), nil
+			}
+		}
+		return t.equality(fn, args)
+	case operators.Less, operators.LessEquals, operators.Greater, operators.GreaterEquals:
+		return t.comparison(args, sqlOperators[fn])
+	case operators.Add, operators.Subtract, operators.Multiply, operators.Divide:
+		switch t.kind(node) {
+		case types.DoubleKind:
+		case types.IntKind:
+			if fn == operators.Divide {
+				return "", noSQL("integer division")
+			}
+		default:
+			return "", noSQL("%s on %s values", fn, t.ast.GetType(node.ID()))
+		}
+		return t.binary(args[0], sqlOperators[fn], args[1])
+	case operators.Negate:
+		operand, err := t.translate(args[0])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(-%s)", operand), nil
+	case operators.Conditional:
+		if t.kind(node) == types.BoolKind && !t.booleans() {
+			return "", noSQL("boolean conditional in %s", t.dialect.Type)
+		}
+		parts, err := t.translateAll(args)
+		if err != nil {
+			return "", err
+		}
+		// An unknown condition yields NULL, as Eval fails on it
+		return fmt.Sprintf("(CASE WHEN %s THEN %s WHEN NOT %s THEN %s END)", parts[0], parts[1], parts[0], parts[2]), nil
+	case operators.In:
+		return t.in(args[0], args[1])
+	case overloads.Size:
+		if t.kind(args[0]) != types.StringKind {
+			return "", noSQL("size of %s values", t.ast.GetType(args[0].ID()))
+		}
+		operand, err := t.translate(args[0])
+		if err != nil {
+			return "", err
+		}
+		return t.dialect.Length(operand), nil
+	case overloads.StartsWith, overloads.EndsWith, overloads.Contains:
+		return t.like(fn, args[0], args[1])
+	case overloads.Matches:
+		pattern, ok := stringLiteral(args[1])
+		if !ok {
+			return "", noSQL("matches with a pattern that is not a literal")
+		}
+		operand, err := t.translate(args[0])
+		if err != nil {
+			return "", err
+		}
+		match, err := t.dialect.RegexMatch(operand, pattern)
+		if err != nil {
+			return "", noSQL("%v", err)
+		}
+		return "(" + match + ")", nil
+	case overloads.TypeConvertTimestamp:
+		text, ok := stringLiteral(args[0])
+		if !ok {
+			return "", noSQL("timestamp of a value that is not a string literal")
+		}
+		ts, err := time.Parse(time.RFC3339Nano, text)
+		if err != nil {
+			return "", noSQL("invalid timestamp %q", text)
+		}
+		return t.dialect.TimestampLiteral(ts), nil
+	default:
+		return "", noSQL("function %s", fn)
+	}
+}
+
+func (t *translator) translateAll(nodes []celast.Expr) ([]string, error) {
+	parts := make([]string, len(nodes))
+	for i, node := range nodes {
+		part, err := t.translate(node)
+		if err != nil {
+			return nil, err
+		}
+		parts[i] = part
+	}
+	return parts, nil
+}
+
+func (t *translator) binary(left celast.Expr, sqlOp string, right celast.Expr) (string, error) {
+	parts, err := t.translateAll([]celast.Expr{left, right})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("(%s %s %s)", parts[0], sqlOp, parts[1]), nil
+}
+
+// comparison renders a comparison of values of a comparable kind
+func (t *translator) comparison(args []celast.Expr, sqlOp string) (string, error) {
+	for _, operand := range args {
+		switch t.kind(operand) {
+		case types.IntKind, types.UintKind, types.DoubleKind, types.StringKind, types.TimestampKind:
+		case types.BoolKind:
+			if !t.booleans() {
+				return "", noSQL("boolean comparison in %s", t.dialect.Type)
+			}
+		default:
+			return "", noSQL("comparison of %s values", t.ast.GetType(operand.ID()))
+		}
+	}
+	return t.binary(args[0], sqlOp, args[1])
+}
+
+// equality renders == and != between values of which none is the null
+// literal. As in Go, a NULL column equals NULL and differs from any value,
+// where SQL's comparison is unknown. Other operands are NULL where Eval
+// fails, so comparisons with them stay unknown.
+func (t *translator) equality(fn string, args []celast.Expr) (string, error) {
+	cmp, err := t.comparison(args, sqlOperators[fn])
+	if err != nil {
+		return "", err
+	}
+	var column, other string
+	for i, operand := range args {
+		if isConditional(operand) {
+			return "", noSQL("equality on the result of a conditional")
+		}
+		if operand.Kind() == celast.IdentKind && column == "" {
+			column = operand.AsIdent()
+			if other, err = t.translate(args[1-i]); err != nil {
+				return "", err
+			}
+		}
+	}
+	if column == "" {
+		return cmp, nil
+	}
+
+	equals := fn == operators.Equals
+	switch {
+	case args[0].Kind() == celast.LiteralKind || args[1].Kind() == celast.LiteralKind:
+		if equals {
+			return fmt.Sprintf("(%s AND %s IS NOT NULL)", cmp, column), nil
+		}
+		return fmt.Sprintf("(%s OR %s IS NULL)", cmp, column), nil
+	case other == column:
+		// A column always equals itself
+		if equals {
+			return "(1 = 1)", nil
+		}
+		return "(1 = 0)", nil
+	case args[0].Kind() == celast.IdentKind && args[1].Kind() == celast.IdentKind:
+		if equals {
+			return fmt.Sprintf("((%s AND %s IS NOT NULL AND %s IS NOT NULL) OR (%s IS NULL AND %s IS NULL))",
+				cmp, column, other, column, other), nil
+		}
+		return fmt.Sprintf("((%s AND %s IS NOT NULL AND %s IS NOT NULL) OR (%s IS NULL AND %s IS NOT NULL) OR (%s IS NOT NULL AND %s IS NULL))",
+			cmp, column, other, column, other, column, other), nil
+	default:
+		// other = other is unknown where the other operand is NULL
+		if equals {
+			return fmt.Sprintf("((%s AND %s IS NOT NULL) OR (%s IS NULL AND %s <> %s))", cmp, column, column, other, other), nil
+		}
+		return fmt.Sprintf("((%s AND %s IS NOT NULL) OR (%s IS NULL AND %s = %s))", cmp, column, column, other, other), nil
+	}
+}
+
+// isConditional reports whether a node is a ?: conditional, whose result is
+// NULL in SQL both for a NULL branch and for an unknown condition
+func isConditional(node celast.Expr) bool {
+	return node.Kind() == celast.CallKind && node.AsCall().FunctionName() == operators.Conditional
+}
+
+// in renders membership in a list literal of non-null literals. As in Go, a
+// NULL column is in no such list.
+func (t *translator) in(elem, list celast.Expr) (string, error) {
+	if list.Kind() != celast.ListKind || len(list.AsList().Elements()) == 0 {
+		return "", noSQL("in with a value that is not a list literal")
+	}
+	for _, value := range list.AsList().Elements() {
+		if value.Kind() != celast.LiteralKind || value.AsLiteral() == types.NullValue {
+			return "", noSQL("in with a list of values that are not non-null literals")
+		}
+	}
+	if isConditional(elem) {
+		return "", noSQL("membership of the result of a conditional")
+	}
+	operand, err := t.translate(elem)
+	if err != nil {
+		return "", err
+	}
+	values, err := t.translateAll(list.AsList().Elements())
+	if err != nil {
+		return "", err
+	}
+	if elem.Kind() == celast.IdentKind {
+		return fmt.Sprintf("(%s IN (%s) AND %s IS NOT NULL)", operand, strings.Join(values, ", "), operand), nil
+	}
+	return fmt.Sprintf("(%s IN (%s))", operand, strings.Join(values, ", ")), nil
+}
+
+// like renders startsWith, endsWith and contains with a literal argument free
+// of LIKE wildcards and escapes
+func (t *translator) like(fn string, target, arg celast.Expr) (string, error) {
+	text, ok := stringLiteral(arg)
+	if !ok || strings.ContainsAny(text, `%_\`) {
+		return "", noSQL("%s with an argument that is not a plain string literal", fn)
+	}
+	operand, err := t.translate(target)
+	if err != nil {
+		return "", err
+	}
+	switch fn {
+	case overloads.StartsWith:
+		text += "%"
+	case overloads.EndsWith:
+		text = "%" + text
+	default:
+		text = "%" + text + "%"
+	}
+	return fmt.Sprintf("(%s LIKE %s)", operand, t.dialect.StringLiteral(text)), nil
+}
+
+// stringLiteral returns the value of a string literal node
+func stringLiteral(node celast.Expr) (string, bool) {
+	if node.Kind() != celast.LiteralKind {
+		return "", false
+	}
+	s, ok := node.AsLiteral().(types.String)
+	return string(s), ok
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/vinod901/opendq-go/internal/auth"
 	"github.com/vinod901/opendq-go/internal/authorization"
+	"github.com/vinod901/opendq-go/internal/datasource"
 	"github.com/vinod901/opendq-go/internal/tenant"
 )
 
@@ -48,6 +49,7 @@ func (m *AuthMiddleware) Handle(next http.Handler) http.Handler {
 		// Add claims to context
 		ctx := context.WithValue(r.Context(), contextKeyClaims, claims)
 		ctx = context.WithValue(ctx, contextKeyUserID, claims.Subject)
+		ctx = datasource.WithQueryOrigin(ctx, datasource.QueryOrigin{Type: datasource.OriginUser, UserID: claims.Subject})
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -74,8 +76,11 @@ func (m *TenantMiddleware) Handle(next http.Handler) http.Handler {
 			return
 		}
 
-		// Add tenant to context
+		// Add tenant to context, with its ID once the slug resolves
 		ctx := tenant.WithTenantSlug(r.Context(), tenantSlug)
+		if t, err := m.tenantManager.GetTenantBySlug(ctx, tenantSlug); err == nil {
+			ctx = tenant.WithTenantID(ctx, t.ID)
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
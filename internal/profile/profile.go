@@ -0,0 +1,484 @@
+// Package profile computes per-column statistics for datasource tables and
+// derives data quality check recommendations from them.
+package profile
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+const (
+	defaultTopK             = 10
+	defaultHistogramBuckets = 10
+)
+
+// Kind is the broad category of a column's data type
+type Kind = datasource.TypeKind
+
+const (
+	KindNumeric  = datasource.KindNumeric
+	KindString   = datasource.KindString
+	KindTemporal = datasource.KindTemporal
+	KindBoolean  = datasource.KindBoolean
+	KindOther    = datasource.KindOther
+)
+
+// ClassifyType returns the kind of an engine data type name
+func ClassifyType(dataType string) Kind {
+	return datasource.ClassifyType(dataType)
+}
+
+// Options configures a profiling run
+type Options struct {
+	Columns             []string           `json:"columns,omitempty"`              // Defaults to all columns
+	ApproximateDistinct bool               `json:"approximate_distinct,omitempty"` // Use HyperLogLog where supported
+	TopK                int                `json:"top_k,omitempty"`                // Defaults to 10
+	HistogramBuckets    int                `json:"histogram_buckets,omitempty"`    // Defaults to 10
+	Sample              *datasource.Sample `json:"sample,omitempty"`
+}
+
+// ValueFrequency is a value and the number of rows holding it
+type ValueFrequency struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// HistogramBucket counts the values in [Lower, Upper); the last bucket includes Upper
+type HistogramBucket struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+	Count int64   `json:"count"`
+}
+
+// ColumnProfile holds the statistics of a single column
+type ColumnProfile struct {
+	Name                string            `json:"name"`
+	DataType            string            `json:"data_type"`
+	Kind                Kind              `json:"kind"`
+	Nullable            bool              `json:"nullable"`
+	NullCount           int64             `json:"null_count"`
+	NullPercentage      float64           `json:"null_percentage"`
+	DistinctCount       int64             `json:"distinct_count"`
+	DistinctApproximate bool              `json:"distinct_approximate,omitempty"`
+	Min                 interface{}       `json:"min,omitempty"`
+	Max                 interface{}       `json:"max,omitempty"`
+	Mean                *float64          `json:"mean,omitempty"`
+	StdDev              *float64          `json:"std_dev,omitempty"`
+	MinLength           *int64            `json:"min_length,omitempty"`
+	MaxLength           *int64            `json:"max_length,omitempty"`
+	AvgLength           *float64          `json:"avg_length,omitempty"`
+	TopValues           []ValueFrequency  `json:"top_values,omitempty"`
+	Histogram           []HistogramBucket `json:"histogram,omitempty"`
+}
+
+// TableProfile is a timestamped profile of a table
+type TableProfile struct {
+	ID           string             `json:"id"`
+	TenantID     string             `json:"tenant_id"`
+	DatasourceID string             `json:"datasource_id"`
+	Table        string             `json:"table"`
+	RowCount     int64              `json:"row_count"`
+	Columns      []ColumnProfile    `json:"columns"`
+	Sample       *datasource.Sample `json:"sample,omitempty"`
+	ProfiledAt   time.Time          `json:"profiled_at"`
+	Duration     time.Duration      `json:"duration"`
+}
+
+// Column returns the profile of a named column
+func (p *TableProfile) Column(name string) (*ColumnProfile, bool) {
+	for i := range p.Columns {
+		if p.Columns[i].Name == name {
+			return &p.Columns[i], true
+		}
+	}
+	return nil, false
+}
+
+// Manager profiles tables and stores profile history
+type Manager struct {
+	datasourceManager *datasource.Manager
+	profiles          map[string][]*TableProfile // Keyed by datasource ID and table, oldest first
+	mu                sync.RWMutex
+}
+
+// NewManager creates a new profile manager
+func NewManager(dsManager *datasource.Manager) *Manager {
+	return &Manager{
+		datasourceManager: dsManager,
+		profiles:          make(map[string][]*TableProfile),
+	}
+}
+
+// ProfileTable profiles a table and stores the result
+func (m *Manager) ProfileTable(ctx context.Context, datasourceID, table string, opts Options) (*TableProfile, error) {
+	ds, err := m.datasourceManager.GetDatasource(ctx, datasourceID)
+	if err != nil {
+		return nil, err
+	}
+	connector, err := m.datasourceManager.GetConnector(ctx, datasourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get datasource connector: %w", err)
+	}
+
+	ctx = datasource.WithQueryOrigin(ctx, datasource.QueryOrigin{Type: datasource.OriginProfile, ID: table})
+	profile, err := Profile(ctx, connector, table, opts)
+	if err != nil {
+		return nil, err
+	}
+	profile.TenantID = ds.TenantID
+	profile.DatasourceID = datasourceID
+
+	key := profileKey(datasourceID, table)
+	m.mu.Lock()
+	m.profiles[key] = append(m.profiles[key], profile)
+	m.mu.Unlock()
+
+	return profile, nil
+}
+
+// ListProfiles returns the stored profiles of a table, oldest first
+func (m *Manager) ListProfiles(ctx context.Context, datasourceID, table string) ([]*TableProfile, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]*TableProfile(nil), m.profiles[profileKey(datasourceID, table)]...), nil
+}
+
+// GetLatestProfile returns the most recent profile of a table
+func (m *Manager) GetLatestProfile(ctx context.Context, datasourceID, table string) (*TableProfile, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	profiles := m.profiles[profileKey(datasourceID, table)]
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("no profile found for table: %s", table)
+	}
+	return profiles[len(profiles)-1], nil
+}
+
+func profileKey(datasourceID, table string) string {
+	return datasourceID + "/" + table
+}
+
+// Profile computes a table profile in two queries. Row, null and distinct
+// counts, extremes, moments and string lengths for every column come from a
+// single aggregate scan; top-K values and histograms of every column from one
+// grouped query. A sampled profile is only as consistent as its sample is
+// repeatable: random samples are seeded where the engine allows, and the
+// grouped query is skipped for samples no seed can repeat.
+func Profile(ctx context.Context, connector datasource.Connector, table string, opts Options) (*TableProfile, error) {
+	startTime := time.Now()
+	if opts.TopK <= 0 {
+		opts.TopK = defaultTopK
+	}
+	if opts.HistogramBuckets <= 0 {
+		opts.HistogramBuckets = defaultHistogramBuckets
+	}
+
+	columns, err := selectColumns(ctx, connector, table, opts.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	dialect := datasource.DialectFor(connector.Type())
+	from := table
+	repeatable := true
+	var sample *datasource.Sample
+	if opts.Sample != nil {
+		s := *opts.Sample
+		if s.Seed == 0 && dialect.Seedable(s) {
+			// Every query of the profile must read the same rows
+			s.Seed = rand.Int63n(math.MaxInt32) + 1
+		}
+		sampled, err := dialect.SampledTable(table, s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build table sample: %w", err)
+		}
+		from = sampled + " _profile"
+		repeatable = dialect.Repeatable(s)
+		sample = &s
+	}
+
+	profile := &TableProfile{
+		ID:         uuid.New().String(),
+		Table:      table,
+		Sample:     sample,
+		ProfiledAt: startTime,
+	}
+
+	result, err := connector.Query(ctx, aggregateSQL(dialect, from, columns, opts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to profile table: %w", err)
+	}
+	if len(result.Rows) == 0 {
+		return nil, fmt.Errorf("profile query returned no rows")
+	}
+	row := result.Rows[0]
+	profile.RowCount = toInt64(row["row_count"])
+
+	var series []string
+	for i, col := range columns {
+		cp := columnStats(row, i, col, profile.RowCount, dialect, opts)
+		nonNull := profile.RowCount - cp.NullCount
+
+		if repeatable && cp.DistinctCount > 0 && cp.DistinctCount < nonNull {
+			// Top values are only informative when some values repeat
+			series = append(series, topValuesSQL(dialect, from, i, col.Name, opts.TopK))
+		}
+		if cp.Kind == KindNumeric && cp.Min != nil && cp.Max != nil {
+			min, max := toFloat64(cp.Min), toFloat64(cp.Max)
+			switch {
+			case max <= min:
+				// A column of a single value has one bucket holding every value
+				cp.Histogram = []HistogramBucket{{Lower: min, Upper: max, Count: nonNull}}
+			case repeatable:
+				cp.Histogram = histogramBuckets(min, max, opts.HistogramBuckets)
+				series = append(series, histogramSQL(dialect, from, i, col.Name, min, max, opts.HistogramBuckets))
+			}
+		}
+		profile.Columns = append(profile.Columns, cp)
+	}
+
+	if len(series) > 0 {
+		if err := distributions(ctx, connector, strings.Join(series, " UNION ALL "), profile.Columns); err != nil {
+			return nil, err
+		}
+	}
+
+	profile.Duration = time.Since(startTime)
+	return profile, nil
+}
+
+// selectColumns returns the table's columns, restricted to the requested names
+func selectColumns(ctx context.Context, connector datasource.Connector, table string, names []string) ([]datasource.ColumnInfo, error) {
+	columns, err := connector.GetColumns(ctx, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns: %w", err)
+	}
+	if len(names) == 0 {
+		return columns, nil
+	}
+
+	byName := make(map[string]datasource.ColumnInfo, len(columns))
+	for _, col := range columns {
+		byName[col.Name] = col
+	}
+	selected := make([]datasource.ColumnInfo, 0, len(names))
+	for _, name := range names {
+		col, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("column not found: %s", name)
+		}
+		selected = append(selected, col)
+	}
+	return selected, nil
+}
+
+// aggregateSQL builds the single-scan aggregate query. Column statistics are
+// aliased c<index>_<stat> so arbitrary column names cannot collide.
+func aggregateSQL(dialect datasource.Dialect, from string, columns []datasource.ColumnInfo, opts Options) string {
+	selects := []string{"COUNT(*) AS row_count"}
+	for i, col := range columns {
+		p := fmt.Sprintf("c%d_", i)
+		distinct, _ := dialect.CountDistinct(col.Name, opts.ApproximateDistinct)
+		selects = append(selects,
+			fmt.Sprintf("COUNT(%s) AS %snon_null", col.Name, p),
+			fmt.Sprintf("%s AS %sdistinct", distinct, p),
+		)
+
+		switch ClassifyType(col.DataType) {
+		case KindNumeric:
+			selects = append(selects,
+				fmt.Sprintf("MIN(%s) AS %smin", col.Name, p),
+				fmt.Sprintf("MAX(%s) AS %smax", col.Name, p),
+				fmt.Sprintf("AVG(%s) AS %smean", col.Name, p),
+				fmt.Sprintf("%s AS %sstddev", dialect.StdDev(col.Name), p),
+			)
+		case KindTemporal:
+			selects = append(selects,
+				fmt.Sprintf("MIN(%s) AS %smin", col.Name, p),
+				fmt.Sprintf("MAX(%s) AS %smax", col.Name, p),
+			)
+		case KindString:
+			length := dialect.Length(col.Name)
+			selects = append(selects,
+				fmt.Sprintf("MIN(%s) AS %smin_length", length, p),
+				fmt.Sprintf("MAX(%s) AS %smax_length", length, p),
+				fmt.Sprintf("AVG(%s) AS %savg_length", length, p),
+			)
+		}
+	}
+	return fmt.Sprintf("SELECT %s FROM %s", strings.Join(selects, ", "), from)
+}
+
+// columnStats reads a column's statistics from the aggregate query row
+func columnStats(row map[string]interface{}, i int, col datasource.ColumnInfo, rowCount int64, dialect datasource.Dialect, opts Options) ColumnProfile {
+	p := fmt.Sprintf("c%d_", i)
+	_, approximate := dialect.CountDistinct(col.Name, opts.ApproximateDistinct)
+	cp := ColumnProfile{
+		Name:                col.Name,
+		DataType:            col.DataType,
+		Kind:                ClassifyType(col.DataType),
+		Nullable:            col.Nullable,
+		NullCount:           rowCount - toInt64(row[p+"non_null"]),
+		DistinctCount:       toInt64(row[p+"distinct"]),
+		DistinctApproximate: approximate,
+	}
+	if rowCount > 0 {
+		cp.NullPercentage = float64(cp.NullCount) / float64(rowCount) * 100
+	}
+
+	switch cp.Kind {
+	case KindNumeric:
+		cp.Min, cp.Max = row[p+"min"], row[p+"max"]
+		cp.Mean = optionalFloat(row[p+"mean"])
+		cp.StdDev = optionalFloat(row[p+"stddev"])
+	case KindTemporal:
+		cp.Min, cp.Max = row[p+"min"], row[p+"max"]
+	case KindString:
+		if v := row[p+"min_length"]; v != nil {
+			minLength, maxLength := toInt64(v), toInt64(row[p+"max_length"])
+			cp.MinLength, cp.MaxLength = &minLength, &maxLength
+		}
+		cp.AvgLength = optionalFloat(row[p+"avg_length"])
+	}
+	return cp
+}
+
+// topValuesSQL selects the k most frequent non-null values of a column as the
+// series c<index>_top
+func topValuesSQL(dialect datasource.Dialect, from string, i int, column string, k int) string {
+	return fmt.Sprintf(
+		"SELECT 'c%d_top' AS series, value, frequency FROM (SELECT %s AS value, COUNT(*) AS frequency FROM %s WHERE %s IS NOT NULL GROUP BY %s ORDER BY frequency DESC %s) _top%d",
+		i, dialect.CastText(column), from, column, column, dialect.LimitClause(k), i,
+	)
+}
+
+// histogramSQL counts the non-null values of a numeric column per equal-width
+// bucket over [min, max] as the series c<index>_histogram. Values outside the
+// range count in the nearest bucket.
+func histogramSQL(dialect datasource.Dialect, from string, i int, column string, min, max float64, buckets int) string {
+	bucketExpr := fmt.Sprintf("CASE WHEN %s >= %g THEN %d WHEN %s <= %g THEN 0 ELSE FLOOR((%s - %g) * %d / %g) END",
+		column, max, buckets-1, column, min, column, min, buckets, max-min)
+	return fmt.Sprintf(
+		"SELECT 'c%d_histogram' AS series, %s AS value, COUNT(*) AS frequency FROM (SELECT %s AS bucket FROM %s WHERE %s IS NOT NULL) _histogram%d GROUP BY bucket",
+		i, dialect.CastText("bucket"), bucketExpr, from, column, i,
+	)
+}
+
+// histogramBuckets returns empty equal-width buckets over [min, max]
+func histogramBuckets(min, max float64, buckets int) []HistogramBucket {
+	width := (max - min) / float64(buckets)
+	histogram := make([]HistogramBucket, buckets)
+	for i := range histogram {
+		histogram[i] = HistogramBucket{Lower: min + float64(i)*width, Upper: min + float64(i+1)*width}
+	}
+	histogram[buckets-1].Upper = max
+	return histogram
+}
+
+// distributions runs the grouped query of top values and histogram series,
+// filling in the column profiles they belong to
+func distributions(ctx context.Context, connector datasource.Connector, query string, columns []ColumnProfile) error {
+	result, err := connector.Query(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to compute value distributions: %w", err)
+	}
+
+	for _, row := range result.Rows {
+		prefix, kind, _ := strings.Cut(toString(row["series"]), "_")
+		i, err := strconv.Atoi(strings.TrimPrefix(prefix, "c"))
+		if err != nil || i < 0 || i >= len(columns) {
+			continue
+		}
+		cp := &columns[i]
+		frequency := toInt64(row["frequency"])
+		switch kind {
+		case "top":
+			cp.TopValues = append(cp.TopValues, ValueFrequency{Value: toString(row["value"]), Count: frequency})
+		case "histogram":
+			if bucket := toInt64(row["value"]); bucket >= 0 && int(bucket) < len(cp.Histogram) {
+				cp.Histogram[bucket].Count += frequency
+			}
+		}
+	}
+
+	// The union does not keep each series' order
+	for i := range columns {
+		values := columns[i].TopValues
+		sort.SliceStable(values, func(a, b int) bool {
+			if values[a].Count != values[b].Count {
+				return values[a].Count > values[b].Count
+			}
+			return values[a].Value < values[b].Value
+		})
+	}
+	return nil
+}
+
+func optionalFloat(v interface{}) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := toFloat64(v)
+	return &f
+}
+
+func toString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(val)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+func toInt64(v interface{}) int64 {
+	switch val := v.(type) {
+	case int64:
+		return val
+	case int:
+		return int64(val)
+	case int32:
+		return int64(val)
+	case float64:
+		return int64(val)
+	case float32:
+		return int64(val)
+	case []byte, string:
+		f, _ := strconv.ParseFloat(toString(val), 64)
+		return int64(f)
+	default:
+		return 0
+	}
+}
+
+func toFloat64(v interface{}) float64 {
+	switch val := v.(type) {
+	case float64:
+		return val
+	case float32:
+		return float64(val)
+	case int64:
+		return float64(val)
+	case int:
+		return float64(val)
+	case int32:
+		return float64(val)
+	case []byte, string:
+		f, _ := strconv.ParseFloat(toString(val), 64)
+		return f
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,219 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// fakeConnector answers profiling queries with canned results keyed by a
+// substring of the query
+type fakeConnector struct {
+	columns   []datasource.ColumnInfo
+	tables    map[string][]datasource.ColumnInfo // Per-table columns, overriding columns
+	infos     []datasource.TableInfo             // Tables listed by GetTables
+	responses map[string][]map[string]interface{}
+	queries   []string
+}
+
+func (c *fakeConnector) Connect(ctx context.Context) error { return nil }
+func (c *fakeConnector) Close() error                      { return nil }
+func (c *fakeConnector) Ping(ctx context.Context) error    { return nil }
+
+func (c *fakeConnector) Query(ctx context.Context, query string, args ...interface{}) (*datasource.QueryResult, error) {
+	c.queries = append(c.queries, query)
+	for match, rows := range c.responses {
+		if strings.Contains(query, match) {
+			return &datasource.QueryResult{Rows: rows, RowCount: int64(len(rows))}, nil
+		}
+	}
+	return &datasource.QueryResult{}, nil
+}
+
+func (c *fakeConnector) GetTables(ctx context.Context) ([]datasource.TableInfo, error) {
+	return c.infos, nil
+}
+
+func (c *fakeConnector) GetColumns(ctx context.Context, table string) ([]datasource.ColumnInfo, error) {
+	if columns, ok := c.tables[table]; ok {
+		return columns, nil
+	}
+	return c.columns, nil
+}
+
+func (c *fakeConnector) GetRowCount(ctx context.Context, table string) (int64, error) {
+	return 0, nil
+}
+
+func (c *fakeConnector) GetPartitions(ctx context.Context, table string) ([]datasource.PartitionInfo, error) {
+	return []datasource.PartitionInfo{}, nil
+}
+
+func (c *fakeConnector) Type() datasource.Type {
+	return datasource.TypePostgres
+}
+
+func TestClassifyType(t *testing.T) {
+	testCases := []struct {
+		dataType string
+		expected Kind
+	}{
+		{"integer", KindNumeric},
+		{"NUMERIC(10,2)", KindNumeric},
+		{"double precision", KindNumeric},
+		{"character varying", KindString},
+		{"text", KindString},
+		{"timestamp with time zone", KindTemporal},
+		{"DATE", KindTemporal},
+		{"boolean", KindBoolean},
+		{"interval", KindOther},
+		{"jsonb", KindOther},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.dataType, func(t *testing.T) {
+			if got := ClassifyType(tc.dataType); got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestProfile(t *testing.T) {
+	connector := &fakeConnector{
+		columns: []datasource.ColumnInfo{
+			{Name: "amount", DataType: "numeric"},
+			{Name: "status", DataType: "text", Nullable: true},
+		},
+		responses: map[string][]map[string]interface{}{
+			"AS row_count": {{
+				"row_count":     int64(100),
+				"c0_non_null":   int64(100),
+				"c0_distinct":   int64(100),
+				"c0_min":        []byte("0"),
+				"c0_max":        []byte("100"),
+				"c0_mean":       []byte("50.5"),
+				"c0_stddev":     []byte("29.0"),
+				"c1_non_null":   int64(90),
+				"c1_distinct":   int64(3),
+				"c1_min_length": int64(4),
+				"c1_max_length": int64(9),
+				"c1_avg_length": 6.5,
+			}},
+			"AS series": {
+				{"series": "c1_top", "value": "pending", "frequency": int64(25)},
+				{"series": "c0_histogram", "value": "0", "frequency": int64(50)},
+				{"series": "c1_top", "value": "paid", "frequency": int64(60)},
+				{"series": "c0_histogram", "value": []byte("1"), "frequency": int64(50)},
+			},
+		},
+	}
+
+	profile, err := Profile(context.Background(), connector, "orders", Options{HistogramBuckets: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.RowCount != 100 {
+		t.Errorf("expected 100 rows, got %d", profile.RowCount)
+	}
+
+	amount, _ := profile.Column("amount")
+	if amount.NullCount != 0 || amount.DistinctCount != 100 {
+		t.Errorf("unexpected amount counts: %+v", amount)
+	}
+	if amount.Mean == nil || *amount.Mean != 50.5 {
+		t.Errorf("expected mean 50.5, got %v", amount.Mean)
+	}
+	if len(amount.TopValues) != 0 {
+		t.Error("expected no top values for an all-distinct column")
+	}
+	if len(amount.Histogram) != 2 || amount.Histogram[1].Upper != 100 || amount.Histogram[0].Count != 50 {
+		t.Errorf("unexpected histogram: %+v", amount.Histogram)
+	}
+
+	status, _ := profile.Column("status")
+	if status.NullCount != 10 || status.NullPercentage != 10 {
+		t.Errorf("expected 10 nulls, got %d (%f%%)", status.NullCount, status.NullPercentage)
+	}
+	if *status.MinLength != 4 || *status.MaxLength != 9 {
+		t.Errorf("unexpected length stats: %d..%d", *status.MinLength, *status.MaxLength)
+	}
+	if len(status.TopValues) != 2 || status.TopValues[0].Value != "paid" {
+		t.Errorf("unexpected top values: %+v", status.TopValues)
+	}
+
+	if !strings.Contains(connector.queries[0], "STDDEV_SAMP(amount)") {
+		t.Errorf("expected a single aggregate scan, got %s", connector.queries[0])
+	}
+	if len(connector.queries) != 2 || !strings.Contains(connector.queries[1], "UNION ALL") {
+		t.Errorf("expected one grouped query for every column, got %v", connector.queries)
+	}
+
+	// A column of a single value has one bucket holding every non-null row
+	connector.responses["AS row_count"][0]["c0_min"] = []byte("7")
+	connector.responses["AS row_count"][0]["c0_max"] = []byte("7")
+	connector.responses["AS series"] = connector.responses["AS series"][:1]
+	constant, err := Profile(context.Background(), connector, "orders", Options{HistogramBuckets: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if amount, _ := constant.Column("amount"); len(amount.Histogram) != 1 || amount.Histogram[0].Count != 100 {
+		t.Errorf("expected one bucket of 100 rows, got %+v", amount.Histogram)
+	}
+}
+
+func TestProfile_Sample(t *testing.T) {
+	newConnector := func() *fakeConnector {
+		return &fakeConnector{
+			columns: []datasource.ColumnInfo{{Name: "status", DataType: "text"}},
+			responses: map[string][]map[string]interface{}{
+				"AS row_count": {{"row_count": int64(10), "c0_non_null": int64(10), "c0_distinct": int64(2)}},
+				"AS series":    {{"series": "c0_top", "value": "paid", "frequency": int64(10)}},
+			},
+		}
+	}
+
+	// A random sample is seeded so that both queries read the same rows
+	connector := newConnector()
+	requested := &datasource.Sample{Percentage: 10}
+	profile, err := Profile(context.Background(), connector, "orders", Options{Sample: requested})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requested.Seed != 0 || profile.Sample == nil || profile.Sample.Seed == 0 {
+		t.Fatalf("expected the profile to record a seed without changing the options, got %+v", profile.Sample)
+	}
+	clause := fmt.Sprintf("REPEATABLE (%d)", profile.Sample.Seed)
+	if len(connector.queries) != 2 || !strings.Contains(connector.queries[0], clause) || !strings.Contains(connector.queries[1], clause) {
+		t.Errorf("expected both queries to read the seeded sample, got %v", connector.queries)
+	}
+
+	// A sample no seed can repeat has no top values or histograms
+	connector = newConnector()
+	profile, err = Profile(context.Background(), connector, "orders", Options{Sample: &datasource.Sample{RowCount: 100}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status, _ := profile.Column("status"); len(connector.queries) != 1 || len(status.TopValues) != 0 {
+		t.Errorf("expected only the aggregate query, got %v", connector.queries)
+	}
+}
+
+func TestProfile_UnknownColumn(t *testing.T) {
+	connector := &fakeConnector{columns: []datasource.ColumnInfo{{Name: "id", DataType: "integer"}}}
+
+	_, err := Profile(context.Background(), connector, "orders", Options{Columns: []string{"missing"}})
+	if err == nil {
+		t.Fatal("expected error for unknown column")
+	}
+}
+
+func TestManager_ProfileTable_NotFound(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	if _, err := m.ProfileTable(context.Background(), "nonexistent", "orders", Options{}); err == nil {
+		t.Fatal("expected error for nonexistent datasource")
+	}
+}
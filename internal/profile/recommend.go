@@ -0,0 +1,231 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/check"
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+const (
+	// maxSetSize is the largest number of distinct values suggested as an allowed set
+	maxSetSize = 10
+	// minRowsPerSetValue avoids suggesting sets from tables too small to show repetition
+	minRowsPerSetValue = 5
+	// rangeMargin widens observed numeric bounds by this fraction of their span
+	rangeMargin = 0.1
+	// freshnessFactor multiplies the observed data lag to get a freshness limit
+	freshnessFactor = 2.0
+	// maxFreshnessLag skips freshness suggestions for tables that look historical
+	maxFreshnessLag = 30 * 24 * time.Hour
+)
+
+// Recommendation is a suggested check with the observation that motivated it
+type Recommendation struct {
+	Check     *check.Check `json:"check"`
+	Rationale string       `json:"rationale"`
+}
+
+// RecommendOptions configures check recommendation for a table
+type RecommendOptions struct {
+	Refresh bool               `json:"refresh,omitempty"` // Profile the table even if a stored profile exists
+	Sample  *datasource.Sample `json:"sample,omitempty"`  // Sample used when profiling
+}
+
+// RecommendChecks suggests checks for a table from its latest stored profile,
+// profiling the table first when no profile exists or a refresh is requested
+func (m *Manager) RecommendChecks(ctx context.Context, datasourceID, table string, opts RecommendOptions) ([]Recommendation, error) {
+	var profile *TableProfile
+	if !opts.Refresh {
+		profile, _ = m.GetLatestProfile(ctx, datasourceID, table)
+	}
+	if profile == nil {
+		var err error
+		profile, err = m.ProfileTable(ctx, datasourceID, table, Options{Sample: opts.Sample})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return Recommend(profile), nil
+}
+
+// Recommend derives check suggestions from a table profile. Thresholds are
+// taken from the observed data, so the suggested checks pass on the data as
+// profiled and fail when it changes shape.
+func Recommend(profile *TableProfile) []Recommendation {
+	recommendations := []Recommendation{}
+	if profile.RowCount == 0 {
+		return recommendations
+	}
+
+	observed := "observed"
+	if profile.Sample != nil {
+		observed = "observed in a sample"
+	}
+
+	for i := range profile.Columns {
+		col := &profile.Columns[i]
+		nonNull := profile.RowCount - col.NullCount
+
+		switch {
+		case col.NullCount == 0:
+			notNull := newCheck(profile, col, "not null", check.TypeNullCheck, check.CheckParameters{})
+			notNull.Threshold = check.Threshold{Type: check.ThresholdAbsolute, Operator: check.OperatorEq, Value: 0}
+			recommendations = appendRecommendation(recommendations, Recommendation{
+				Check:     notNull,
+				Rationale: fmt.Sprintf("no nulls %s across %d rows", observed, profile.RowCount),
+			})
+		case col.NullPercentage < 100:
+			limit := math.Ceil(col.NullPercentage + math.Max(col.NullPercentage/2, 1))
+			recommendations = appendRecommendation(recommendations, Recommendation{
+				Check: newCheck(profile, col, "null percentage", check.TypeNullCheck, check.CheckParameters{
+					MaxNullPercentage: math.Min(limit, 100),
+				}),
+				Rationale: fmt.Sprintf("%.2f%% nulls %s; limit allows headroom above the observed rate", col.NullPercentage, observed),
+			})
+		}
+
+		if col.NullCount == 0 && !col.DistinctApproximate && profile.RowCount > 1 && col.DistinctCount == nonNull &&
+			(col.Kind == KindNumeric || col.Kind == KindString) {
+			recommendations = appendRecommendation(recommendations, Recommendation{
+				Check:     newCheck(profile, col, "uniqueness", check.TypeUniqueness, check.CheckParameters{}),
+				Rationale: fmt.Sprintf("all %d values %s are distinct, making the column a candidate key", nonNull, observed),
+			})
+		}
+
+		if r, ok := recommendSet(profile, col, nonNull, observed); ok {
+			recommendations = appendRecommendation(recommendations, r)
+		}
+		if r, ok := recommendRange(profile, col, observed); ok {
+			recommendations = appendRecommendation(recommendations, r)
+		}
+		if r, ok := recommendFreshness(profile, col); ok {
+			recommendations = appendRecommendation(recommendations, r)
+		}
+	}
+	return recommendations
+}
+
+// recommendSet suggests set membership for low-cardinality string columns
+// whose top values cover every non-null row
+func recommendSet(profile *TableProfile, col *ColumnProfile, nonNull int64, observed string) (Recommendation, bool) {
+	if col.Kind != KindString || col.DistinctCount == 0 || col.DistinctCount > maxSetSize ||
+		nonNull < col.DistinctCount*minRowsPerSetValue {
+		return Recommendation{}, false
+	}
+
+	var covered int64
+	values := make([]string, 0, len(col.TopValues))
+	for _, v := range col.TopValues {
+		covered += v.Count
+		values = append(values, v.Value)
+	}
+	if covered != nonNull {
+		return Recommendation{}, false
+	}
+
+	return Recommendation{
+		Check: newCheck(profile, col, "set membership", check.TypeSetMembership, check.CheckParameters{
+			AllowedValues: values,
+		}),
+		Rationale: fmt.Sprintf("only %d distinct values %s across %d rows: %s",
+			len(values), observed, nonNull, strings.Join(values, ", ")),
+	}, true
+}
+
+// recommendRange suggests a range check from observed numeric bounds, widened
+// by a margin and never crossing zero for non-negative columns
+func recommendRange(profile *TableProfile, col *ColumnProfile, observed string) (Recommendation, bool) {
+	if col.Kind != KindNumeric || col.Min == nil || col.Max == nil {
+		return Recommendation{}, false
+	}
+
+	min, max := toFloat64(col.Min), toFloat64(col.Max)
+	margin := (max - min) * rangeMargin
+	expectedMin, expectedMax := min-margin, max+margin
+	if min >= 0 && expectedMin < 0 {
+		expectedMin = 0
+	}
+
+	return Recommendation{
+		Check: newCheck(profile, col, "range", check.TypeRange, check.CheckParameters{
+			ExpectedMin: expectedMin,
+			ExpectedMax: expectedMax,
+		}),
+		Rationale: fmt.Sprintf("values %s between %g and %g; bounds widened by %.0f%% of the span",
+			observed, min, max, rangeMargin*100),
+	}, true
+}
+
+// recommendFreshness suggests a freshness check for timestamp columns whose
+// latest value is recent, allowing a multiple of the observed lag
+func recommendFreshness(profile *TableProfile, col *ColumnProfile) (Recommendation, bool) {
+	if col.Kind != KindTemporal {
+		return Recommendation{}, false
+	}
+	latest, ok := toTime(col.Max)
+	if !ok {
+		return Recommendation{}, false
+	}
+	lag := profile.ProfiledAt.Sub(latest)
+	if lag < 0 || lag > maxFreshnessLag {
+		return Recommendation{}, false
+	}
+
+	maxAgeHours := math.Max(math.Ceil(lag.Hours()*freshnessFactor), 1)
+	return Recommendation{
+		Check: newCheck(profile, col, "freshness", check.TypeFreshness, check.CheckParameters{
+			TimestampColumn: col.Name,
+			MaxAgeHours:     maxAgeHours,
+		}),
+		Rationale: fmt.Sprintf("latest value was %.1f hours old when profiled; limit allows %.0fx that lag",
+			lag.Hours(), freshnessFactor),
+	}, true
+}
+
+// newCheck builds a suggested check for a profiled column
+func newCheck(profile *TableProfile, col *ColumnProfile, kind string, checkType check.Type, params check.CheckParameters) *check.Check {
+	return &check.Check{
+		TenantID:     profile.TenantID,
+		DatasourceID: profile.DatasourceID,
+		Name:         fmt.Sprintf("%s.%s %s", profile.Table, col.Name, kind),
+		Type:         checkType,
+		Table:        profile.Table,
+		Column:       col.Name,
+		Parameters:   params,
+		Severity:     check.SeverityMedium,
+		Tags:         []string{"recommended"},
+		Metadata:     map[string]interface{}{"profile_id": profile.ID},
+	}
+}
+
+// timeLayouts are the text forms in which drivers return timestamps
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+func toTime(v interface{}) (time.Time, bool) {
+	if t, ok := v.(time.Time); ok {
+		return t, true
+	}
+	s := toString(v)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// appendRecommendation appends a recommendation, describing its check with the rationale
+func appendRecommendation(recommendations []Recommendation, r Recommendation) []Recommendation {
+	r.Check.Description = r.Rationale
+	return append(recommendations, r)
+}
@@ -0,0 +1,108 @@
+package profile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/check"
+)
+
+func TestRecommend(t *testing.T) {
+	profiledAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	profile := &TableProfile{
+		ID:           "p1",
+		TenantID:     "t1",
+		DatasourceID: "ds1",
+		Table:        "orders",
+		RowCount:     100,
+		ProfiledAt:   profiledAt,
+		Columns: []ColumnProfile{
+			{Name: "id", Kind: KindNumeric, DistinctCount: 100, Min: int64(1), Max: int64(100)},
+			{Name: "status", Kind: KindString, DistinctCount: 2, TopValues: []ValueFrequency{
+				{Value: "paid", Count: 70}, {Value: "pending", Count: 30},
+			}},
+			{Name: "note", Kind: KindString, NullCount: 10, NullPercentage: 10, DistinctCount: 90},
+			{Name: "updated_at", Kind: KindTemporal, DistinctCount: 100, Max: "2024-06-01 09:00:00"},
+		},
+	}
+
+	recommendations := Recommend(profile)
+
+	byName := make(map[string]*check.Check)
+	for _, r := range recommendations {
+		if r.Rationale == "" || r.Check.Description != r.Rationale {
+			t.Errorf("expected rationale on %s", r.Check.Name)
+		}
+		if r.Check.DatasourceID != "ds1" || r.Check.TenantID != "t1" {
+			t.Errorf("expected datasource and tenant on %s", r.Check.Name)
+		}
+		byName[r.Check.Name] = r.Check
+	}
+
+	expected := []struct {
+		name      string
+		checkType check.Type
+	}{
+		{"orders.id not null", check.TypeNullCheck},
+		{"orders.id uniqueness", check.TypeUniqueness},
+		{"orders.id range", check.TypeRange},
+		{"orders.status not null", check.TypeNullCheck},
+		{"orders.status set membership", check.TypeSetMembership},
+		{"orders.note null percentage", check.TypeNullCheck},
+		{"orders.updated_at not null", check.TypeNullCheck},
+		{"orders.updated_at freshness", check.TypeFreshness},
+	}
+	if len(recommendations) != len(expected) {
+		t.Errorf("expected %d recommendations, got %d: %v", len(expected), len(recommendations), byName)
+	}
+	for _, e := range expected {
+		c, ok := byName[e.name]
+		if !ok {
+			t.Errorf("missing recommendation %s", e.name)
+			continue
+		}
+		if c.Type != e.checkType {
+			t.Errorf("%s: expected type %s, got %s", e.name, e.checkType, c.Type)
+		}
+	}
+
+	if r := byName["orders.id range"]; r.Parameters.ExpectedMin != 0 || r.Parameters.ExpectedMax != 109.9 {
+		t.Errorf("expected range [0, 109.9], got [%g, %g]", r.Parameters.ExpectedMin, r.Parameters.ExpectedMax)
+	}
+	if r := byName["orders.status set membership"]; len(r.Parameters.AllowedValues) != 2 {
+		t.Errorf("expected 2 allowed values, got %v", r.Parameters.AllowedValues)
+	}
+	if r := byName["orders.id not null"]; r.Threshold.Type != check.ThresholdAbsolute || r.Threshold.Operator != check.OperatorEq || r.Threshold.Value != 0 {
+		t.Errorf("expected an absolute eq 0 threshold on not null, got %+v", r.Threshold)
+	}
+	if r := byName["orders.note null percentage"]; r.Parameters.MaxNullPercentage != 15 {
+		t.Errorf("expected max null percentage 15, got %g", r.Parameters.MaxNullPercentage)
+	}
+	if r := byName["orders.updated_at freshness"]; r.Parameters.MaxAgeHours != 6 || r.Parameters.TimestampColumn != "updated_at" {
+		t.Errorf("expected 6 hour freshness on updated_at, got %+v", r.Parameters)
+	}
+}
+
+func TestRecommend_SkipsUninformativeColumns(t *testing.T) {
+	profile := &TableProfile{
+		Table:      "events",
+		RowCount:   20,
+		ProfiledAt: time.Now(),
+		Columns: []ColumnProfile{
+			// Approximate distinct counts cannot prove uniqueness
+			{Name: "user_id", Kind: KindString, DistinctCount: 20, DistinctApproximate: true},
+			// Too few rows per value to suggest a set
+			{Name: "country", Kind: KindString, DistinctCount: 8, TopValues: []ValueFrequency{{Value: "US", Count: 13}}},
+			// Historical data gets no freshness check
+			{Name: "created_at", Kind: KindTemporal, Max: time.Now().AddDate(-1, 0, 0)},
+			// Entirely null columns get no null limit
+			{Name: "unused", Kind: KindOther, NullCount: 20, NullPercentage: 100},
+		},
+	}
+
+	for _, r := range Recommend(profile) {
+		if r.Check.Type != check.TypeNullCheck || r.Check.Parameters.MaxNullPercentage != 0 {
+			t.Errorf("unexpected recommendation %s: %s", r.Check.Name, r.Rationale)
+		}
+	}
+}
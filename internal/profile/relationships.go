@@ -0,0 +1,313 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/vinod901/opendq-go/internal/check"
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+const (
+	defaultDiscoverySampleSize = 1000
+	defaultMinConfidence       = 0.5
+	// minContainment is the share of sampled child values that must exist in the parent
+	minContainment = 0.9
+)
+
+// keySuffixes are column name endings that mark identifier columns
+var keySuffixes = []string{"_id", "_key", "_code", "id"}
+
+// DiscoveryOptions configures relationship discovery
+type DiscoveryOptions struct {
+	Tables        []string `json:"tables,omitempty"`         // Restrict to these tables; defaults to all
+	SampleSize    int64    `json:"sample_size,omitempty"`    // Child rows sampled per candidate; defaults to 1000
+	MinConfidence float64  `json:"min_confidence,omitempty"` // Defaults to 0.5
+}
+
+// Relationship is a likely foreign key or inclusion dependency: values of the
+// child column are contained in the parent column
+type Relationship struct {
+	ChildTable        string       `json:"child_table"`
+	ChildColumn       string       `json:"child_column"`
+	ParentTable       string       `json:"parent_table"`
+	ParentColumn      string       `json:"parent_column"`
+	NameSimilarity    float64      `json:"name_similarity"`
+	TypeCompatibility float64      `json:"type_compatibility"`
+	Containment       float64      `json:"containment"` // Share of sampled distinct child values found in the parent
+	SampledValues     int64        `json:"sampled_values"`
+	Confidence        float64      `json:"confidence"`
+	Check             *check.Check `json:"check"` // Referential integrity check enforcing the relationship
+}
+
+// candidate is a column pair that passed the name and type filters
+type candidate struct {
+	child, parent        tableColumn
+	nameScore, typeScore float64
+}
+
+type tableColumn struct {
+	table  string
+	column datasource.ColumnInfo
+}
+
+// DiscoverRelationships finds likely parent/child column pairs across the
+// tables of a datasource. Pairs are shortlisted by name similarity and type
+// compatibility, then confirmed by checking that sampled child values exist
+// in the parent column.
+func (m *Manager) DiscoverRelationships(ctx context.Context, datasourceID string, opts DiscoveryOptions) ([]Relationship, error) {
+	ds, err := m.datasourceManager.GetDatasource(ctx, datasourceID)
+	if err != nil {
+		return nil, err
+	}
+	connector, err := m.datasourceManager.GetConnector(ctx, datasourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get datasource connector: %w", err)
+	}
+
+	ctx = datasource.WithQueryOrigin(ctx, datasource.QueryOrigin{Type: datasource.OriginProfile, ID: "relationships"})
+	relationships, err := DiscoverRelationships(ctx, connector, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range relationships {
+		r.Check.TenantID = ds.TenantID
+		r.Check.DatasourceID = datasourceID
+	}
+	return relationships, nil
+}
+
+// DiscoverRelationships finds likely relationships using a connector, ordered
+// by descending confidence
+func DiscoverRelationships(ctx context.Context, connector datasource.Connector, opts DiscoveryOptions) ([]Relationship, error) {
+	if opts.SampleSize <= 0 {
+		opts.SampleSize = defaultDiscoverySampleSize
+	}
+	if opts.MinConfidence <= 0 {
+		opts.MinConfidence = defaultMinConfidence
+	}
+
+	tables, err := discoveryTables(ctx, connector, opts.Tables)
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []tableColumn
+	for _, table := range tables {
+		tableColumns, err := connector.GetColumns(ctx, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get columns for %s: %w", table, err)
+		}
+		for _, col := range tableColumns {
+			columns = append(columns, tableColumn{table: table, column: col})
+		}
+	}
+
+	dialect := datasource.DialectFor(connector.Type())
+	relationships := []Relationship{}
+	for _, c := range candidates(columns) {
+		sampled, matched, err := containment(ctx, connector, dialect, c, opts.SampleSize)
+		if err != nil {
+			return nil, err
+		}
+		if sampled == 0 {
+			continue
+		}
+
+		contained := float64(matched) / float64(sampled)
+		confidence := contained * (0.7*c.nameScore + 0.3*c.typeScore)
+		if contained < minContainment || confidence < opts.MinConfidence {
+			continue
+		}
+
+		relationships = append(relationships, Relationship{
+			ChildTable:        c.child.table,
+			ChildColumn:       c.child.column.Name,
+			ParentTable:       c.parent.table,
+			ParentColumn:      c.parent.column.Name,
+			NameSimilarity:    c.nameScore,
+			TypeCompatibility: c.typeScore,
+			Containment:       contained,
+			SampledValues:     sampled,
+			Confidence:        math.Round(confidence*100) / 100,
+			Check:             referentialCheck(c, contained),
+		})
+	}
+
+	sort.SliceStable(relationships, func(i, j int) bool {
+		return relationships[i].Confidence > relationships[j].Confidence
+	})
+	return relationships, nil
+}
+
+// discoveryTables returns the names of the tables to analyze, qualified by
+// schema so that their columns and the containment queries are looked up in
+// that schema
+func discoveryTables(ctx context.Context, connector datasource.Connector, only []string) ([]string, error) {
+	if len(only) > 0 {
+		return only, nil
+	}
+	infos, err := connector.GetTables(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tables: %w", err)
+	}
+	tables := make([]string, 0, len(infos))
+	for _, t := range infos {
+		if t.Schema == "" {
+			tables = append(tables, t.Name)
+		} else {
+			tables = append(tables, t.Schema+"."+t.Name)
+		}
+	}
+	return tables, nil
+}
+
+// candidates returns the column pairs across different tables whose names
+// suggest a reference and whose types can be compared
+func candidates(columns []tableColumn) []candidate {
+	var result []candidate
+	for _, child := range columns {
+		for _, parent := range columns {
+			if child.table == parent.table {
+				continue
+			}
+			nameScore := nameSimilarity(child.column.Name, parent.table, parent.column.Name)
+			if nameScore == 0 {
+				continue
+			}
+			typeScore := typeCompatibility(child.column.DataType, parent.column.DataType)
+			if typeScore == 0 {
+				continue
+			}
+			result = append(result, candidate{child: child, parent: parent, nameScore: nameScore, typeScore: typeScore})
+		}
+	}
+	return result
+}
+
+// nameSimilarity scores how strongly a child column name points at a parent
+// table's column, e.g. orders.customer_id -> customers.id
+func nameSimilarity(childColumn, parentTable, parentColumn string) float64 {
+	child, parent := strings.ToLower(childColumn), strings.ToLower(parentColumn)
+	entity := singular(strings.ToLower(baseName(parentTable)))
+
+	stem, ok := keyStem(child)
+	if !ok {
+		return 0
+	}
+
+	// The parent column is the parent table's own identifier
+	if parent == "id" || parent == entity+"_id" || parent == entity+"id" {
+		switch {
+		case stem == entity:
+			return 1.0
+		case strings.HasSuffix(stem, "_"+entity):
+			return 0.8 // Role-prefixed reference such as billing_customer_id
+		}
+		return 0
+	}
+
+	// The same identifier name appears in both tables
+	if child == parent {
+		if stem == entity {
+			return 0.9
+		}
+		return 0.6
+	}
+	return 0
+}
+
+// keyStem strips an identifier suffix from a column name
+func keyStem(name string) (string, bool) {
+	for _, suffix := range keySuffixes {
+		if strings.HasSuffix(name, suffix) && len(name) > len(suffix) {
+			return strings.TrimSuffix(name, suffix), true
+		}
+	}
+	return "", false
+}
+
+// typeCompatibility scores whether values of two identifier types can be
+// compared directly: 1 for identical types, 0.8 for the same kind of type
+func typeCompatibility(childType, parentType string) float64 {
+	childKind, parentKind := ClassifyType(childType), ClassifyType(parentType)
+	switch {
+	case childKind != parentKind || (childKind != KindNumeric && childKind != KindString):
+		return 0
+	case strings.EqualFold(childType, parentType):
+		return 1.0
+	default:
+		return 0.8
+	}
+}
+
+// containment samples distinct child values and counts how many exist in the parent column
+func containment(ctx context.Context, connector datasource.Connector, dialect datasource.Dialect, c candidate, sampleSize int64) (int64, int64, error) {
+	sampled, err := dialect.SampledTable(c.child.table, datasource.Sample{RowCount: sampleSize})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to build table sample: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT COUNT(*) AS sampled_count, COUNT(p.v) AS matched_count
+		FROM (SELECT DISTINCT _child.%s AS v FROM %s _child WHERE _child.%s IS NOT NULL) c
+		LEFT JOIN (SELECT DISTINCT %s AS v FROM %s) p ON c.v = p.v`,
+		c.child.column.Name, sampled, c.child.column.Name, c.parent.column.Name, c.parent.table)
+
+	result, err := connector.Query(ctx, query)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to check containment of %s.%s in %s.%s: %w",
+			c.child.table, c.child.column.Name, c.parent.table, c.parent.column.Name, err)
+	}
+	if len(result.Rows) == 0 {
+		return 0, 0, nil
+	}
+	return toInt64(result.Rows[0]["sampled_count"]), toInt64(result.Rows[0]["matched_count"]), nil
+}
+
+// referentialCheck builds the referential integrity check for a relationship,
+// expecting the containment observed in the sample
+func referentialCheck(c candidate, contained float64) *check.Check {
+	threshold := check.Threshold{}
+	if contained < 1 {
+		threshold = check.Threshold{Type: check.ThresholdPercentage, Value: math.Floor(contained * 100)}
+	}
+	return &check.Check{
+		Name:        fmt.Sprintf("%s.%s references %s.%s", c.child.table, c.child.column.Name, c.parent.table, c.parent.column.Name),
+		Description: "discovered relationship",
+		Type:        check.TypeReferentialIntegrity,
+		Table:       c.child.table,
+		Column:      c.child.column.Name,
+		Parameters: check.CheckParameters{
+			ReferenceTable:  c.parent.table,
+			ReferenceColumn: c.parent.column.Name,
+		},
+		Threshold: threshold,
+		Severity:  check.SeverityMedium,
+		Tags:      []string{"recommended", "relationship"},
+	}
+}
+
+// baseName strips the schema from a qualified table name
+func baseName(table string) string {
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		return table[i+1:]
+	}
+	return table
+}
+
+// singular returns a naive singular form of a table name
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"), strings.HasSuffix(name, "ches"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss"):
+		return strings.TrimSuffix(name, "s")
+	}
+	return name
+}
@@ -0,0 +1,128 @@
+package profile
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vinod901/opendq-go/internal/check"
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+func TestNameSimilarity(t *testing.T) {
+	testCases := []struct {
+		child, parentTable, parent string
+		expected                   float64
+	}{
+		{"customer_id", "customers", "id", 1.0},
+		{"customer_id", "public.customers", "customer_id", 1.0},
+		{"category_id", "categories", "id", 1.0},
+		{"billing_customer_id", "customers", "id", 0.8},
+		{"region_code", "stores", "region_code", 0.6},
+		{"customer_id", "orders", "id", 0},
+		{"name", "customers", "name", 0},
+		{"id", "customers", "id", 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.child+"->"+tc.parentTable+"."+tc.parent, func(t *testing.T) {
+			if got := nameSimilarity(tc.child, tc.parentTable, tc.parent); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestTypeCompatibility(t *testing.T) {
+	if got := typeCompatibility("integer", "INTEGER"); got != 1.0 {
+		t.Errorf("expected identical types to score 1, got %v", got)
+	}
+	if got := typeCompatibility("integer", "bigint"); got != 0.8 {
+		t.Errorf("expected numeric types to score 0.8, got %v", got)
+	}
+	if got := typeCompatibility("integer", "text"); got != 0 {
+		t.Errorf("expected incompatible types to score 0, got %v", got)
+	}
+	if got := typeCompatibility("boolean", "boolean"); got != 0 {
+		t.Errorf("expected boolean columns to be excluded, got %v", got)
+	}
+}
+
+func TestDiscoverRelationships(t *testing.T) {
+	connector := &fakeConnector{
+		tables: map[string][]datasource.ColumnInfo{
+			"customers": {{Name: "id", DataType: "integer"}, {Name: "name", DataType: "text"}},
+			"orders": {
+				{Name: "id", DataType: "integer"},
+				{Name: "customer_id", DataType: "bigint"},
+				{Name: "store_id", DataType: "integer"},
+			},
+			"stores": {{Name: "id", DataType: "integer"}},
+		},
+		responses: map[string][]map[string]interface{}{
+			"_child.customer_id": {{"sampled_count": int64(200), "matched_count": int64(200)}},
+			// Only half the sampled store ids exist: not a relationship
+			"_child.store_id": {{"sampled_count": int64(100), "matched_count": int64(50)}},
+		},
+	}
+
+	relationships, err := DiscoverRelationships(context.Background(), connector, DiscoveryOptions{
+		Tables: []string{"customers", "orders", "stores"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(relationships) != 1 {
+		t.Fatalf("expected 1 relationship, got %d: %+v", len(relationships), relationships)
+	}
+
+	r := relationships[0]
+	if r.ChildTable != "orders" || r.ChildColumn != "customer_id" || r.ParentTable != "customers" || r.ParentColumn != "id" {
+		t.Errorf("unexpected relationship: %+v", r)
+	}
+	if r.Containment != 1 || r.SampledValues != 200 {
+		t.Errorf("expected full containment of 200 values, got %v of %d", r.Containment, r.SampledValues)
+	}
+	if r.Confidence != 0.94 {
+		t.Errorf("expected confidence 0.94, got %v", r.Confidence)
+	}
+
+	c := r.Check
+	if c.Type != check.TypeReferentialIntegrity || c.Parameters.ReferenceTable != "customers" || c.Parameters.ReferenceColumn != "id" {
+		t.Errorf("unexpected check: %+v", c)
+	}
+
+	// Only the two shortlisted pairs are sampled
+	if len(connector.queries) != 2 {
+		t.Errorf("expected 2 containment queries, got %d", len(connector.queries))
+	}
+}
+
+func TestDiscoverRelationships_QualifiedTables(t *testing.T) {
+	// Columns are only known by schema-qualified name, as on PostgreSQL
+	connector := &fakeConnector{
+		infos: []datasource.TableInfo{{Schema: "sales", Name: "customers"}, {Schema: "sales", Name: "orders"}},
+		tables: map[string][]datasource.ColumnInfo{
+			"sales.customers": {{Name: "id", DataType: "integer"}},
+			"sales.orders":    {{Name: "id", DataType: "integer"}, {Name: "customer_id", DataType: "integer"}},
+		},
+		responses: map[string][]map[string]interface{}{
+			"_child.customer_id": {{"sampled_count": int64(10), "matched_count": int64(10)}},
+		},
+	}
+
+	relationships, err := DiscoverRelationships(context.Background(), connector, DiscoveryOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(relationships) != 1 {
+		t.Fatalf("expected 1 relationship, got %d: %+v", len(relationships), relationships)
+	}
+	r := relationships[0]
+	if r.ChildTable != "sales.orders" || r.ParentTable != "sales.customers" || r.ParentColumn != "id" {
+		t.Errorf("unexpected relationship: %+v", r)
+	}
+	if len(connector.queries) != 1 || !strings.Contains(connector.queries[0], "FROM sales.orders") {
+		t.Errorf("expected the containment query to read sales.orders, got %v", connector.queries)
+	}
+}
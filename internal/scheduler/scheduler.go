@@ -5,6 +5,7 @@ package scheduler
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -353,13 +354,8 @@ func (m *Manager) executeSchedule(ctx context.Context, schedule *Schedule) (*Sch
 		checkIDs = schedule.CheckIDs
 	}
 
-	// Execute checks
-	for _, checkID := range checkIDs {
-		result, err := m.checkManager.RunCheck(ctx, checkID)
-		if err != nil {
-			execution.Summary.ErrorChecks++
-			continue
-		}
+	// Execute checks in parallel; a check that fails to run gets an error result
+	for _, result := range m.checkManager.RunChecks(ctx, checkIDs) {
 		execution.Results = append(execution.Results, result)
 
 		// Update summary
@@ -409,12 +405,41 @@ func (m *Manager) executeSchedule(ctx context.Context, schedule *Schedule) (*Sch
 	return execution, nil
 }
 
+// maxAlertSegments caps the failing segments named in an alert message; the
+// alert details list them all
+const maxAlertSegments = 10
+
 // sendAlerts sends alerts for failed checks
 func (m *Manager) sendAlerts(ctx context.Context, schedule *Schedule, execution *ScheduleExecution) {
+	message := fmt.Sprintf("%d of %d checks failed", execution.Summary.FailedChecks, execution.Summary.TotalChecks)
+
+	// Name the failing segments of segmented checks
+	var failures []map[string]interface{}
+	var segments []string
+	for _, result := range execution.Results {
+		if result.Status != check.StatusFailed {
+			continue
+		}
+		failure := map[string]interface{}{
+			"check_id": result.CheckID,
+			"message":  result.Message,
+		}
+		if failed := result.FailedSegments(); len(failed) > 0 {
+			failure["segments"] = failed
+			segments = append(segments, failed...)
+		}
+		failures = append(failures, failure)
+	}
+	if len(segments) > maxAlertSegments {
+		message += fmt.Sprintf("; failing segments: %s and %d more", strings.Join(segments[:maxAlertSegments], "; "), len(segments)-maxAlertSegments)
+	} else if len(segments) > 0 {
+		message += fmt.Sprintf("; failing segments: %s", strings.Join(segments, "; "))
+	}
+
 	for _, channelID := range schedule.AlertChannelIDs {
 		alert := &alerting.Alert{
 			Title:       fmt.Sprintf("Data Quality Check Failures - %s", schedule.Name),
-			Message:     fmt.Sprintf("%d of %d checks failed", execution.Summary.FailedChecks, execution.Summary.TotalChecks),
+			Message:     message,
 			Severity:    alerting.SeverityHigh,
 			ScheduleID:  schedule.ID,
 			ExecutionID: execution.ID,
@@ -422,6 +447,7 @@ func (m *Manager) sendAlerts(ctx context.Context, schedule *Schedule, execution
 				"summary":     execution.Summary,
 				"schedule":    schedule.Name,
 				"executed_at": execution.StartedAt,
+				"failures":    failures,
 			},
 		}
 
@@ -0,0 +1,227 @@
+package tablediff
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+// JobStatus is the state of an on-demand diff
+type JobStatus string
+
+const (
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+)
+
+// Request asks for a diff between tables of two datasources, which may be
+// the same datasource
+type Request struct {
+	SourceDatasourceID string `json:"source_datasource_id"`
+	SourceTable        string `json:"source_table"`
+	TargetDatasourceID string `json:"target_datasource_id"`
+	TargetTable        string `json:"target_table"`
+	Where              string `json:"where,omitempty"` // Applied to both sides
+	Options
+}
+
+// Job is an on-demand diff running in the background
+type Job struct {
+	ID          string     `json:"id"`
+	Request     Request    `json:"request"`
+	Status      JobStatus  `json:"status"`
+	Progress    Progress   `json:"progress"`
+	Result      *Result    `json:"result,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// DefaultMaxJobs is how many jobs a new Manager keeps
+const DefaultMaxJobs = 100
+
+// RunLimiter shares the run slots and query budgets of check runs with diffs
+type RunLimiter interface {
+	// AcquireRunSlot waits for a slot against the datasources and returns
+	// the function releasing it
+	AcquireRunSlot(ctx context.Context, datasourceIDs ...string) (func(), error)
+	// BudgetConnector wraps a datasource's connector to enforce its query budget
+	BudgetConnector(ctx context.Context, datasourceID string, connector datasource.Connector) datasource.Connector
+}
+
+// Manager runs on-demand diffs and keeps their jobs
+type Manager struct {
+	datasourceManager *datasource.Manager
+	limiter           RunLimiter
+	maxJobs           int
+	jobs              map[string]*Job
+	mu                sync.RWMutex
+}
+
+// NewManager creates a new diff manager
+func NewManager(dsManager *datasource.Manager) *Manager {
+	return &Manager{
+		datasourceManager: dsManager,
+		maxJobs:           DefaultMaxJobs,
+		jobs:              make(map[string]*Job),
+	}
+}
+
+// SetRunLimiter makes diffs take run slots and apply query budgets through
+// limiter. Without one, diffs run unlimited.
+func (m *Manager) SetRunLimiter(limiter RunLimiter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.limiter = limiter
+}
+
+// SetMaxJobs sets how many jobs are kept. Once exceeded, the oldest finished
+// jobs are dropped; running jobs are always kept.
+func (m *Manager) SetMaxJobs(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if n <= 0 {
+		n = DefaultMaxJobs
+	}
+	m.maxJobs = n
+	m.evictJobs()
+}
+
+// StartDiff validates a request and starts its diff in the background. The
+// diff outlives ctx's cancellation; poll GetJob for its progress and result.
+func (m *Manager) StartDiff(ctx context.Context, req Request) (*Job, error) {
+	if req.SourceDatasourceID == "" || req.TargetDatasourceID == "" || req.SourceTable == "" || req.TargetTable == "" {
+		return nil, fmt.Errorf("source and target datasource and table are required")
+	}
+	if req.KeyColumn == "" {
+		return nil, fmt.Errorf("key column is required")
+	}
+	if err := datasource.ValidatePredicate(req.Where); err != nil {
+		return nil, err
+	}
+	source, err := m.datasourceManager.GetConnector(ctx, req.SourceDatasourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source datasource connector: %w", err)
+	}
+	target, err := m.datasourceManager.GetConnector(ctx, req.TargetDatasourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target datasource connector: %w", err)
+	}
+
+	job := &Job{
+		ID:        uuid.New().String(),
+		Request:   req,
+		Status:    JobRunning,
+		StartedAt: time.Now(),
+	}
+	m.mu.Lock()
+	m.jobs[job.ID] = job
+	m.evictJobs()
+	limiter := m.limiter
+	m.mu.Unlock()
+
+	opts := req.Options
+	opts.OnProgress = func(p Progress) {
+		m.mu.Lock()
+		job.Progress = p
+		m.mu.Unlock()
+	}
+	runCtx := datasource.WithQueryOrigin(context.WithoutCancel(ctx), datasource.QueryOrigin{Type: datasource.OriginDiff, ID: job.ID})
+	go func() {
+		var result *Result
+		var err error
+		if limiter != nil {
+			var release func()
+			release, err = limiter.AcquireRunSlot(runCtx, req.SourceDatasourceID, req.TargetDatasourceID)
+			if err == nil {
+				source = limiter.BudgetConnector(runCtx, req.SourceDatasourceID, source)
+				target = limiter.BudgetConnector(runCtx, req.TargetDatasourceID, target)
+			}
+			if release != nil {
+				defer release()
+			}
+		}
+		if err == nil {
+			result, err = Diff(runCtx,
+				Table{Connector: source, Name: req.SourceTable, Where: req.Where},
+				Table{Connector: target, Name: req.TargetTable, Where: req.Where},
+				opts)
+		}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		now := time.Now()
+		job.CompletedAt = &now
+		if err != nil {
+			job.Status = JobFailed
+			job.Error = err.Error()
+			return
+		}
+		job.Status = JobCompleted
+		job.Result = result
+	}()
+
+	return m.copyJob(job), nil
+}
+
+// GetJob returns a diff job
+func (m *Manager) GetJob(ctx context.Context, id string) (*Job, error) {
+	m.mu.RLock()
+	job, exists := m.jobs[id]
+	m.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("diff job not found: %s", id)
+	}
+	return m.copyJob(job), nil
+}
+
+// ListJobs returns the diff jobs, newest first
+func (m *Manager) ListJobs(ctx context.Context) ([]*Job, error) {
+	m.mu.RLock()
+	jobs := make([]*Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		jobs = append(jobs, job)
+	}
+	m.mu.RUnlock()
+
+	for i, job := range jobs {
+		jobs[i] = m.copyJob(job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].StartedAt.After(jobs[j].StartedAt) })
+	return jobs, nil
+}
+
+// evictJobs drops the oldest finished jobs beyond the cap. It must be called
+// with the lock held.
+func (m *Manager) evictJobs() {
+	if len(m.jobs) <= m.maxJobs {
+		return
+	}
+	var finished []*Job
+	for _, job := range m.jobs {
+		if job.Status != JobRunning {
+			finished = append(finished, job)
+		}
+	}
+	sort.Slice(finished, func(i, j int) bool { return finished[i].StartedAt.Before(finished[j].StartedAt) })
+	for _, job := range finished {
+		if len(m.jobs) <= m.maxJobs {
+			return
+		}
+		delete(m.jobs, job.ID)
+	}
+}
+
+// copyJob copies a job under the lock, so that callers see a consistent state
+func (m *Manager) copyJob(job *Job) *Job {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c := *job
+	return &c
+}
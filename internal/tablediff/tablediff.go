@@ -0,0 +1,581 @@
+// Package tablediff finds the rows that differ between two tables, possibly
+// on different engines, by bisecting their primary key range: it compares
+// checksums of key range segments on both sides, recurses into the segments
+// that differ, and compares small segments row by row.
+package tablediff
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+const (
+	defaultBisectionFactor    = 8
+	defaultBisectionThreshold = 1000
+	defaultMaxDiffs           = 1000
+)
+
+// KeyType is how key values are ordered, split and rendered in queries
+type KeyType string
+
+const (
+	KeyInteger   KeyType = "integer"   // Ranges are split arithmetically
+	KeyText      KeyType = "text"      // Ranges are split at the source's key quantiles
+	KeyTimestamp KeyType = "timestamp" // Ranges are split at the source's key quantiles
+)
+
+// Table is one side of a diff
+type Table struct {
+	Connector datasource.Connector
+	Name      string
+	Where     string // Optional predicate restricting the compared rows
+}
+
+// Options configures a diff
+type Options struct {
+	KeyColumn          string   `json:"key_column"`                    // Unique key, named alike on both sides
+	KeyType            KeyType  `json:"key_type,omitempty"`            // Inferred from the key values by default
+	Columns            []string `json:"columns,omitempty"`             // Compared columns; without any, only added and removed keys are found
+	BisectionFactor    int      `json:"bisection_factor,omitempty"`    // Segments a differing segment is split into, default 8
+	BisectionThreshold int      `json:"bisection_threshold,omitempty"` // Rows per side below which a segment is compared row by row, default 1000
+	MaxDiffs           int      `json:"max_diffs,omitempty"`           // Differing keys after which the diff stops, default 1000
+
+	// OnProgress, when set, is called after each segment is checksummed
+	OnProgress func(Progress) `json:"-"`
+}
+
+// Progress reports how far a diff has got
+type Progress struct {
+	Fraction    float64 `json:"fraction"` // Share of the key range resolved, from 0 to 1
+	Segments    int     `json:"segments"` // Segments checksummed
+	RowsFetched int64   `json:"rows_fetched"`
+	Diffs       int     `json:"diffs"`
+}
+
+// Result lists the keys that differ. Added keys are in the target only,
+// removed keys in the source only, and changed keys on both sides with
+// different column values. Keys are sorted.
+type Result struct {
+	Added      []interface{} `json:"added"`
+	Removed    []interface{} `json:"removed"`
+	Changed    []interface{} `json:"changed"`
+	SourceRows int64         `json:"source_rows"`
+	TargetRows int64         `json:"target_rows"`
+	Segments   int           `json:"segments"`            // Segments checksummed
+	Truncated  bool          `json:"truncated,omitempty"` // The diff stopped at MaxDiffs
+}
+
+// Total returns the number of differing keys
+func (r *Result) Total() int {
+	return len(r.Added) + len(r.Removed) + len(r.Changed)
+}
+
+// side is a table prepared for diff queries
+type side struct {
+	table    Table
+	dialect  datasource.Dialect
+	from     string
+	checksum string // Row checksum expression
+}
+
+// segment is a key range, from lo inclusive to hi, inclusive when closed
+type segment struct {
+	lo, hi interface{}
+	closed bool
+}
+
+// differ holds the state of one diff
+type differ struct {
+	source, target *side
+	opts           Options
+	keyType        KeyType
+	result         *Result
+	progress       Progress
+	added          []interface{}
+	removed        []interface{}
+	changed        []interface{}
+}
+
+// Diff finds the rows that differ between source and target
+func Diff(ctx context.Context, source, target Table, opts Options) (*Result, error) {
+	if opts.KeyColumn == "" {
+		return nil, fmt.Errorf("key column is required")
+	}
+	if source.Connector == nil || target.Connector == nil || source.Name == "" || target.Name == "" {
+		return nil, fmt.Errorf("source and target tables are required")
+	}
+	switch opts.KeyType {
+	case "", KeyInteger, KeyText, KeyTimestamp:
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", opts.KeyType)
+	}
+	if opts.BisectionFactor < 2 {
+		opts.BisectionFactor = defaultBisectionFactor
+	}
+	if opts.BisectionThreshold <= 0 {
+		opts.BisectionThreshold = defaultBisectionThreshold
+	}
+	if opts.MaxDiffs <= 0 {
+		opts.MaxDiffs = defaultMaxDiffs
+	}
+
+	d := &differ{opts: opts, keyType: opts.KeyType, result: &Result{}}
+	var err error
+	if d.source, err = newSide(source, opts); err != nil {
+		return nil, fmt.Errorf("source: %w", err)
+	}
+	if d.target, err = newSide(target, opts); err != nil {
+		return nil, fmt.Errorf("target: %w", err)
+	}
+
+	whole, err := d.bounds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if whole != nil {
+		if err := d.diffSegment(ctx, *whole, 1); err != nil {
+			return nil, err
+		}
+	}
+	d.progress.Fraction = 1
+	d.report()
+
+	d.result.Added = d.sortKeys(d.added)
+	d.result.Removed = d.sortKeys(d.removed)
+	d.result.Changed = d.sortKeys(d.changed)
+	return d.result, nil
+}
+
+func newSide(table Table, opts Options) (*side, error) {
+	dialect := datasource.DialectFor(table.Connector.Type())
+	checksum, err := dialect.RowChecksum(append([]string{opts.KeyColumn}, opts.Columns...))
+	if err != nil {
+		return nil, err
+	}
+	from := table.Name
+	if table.Where != "" {
+		from = fmt.Sprintf("(SELECT * FROM %s WHERE %s) _diff", table.Name, table.Where)
+	}
+	return &side{table: table, dialect: dialect, from: from, checksum: checksum}, nil
+}
+
+// bounds counts both sides and returns the segment spanning the keys of
+// both, or nil when both are empty
+func (d *differ) bounds(ctx context.Context) (*segment, error) {
+	query := "SELECT COUNT(*) as row_count, MIN(%s) as min_key, MAX(%s) as max_key FROM %s"
+	key := d.opts.KeyColumn
+	sourceRow, err := queryRow(ctx, d.source, fmt.Sprintf(query, key, key, d.source.from))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source bounds: %w", err)
+	}
+	targetRow, err := queryRow(ctx, d.target, fmt.Sprintf(query, key, key, d.target.from))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read target bounds: %w", err)
+	}
+	d.result.SourceRows = toInt64(sourceRow["row_count"])
+	d.result.TargetRows = toInt64(targetRow["row_count"])
+
+	if d.keyType == "" {
+		d.keyType = inferKeyType(sourceRow["min_key"], targetRow["min_key"])
+	}
+	var lo, hi interface{}
+	for _, row := range []map[string]interface{}{sourceRow, targetRow} {
+		if row["min_key"] == nil {
+			continue
+		}
+		minKey, err := d.normalizeKey(row["min_key"])
+		if err != nil {
+			return nil, err
+		}
+		maxKey, err := d.normalizeKey(row["max_key"])
+		if err != nil {
+			return nil, err
+		}
+		if lo == nil || d.compareKeys(minKey, lo) < 0 {
+			lo = minKey
+		}
+		if hi == nil || d.compareKeys(maxKey, hi) > 0 {
+			hi = maxKey
+		}
+	}
+	if lo == nil {
+		return nil, nil
+	}
+	return &segment{lo: lo, hi: hi, closed: true}, nil
+}
+
+// diffSegment compares a segment on both sides, recursing into its parts
+// when their checksums differ. share is the segment's part of the key range.
+func (d *differ) diffSegment(ctx context.Context, seg segment, share float64) error {
+	if d.full() {
+		return nil
+	}
+
+	sourceCount, sourceSum, err := d.checksum(ctx, d.source, seg)
+	if err != nil {
+		return fmt.Errorf("failed to checksum source segment: %w", err)
+	}
+	targetCount, targetSum, err := d.checksum(ctx, d.target, seg)
+	if err != nil {
+		return fmt.Errorf("failed to checksum target segment: %w", err)
+	}
+	d.result.Segments++
+	d.progress.Segments++
+
+	if sourceCount == targetCount && sourceSum == targetSum {
+		d.advance(share)
+		return nil
+	}
+
+	var parts []segment
+	if sourceCount > int64(d.opts.BisectionThreshold) || targetCount > int64(d.opts.BisectionThreshold) {
+		larger := d.source
+		if targetCount > sourceCount {
+			larger = d.target
+		}
+		if parts, err = d.split(ctx, larger, seg, max(sourceCount, targetCount)); err != nil {
+			return fmt.Errorf("failed to split segment: %w", err)
+		}
+	}
+	if len(parts) < 2 {
+		if err := d.compareRows(ctx, seg); err != nil {
+			return err
+		}
+		d.advance(share)
+		return nil
+	}
+
+	for _, part := range parts {
+		if err := d.diffSegment(ctx, part, share/float64(len(parts))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checksum returns the row count and checksum of a segment on one side. The
+// checksum is compared as a float, since engines return sums in different
+// types; a sum rounded differently only causes a needless bisection.
+func (d *differ) checksum(ctx context.Context, s *side, seg segment) (int64, float64, error) {
+	row, err := queryRow(ctx, s, fmt.Sprintf("SELECT COUNT(*) as row_count, SUM(%s) as checksum FROM %s WHERE %s",
+		s.checksum, s.from, d.rangePredicate(s, seg)))
+	if err != nil {
+		return 0, 0, err
+	}
+	return toInt64(row["row_count"]), toFloat64(row["checksum"]), nil
+}
+
+// split divides a segment into up to BisectionFactor parts: integer ranges
+// arithmetically, others at the key quantiles of the side holding more rows
+func (d *differ) split(ctx context.Context, s *side, seg segment, rows int64) ([]segment, error) {
+	factor := int64(d.opts.BisectionFactor)
+	var bounds []interface{}
+
+	if d.keyType == KeyInteger {
+		lo, hi := seg.lo.(int64), seg.hi.(int64)
+		step := (hi - lo + factor) / factor
+		if step < 1 {
+			step = 1
+		}
+		for b := lo + step; b > lo && (b < hi || seg.closed && b == hi); b += step {
+			bounds = append(bounds, b)
+		}
+	} else {
+		step := (rows + factor - 1) / factor
+		if step < 1 {
+			step = 1
+		}
+		key := d.opts.KeyColumn
+		result, err := s.table.Connector.Query(ctx, fmt.Sprintf(
+			"SELECT bound FROM (SELECT %s as bound, ROW_NUMBER() OVER (ORDER BY %s) as rn FROM %s WHERE %s) _keys WHERE rn > 1 AND %s = 0 ORDER BY bound",
+			key, key, s.from, d.rangePredicate(s, seg), s.dialect.Mod("rn - 1", step)))
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range result.Rows {
+			bound, err := d.normalizeKey(row["bound"])
+			if err != nil {
+				return nil, err
+			}
+			if d.compareKeys(bound, seg.lo) > 0 {
+				bounds = append(bounds, bound)
+			}
+		}
+	}
+
+	parts := make([]segment, 0, len(bounds)+1)
+	lo := seg.lo
+	for _, bound := range bounds {
+		parts = append(parts, segment{lo: lo, hi: bound})
+		lo = bound
+	}
+	return append(parts, segment{lo: lo, hi: seg.hi, closed: seg.closed}), nil
+}
+
+// compareRows fetches the key and row checksum of a segment's rows on both
+// sides and records the keys that differ
+func (d *differ) compareRows(ctx context.Context, seg segment) error {
+	sourceRows, err := d.rowChecksums(ctx, d.source, seg)
+	if err != nil {
+		return fmt.Errorf("failed to read source rows: %w", err)
+	}
+	targetRows, err := d.rowChecksums(ctx, d.target, seg)
+	if err != nil {
+		return fmt.Errorf("failed to read target rows: %w", err)
+	}
+
+	for id, source := range sourceRows {
+		target, ok := targetRows[id]
+		switch {
+		case !ok:
+			d.removed = append(d.removed, source.key)
+		case source.checksum != target.checksum:
+			d.changed = append(d.changed, source.key)
+		}
+	}
+	for id, target := range targetRows {
+		if _, ok := sourceRows[id]; !ok {
+			d.added = append(d.added, target.key)
+		}
+	}
+	d.progress.Diffs = len(d.added) + len(d.removed) + len(d.changed)
+	return nil
+}
+
+// keyedChecksum is a row's key and checksum
+type keyedChecksum struct {
+	key      interface{}
+	checksum int64
+}
+
+// rowChecksums returns the checksums of a segment's rows on one side, keyed
+// by the text of their key
+func (d *differ) rowChecksums(ctx context.Context, s *side, seg segment) (map[string]keyedChecksum, error) {
+	result, err := s.table.Connector.Query(ctx, fmt.Sprintf("SELECT %s as row_key, %s as row_checksum FROM %s WHERE %s",
+		d.opts.KeyColumn, s.checksum, s.from, d.rangePredicate(s, seg)))
+	if err != nil {
+		return nil, err
+	}
+	d.progress.RowsFetched += int64(len(result.Rows))
+
+	rows := make(map[string]keyedChecksum, len(result.Rows))
+	for _, row := range result.Rows {
+		key, err := d.normalizeKey(row["row_key"])
+		if err != nil {
+			return nil, err
+		}
+		rows[keyText(key)] = keyedChecksum{key: key, checksum: toInt64(row["row_checksum"])}
+	}
+	return rows, nil
+}
+
+// full reports whether MaxDiffs differing keys have been found
+func (d *differ) full() bool {
+	if d.progress.Diffs >= d.opts.MaxDiffs {
+		d.result.Truncated = true
+		return true
+	}
+	return false
+}
+
+// advance marks a share of the key range resolved
+func (d *differ) advance(share float64) {
+	d.progress.Fraction += share
+	if d.progress.Fraction > 1 {
+		d.progress.Fraction = 1
+	}
+	d.report()
+}
+
+func (d *differ) report() {
+	if d.opts.OnProgress != nil {
+		d.opts.OnProgress(d.progress)
+	}
+}
+
+// rangePredicate renders a segment as a predicate on the key, with literals
+// in the side's dialect
+func (d *differ) rangePredicate(s *side, seg segment) string {
+	key := d.opts.KeyColumn
+	upper := "<"
+	if seg.closed {
+		upper = "<="
+	}
+	return fmt.Sprintf("%s >= %s AND %s %s %s", key, d.literal(s, seg.lo), key, upper, d.literal(s, seg.hi))
+}
+
+// literal renders a normalized key value as a SQL literal
+func (d *differ) literal(s *side, key interface{}) string {
+	switch v := key.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case time.Time:
+		return s.dialect.TimestampLiteral(v)
+	default:
+		return "'" + strings.ReplaceAll(fmt.Sprint(v), "'", "''") + "'"
+	}
+}
+
+// inferKeyType infers the key type from the first non-nil of the given key
+// values as returned by drivers. Text holding a canonical integer, as some
+// drivers return numbers, is an integer.
+func inferKeyType(values ...interface{}) KeyType {
+	for _, v := range values {
+		switch val := v.(type) {
+		case nil:
+			continue
+		case int64, int, int32:
+			return KeyInteger
+		case float64:
+			if val == float64(int64(val)) {
+				return KeyInteger
+			}
+			return KeyText
+		case time.Time:
+			return KeyTimestamp
+		case []byte, string:
+			text := fmt.Sprintf("%s", val)
+			if n, err := strconv.ParseInt(text, 10, 64); err == nil && strconv.FormatInt(n, 10) == text {
+				return KeyInteger
+			}
+			return KeyText
+		default:
+			return KeyText
+		}
+	}
+	return KeyText
+}
+
+// normalizeKey converts a key value returned by a driver to the diff's key
+// type: int64, string or time.Time
+func (d *differ) normalizeKey(v interface{}) (interface{}, error) {
+	switch d.keyType {
+	case KeyInteger:
+		switch val := v.(type) {
+		case int64, int, int32:
+			return toInt64(val), nil
+		case float64:
+			return int64(val), nil
+		case []byte, string:
+			n, err := strconv.ParseInt(strings.TrimSpace(fmt.Sprintf("%s", val)), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("key %q is not an integer", val)
+			}
+			return n, nil
+		}
+		return nil, fmt.Errorf("key %v is not an integer", v)
+	case KeyTimestamp:
+		switch val := v.(type) {
+		case time.Time:
+			return val.UTC(), nil
+		case []byte, string:
+			text := fmt.Sprintf("%s", val)
+			for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02"} {
+				if t, err := time.Parse(layout, text); err == nil {
+					return t.UTC(), nil
+				}
+			}
+			return nil, fmt.Errorf("key %q is not a timestamp", text)
+		}
+		return nil, fmt.Errorf("key %v is not a timestamp", v)
+	default:
+		switch val := v.(type) {
+		case []byte:
+			return string(val), nil
+		case string:
+			return val, nil
+		default:
+			return fmt.Sprint(val), nil
+		}
+	}
+}
+
+// compareKeys orders two normalized keys
+func (d *differ) compareKeys(a, b interface{}) int {
+	switch x := a.(type) {
+	case int64:
+		y := b.(int64)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case time.Time:
+		return x.Compare(b.(time.Time))
+	default:
+		return strings.Compare(a.(string), b.(string))
+	}
+}
+
+// sortKeys sorts normalized keys, keeping nil slices empty for JSON
+func (d *differ) sortKeys(keys []interface{}) []interface{} {
+	if keys == nil {
+		return []interface{}{}
+	}
+	sort.Slice(keys, func(i, j int) bool { return d.compareKeys(keys[i], keys[j]) < 0 })
+	return keys
+}
+
+// keyText renders a normalized key for matching rows across sides
+func keyText(key interface{}) string {
+	if t, ok := key.(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(key)
+}
+
+// queryRow runs a query on one side and returns its first row
+func queryRow(ctx context.Context, s *side, query string) (map[string]interface{}, error) {
+	result, err := s.table.Connector.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Rows) == 0 {
+		return nil, fmt.Errorf("query returned no results")
+	}
+	return result.Rows[0], nil
+}
+
+func toInt64(v interface{}) int64 {
+	switch val := v.(type) {
+	case int64:
+		return val
+	case int:
+		return int64(val)
+	case int32:
+		return int64(val)
+	case float64:
+		return int64(val)
+	case []byte, string:
+		n, _ := strconv.ParseFloat(strings.TrimSpace(fmt.Sprintf("%s", val)), 64)
+		return int64(n)
+	default:
+		return 0
+	}
+}
+
+func toFloat64(v interface{}) float64 {
+	switch val := v.(type) {
+	case float64:
+		return val
+	case int64:
+		return float64(val)
+	case int:
+		return float64(val)
+	case int32:
+		return float64(val)
+	case []byte, string:
+		f, _ := strconv.ParseFloat(strings.TrimSpace(fmt.Sprintf("%s", val)), 64)
+		return f
+	default:
+		return 0
+	}
+}
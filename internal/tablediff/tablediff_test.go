@@ -0,0 +1,258 @@
+package tablediff
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vinod901/opendq-go/internal/datasource"
+)
+
+var (
+	rangePattern = regexp.MustCompile(`id >= ('[^']*'|-?\d+) AND id (<=?) ('[^']*'|-?\d+)`)
+	modPattern   = regexp.MustCompile(`MOD\(rn - 1, (\d+)\)`)
+)
+
+// tableConnector is an in-memory table keyed by id, answering the diff's
+// queries. Row checksums hash the row's value.
+type tableConnector struct {
+	rows    map[interface{}]string // Key, int64 or string, to the row's value
+	queries []string
+}
+
+func (c *tableConnector) Connect(ctx context.Context) error { return nil }
+func (c *tableConnector) Close() error                      { return nil }
+func (c *tableConnector) Ping(ctx context.Context) error    { return nil }
+func (c *tableConnector) GetTables(ctx context.Context) ([]datasource.TableInfo, error) {
+	return nil, nil
+}
+func (c *tableConnector) GetColumns(ctx context.Context, table string) ([]datasource.ColumnInfo, error) {
+	return nil, nil
+}
+func (c *tableConnector) GetRowCount(ctx context.Context, table string) (int64, error) {
+	return int64(len(c.rows)), nil
+}
+func (c *tableConnector) GetPartitions(ctx context.Context, table string) ([]datasource.PartitionInfo, error) {
+	return nil, nil
+}
+func (c *tableConnector) Type() datasource.Type { return datasource.TypePostgres }
+
+func (c *tableConnector) Query(ctx context.Context, query string, args ...interface{}) (*datasource.QueryResult, error) {
+	c.queries = append(c.queries, query)
+	keys := c.keysInRange(query)
+	rows := []map[string]interface{}{}
+
+	switch {
+	case strings.Contains(query, "MIN(id)"):
+		row := map[string]interface{}{"row_count": int64(len(keys))}
+		if len(keys) > 0 {
+			row["min_key"], row["max_key"] = keys[0], keys[len(keys)-1]
+		}
+		rows = append(rows, row)
+	case strings.Contains(query, "as checksum"):
+		var sum int64
+		for _, key := range keys {
+			sum += c.checksum(key)
+		}
+		rows = append(rows, map[string]interface{}{"row_count": int64(len(keys)), "checksum": sum})
+	case strings.Contains(query, "ROW_NUMBER()"):
+		step, _ := strconv.Atoi(modPattern.FindStringSubmatch(query)[1])
+		for i := step; i < len(keys); i += step {
+			rows = append(rows, map[string]interface{}{"bound": keys[i]})
+		}
+	case strings.Contains(query, "as row_key"):
+		for _, key := range keys {
+			rows = append(rows, map[string]interface{}{"row_key": key, "row_checksum": c.checksum(key)})
+		}
+	default:
+		return nil, fmt.Errorf("unexpected query: %s", query)
+	}
+	return &datasource.QueryResult{Rows: rows, RowCount: int64(len(rows))}, nil
+}
+
+// keysInRange returns the sorted keys matching the query's key range, or all
+// keys when it has none
+func (c *tableConnector) keysInRange(query string) []interface{} {
+	match := rangePattern.FindStringSubmatch(query)
+	var keys []interface{}
+	for key := range c.rows {
+		if match == nil || compare(key, parseLiteral(match[1])) >= 0 &&
+			(compare(key, parseLiteral(match[3])) < 0 || match[2] == "<=" && compare(key, parseLiteral(match[3])) == 0) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return compare(keys[i], keys[j]) < 0 })
+	return keys
+}
+
+func (c *tableConnector) checksum(key interface{}) int64 {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%v|%s", key, c.rows[key])
+	return int64(h.Sum32())
+}
+
+func parseLiteral(literal string) interface{} {
+	if strings.HasPrefix(literal, "'") {
+		return strings.Trim(literal, "'")
+	}
+	n, _ := strconv.ParseInt(literal, 10, 64)
+	return n
+}
+
+func compare(a, b interface{}) int {
+	if x, ok := a.(int64); ok {
+		y := b.(int64)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a.(string), b.(string))
+}
+
+func integerTable(n int) *tableConnector {
+	c := &tableConnector{rows: make(map[interface{}]string, n)}
+	for i := 1; i <= n; i++ {
+		c.rows[int64(i)] = fmt.Sprintf("row %d", i)
+	}
+	return c
+}
+
+func TestDiffIntegerKeys(t *testing.T) {
+	source, target := integerTable(5000), integerTable(5000)
+	delete(target.rows, int64(17))
+	delete(target.rows, int64(4200))
+	target.rows[int64(2500)] = "changed"
+	target.rows[int64(5003)] = "new"
+
+	var progress []Progress
+	result, err := Diff(context.Background(),
+		Table{Connector: source, Name: "src.orders"},
+		Table{Connector: target, Name: "dst.orders"},
+		Options{KeyColumn: "id", Columns: []string{"status"}, BisectionFactor: 4, BisectionThreshold: 100,
+			OnProgress: func(p Progress) { progress = append(progress, p) }})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fmt.Sprint(result.Removed) != "[17 4200]" || fmt.Sprint(result.Changed) != "[2500]" || fmt.Sprint(result.Added) != "[5003]" {
+		t.Errorf("unexpected diff: removed %v, changed %v, added %v", result.Removed, result.Changed, result.Added)
+	}
+	if result.SourceRows != 5000 || result.TargetRows != 4999 || result.Truncated {
+		t.Errorf("unexpected totals: %+v", result)
+	}
+	if len(progress) == 0 || progress[len(progress)-1].Fraction != 1 || progress[len(progress)-1].Diffs != 4 {
+		t.Errorf("expected progress to end complete with 4 diffs, got %+v", progress)
+	}
+	// Bisection fetches rows of the differing segments only
+	if fetched := progress[len(progress)-1].RowsFetched; fetched >= 5000 {
+		t.Errorf("expected bisection to fetch few rows, fetched %d", fetched)
+	}
+}
+
+func TestDiffTextKeys(t *testing.T) {
+	source := &tableConnector{rows: map[interface{}]string{}}
+	target := &tableConnector{rows: map[interface{}]string{}}
+	for i := 0; i < 300; i++ {
+		key := fmt.Sprintf("user-%04d", i)
+		source.rows[key] = "a"
+		target.rows[key] = "a"
+	}
+	delete(source.rows, "user-0042")
+	target.rows["user-0150"] = "b"
+
+	result, err := Diff(context.Background(),
+		Table{Connector: source, Name: "src.users", Where: "active"},
+		Table{Connector: target, Name: "dst.users", Where: "active"},
+		Options{KeyColumn: "id", BisectionThreshold: 50})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(result.Added) != "[user-0042]" || fmt.Sprint(result.Changed) != "[user-0150]" || len(result.Removed) != 0 {
+		t.Errorf("unexpected diff: removed %v, changed %v, added %v", result.Removed, result.Changed, result.Added)
+	}
+	if !strings.Contains(source.queries[0], "(SELECT * FROM src.users WHERE active) _diff") {
+		t.Errorf("expected the filter in queries, got %s", source.queries[0])
+	}
+}
+
+func TestDiffMaxDiffs(t *testing.T) {
+	source, target := integerTable(2000), integerTable(2000)
+	for i := int64(1); i <= 2000; i += 2 {
+		target.rows[i] = "changed"
+	}
+
+	result, err := Diff(context.Background(),
+		Table{Connector: source, Name: "a"},
+		Table{Connector: target, Name: "b"},
+		Options{KeyColumn: "id", BisectionThreshold: 100, MaxDiffs: 50})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Truncated || result.Total() < 50 || result.Total() >= 1000 {
+		t.Errorf("expected a truncated diff of about 50 keys, got %d (truncated %v)", result.Total(), result.Truncated)
+	}
+}
+
+func TestDiffEmptyTables(t *testing.T) {
+	result, err := Diff(context.Background(),
+		Table{Connector: integerTable(0), Name: "a"},
+		Table{Connector: integerTable(0), Name: "b"},
+		Options{KeyColumn: "id"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Total() != 0 || result.Segments != 0 {
+		t.Errorf("expected an empty diff, got %+v", result)
+	}
+
+	if _, err := Diff(context.Background(), Table{Connector: integerTable(0), Name: "a"}, Table{Connector: integerTable(0), Name: "b"}, Options{}); err == nil {
+		t.Error("expected an error without a key column")
+	}
+}
+
+func TestStartDiff_RejectsWhere(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	_, err := m.StartDiff(context.Background(), Request{
+		SourceDatasourceID: "ds-1", SourceTable: "a",
+		TargetDatasourceID: "ds-2", TargetTable: "b",
+		Where:   "1 = 1) _diff; DROP TABLE a; --",
+		Options: Options{KeyColumn: "id"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "where predicate") {
+		t.Fatalf("expected the where predicate to be rejected, got %v", err)
+	}
+}
+
+func TestEvictJobs(t *testing.T) {
+	m := NewManager(datasource.NewManager())
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		status := JobCompleted
+		if i == 0 {
+			status = JobRunning
+		}
+		id := strconv.Itoa(i)
+		m.jobs[id] = &Job{ID: id, Status: status, StartedAt: start.Add(time.Duration(i) * time.Second)}
+	}
+
+	m.SetMaxJobs(2)
+	jobs, _ := m.ListJobs(context.Background())
+	var ids []string
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+	// The oldest job is still running, so the oldest finished ones go
+	if strings.Join(ids, ",") != "3,0" {
+		t.Errorf("expected jobs 3,0 to be kept, got %v", ids)
+	}
+}
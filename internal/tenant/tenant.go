@@ -3,6 +3,8 @@ package tenant
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/google/uuid"
 )
@@ -10,11 +12,17 @@ import (
 // Manager handles tenant operations
 type Manager struct {
 	// In a real implementation, this would use Ent client
+	mu      sync.RWMutex
+	tenants map[string]*Tenant // By ID
+	slugs   map[string]string  // Tenant ID by slug
 }
 
 // NewManager creates a new tenant manager
 func NewManager() *Manager {
-	return &Manager{}
+	return &Manager{
+		tenants: make(map[string]*Tenant),
+		slugs:   make(map[string]string),
+	}
 }
 
 // Tenant represents a tenant
@@ -65,6 +73,9 @@ func GetTenantSlug(ctx context.Context) (string, error) {
 // CreateTenant creates a new tenant
 func (m *Manager) CreateTenant(ctx context.Context, name, slug string, metadata map[string]interface{}) (*Tenant, error) {
 	// In real implementation: use Ent to create tenant
+	if slug == "" {
+		return nil, fmt.Errorf("tenant slug is required")
+	}
 	tenant := &Tenant{
 		ID:       generateID(),
 		Name:     name,
@@ -72,37 +83,90 @@ func (m *Manager) CreateTenant(ctx context.Context, name, slug string, metadata
 		Metadata: metadata,
 		Active:   true,
 	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.slugs[slug]; exists {
+		return nil, fmt.Errorf("tenant slug already exists: %s", slug)
+	}
+	m.tenants[tenant.ID] = tenant
+	m.slugs[slug] = tenant.ID
 	return tenant, nil
 }
 
 // GetTenant retrieves a tenant by ID
 func (m *Manager) GetTenant(ctx context.Context, id string) (*Tenant, error) {
 	// In real implementation: use Ent to get tenant
-	return nil, fmt.Errorf("not implemented")
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	tenant, exists := m.tenants[id]
+	if !exists {
+		return nil, fmt.Errorf("tenant not found: %s", id)
+	}
+	return tenant, nil
 }
 
-// GetTenantBySlug retrieves a tenant by slug
+// GetTenantBySlug retrieves an active tenant by slug
 func (m *Manager) GetTenantBySlug(ctx context.Context, slug string) (*Tenant, error) {
 	// In real implementation: use Ent to get tenant
-	return nil, fmt.Errorf("not implemented")
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	tenant, exists := m.tenants[m.slugs[slug]]
+	if !exists || !tenant.Active {
+		return nil, fmt.Errorf("tenant not found: %s", slug)
+	}
+	return tenant, nil
 }
 
 // UpdateTenant updates a tenant
 func (m *Manager) UpdateTenant(ctx context.Context, id string, updates map[string]interface{}) error {
 	// In real implementation: use Ent to update tenant
-	return fmt.Errorf("not implemented")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tenant, exists := m.tenants[id]
+	if !exists {
+		return fmt.Errorf("tenant not found: %s", id)
+	}
+
+	if name, ok := updates["name"].(string); ok {
+		tenant.Name = name
+	}
+	if metadata, ok := updates["metadata"].(map[string]interface{}); ok {
+		tenant.Metadata = metadata
+	}
+	if active, ok := updates["active"].(bool); ok {
+		tenant.Active = active
+	}
+	return nil
 }
 
 // DeleteTenant deletes a tenant
 func (m *Manager) DeleteTenant(ctx context.Context, id string) error {
 	// In real implementation: use Ent to delete tenant
-	return fmt.Errorf("not implemented")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tenant, exists := m.tenants[id]
+	if !exists {
+		return fmt.Errorf("tenant not found: %s", id)
+	}
+	delete(m.slugs, tenant.Slug)
+	delete(m.tenants, id)
+	return nil
 }
 
-// ListTenants lists all tenants
+// ListTenants lists all tenants, ordered by slug
 func (m *Manager) ListTenants(ctx context.Context) ([]*Tenant, error) {
 	// In real implementation: use Ent to list tenants
-	return nil, fmt.Errorf("not implemented")
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	tenants := make([]*Tenant, 0, len(m.tenants))
+	for _, tenant := range m.tenants {
+		tenants = append(tenants, tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool {
+		return tenants[i].Slug < tenants[j].Slug
+	})
+	return tenants, nil
 }
 
 // Helper function to generate IDs using UUID
@@ -210,6 +210,7 @@ func (m *Manager) QueryView(ctx context.Context, id string, limit int) (*datasou
 		return nil, fmt.Errorf("view is inactive")
 	}
 
+	ctx = datasource.WithQueryOrigin(ctx, datasource.QueryOrigin{Type: datasource.OriginView, ID: view.ID})
 	connector, err := m.datasourceManager.GetConnector(ctx, view.DatasourceID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get datasource connector: %w", err)
@@ -234,6 +235,7 @@ func (m *Manager) GetViewRowCount(ctx context.Context, id string) (int64, error)
 		return 0, err
 	}
 
+	ctx = datasource.WithQueryOrigin(ctx, datasource.QueryOrigin{Type: datasource.OriginView, ID: view.ID})
 	connector, err := m.datasourceManager.GetConnector(ctx, view.DatasourceID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get datasource connector: %w", err)
@@ -271,6 +273,7 @@ func (m *Manager) ValidateView(ctx context.Context, id string) error {
 	}
 
 	// Try to execute with limit 0 to validate SQL
+	ctx = datasource.WithQueryOrigin(ctx, datasource.QueryOrigin{Type: datasource.OriginView, ID: view.ID})
 	connector, err := m.datasourceManager.GetConnector(ctx, view.DatasourceID)
 	if err != nil {
 		return fmt.Errorf("failed to get datasource connector: %w", err)
@@ -322,8 +325,12 @@ func (m *Manager) validateViewDefinition(ctx context.Context, view *View) error
 		}
 	}
 
-	// Validate filters
-	for i, filter := range def.Filters {
+	return ValidateFilters(def.Filters)
+}
+
+// ValidateFilters validates filter conditions, as used by views and checks
+func ValidateFilters(filters []FilterDef) error {
+	for i, filter := range filters {
 		if filter.Column == "" {
 			return fmt.Errorf("filter %d: column is required", i)
 		}
@@ -337,6 +344,12 @@ func (m *Manager) validateViewDefinition(ctx context.Context, view *View) error
 		if !validOperators[filter.Operator] {
 			return fmt.Errorf("filter %d: invalid operator '%s'", i, filter.Operator)
 		}
+		if (filter.Operator == "in" || filter.Operator == "not_in") && len(filter.Values) == 0 {
+			return fmt.Errorf("filter %d: values are required for %s", i, filter.Operator)
+		}
+		if filter.LogicalOp != "" && filter.LogicalOp != "AND" && filter.LogicalOp != "OR" {
+			return fmt.Errorf("filter %d: invalid logical operator '%s'", i, filter.LogicalOp)
+		}
 	}
 
 	return nil
@@ -344,6 +357,7 @@ func (m *Manager) validateViewDefinition(ctx context.Context, view *View) error
 
 // inferSchema infers the schema of a view
 func (m *Manager) inferSchema(ctx context.Context, view *View) ([]datasource.ColumnInfo, error) {
+	ctx = datasource.WithQueryOrigin(ctx, datasource.QueryOrigin{Type: datasource.OriginView, ID: view.ID})
 	connector, err := m.datasourceManager.GetConnector(ctx, view.DatasourceID)
 	if err != nil {
 		return nil, err
@@ -435,17 +449,7 @@ func (m *Manager) buildSelectSQL(def ViewDefinition) (string, error) {
 
 	// WHERE
 	if len(def.Filters) > 0 {
-		sql += " WHERE "
-		for i, filter := range def.Filters {
-			if i > 0 {
-				logicalOp := filter.LogicalOp
-				if logicalOp == "" {
-					logicalOp = "AND"
-				}
-				sql += fmt.Sprintf(" %s ", logicalOp)
-			}
-			sql += buildFilterCondition(filter)
-		}
+		sql += " WHERE " + FilterSQL(def.Filters)
 	}
 
 	// GROUP BY
@@ -503,6 +507,23 @@ func (m *Manager) buildUnionSQL(def ViewDefinition) (string, error) {
 	return sql, nil
 }
 
+// FilterSQL builds a SQL predicate from filters, combining each filter with
+// the previous one by its logical operator, AND by default
+func FilterSQL(filters []FilterDef) string {
+	sql := ""
+	for i, filter := range filters {
+		if i > 0 {
+			logicalOp := filter.LogicalOp
+			if logicalOp == "" {
+				logicalOp = "AND"
+			}
+			sql += fmt.Sprintf(" %s ", logicalOp)
+		}
+		sql += buildFilterCondition(filter)
+	}
+	return sql
+}
+
 // buildFilterCondition builds a SQL condition from a filter
 func buildFilterCondition(filter FilterDef) string {
 	switch filter.Operator {
@@ -627,6 +648,11 @@ func (c *ViewConnector) GetRowCount(ctx context.Context, table string) (int64, e
 	return c.manager.GetViewRowCount(ctx, c.view.ID)
 }
 
+// GetPartitions returns no partitions; views are not partitioned
+func (c *ViewConnector) GetPartitions(ctx context.Context, table string) ([]datasource.PartitionInfo, error) {
+	return []datasource.PartitionInfo{}, nil
+}
+
 // Type returns the datasource type
 func (c *ViewConnector) Type() datasource.Type {
 	return datasource.TypeView
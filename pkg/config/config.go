@@ -14,6 +14,8 @@ type Config struct {
 	OpenFGA      OpenFGAConfig
 	MultiTenant  MultiTenantConfig
 	OpenLineage  OpenLineageConfig
+	Crawler      CrawlerConfig
+	Checks       ChecksConfig
 }
 
 // ServerConfig contains HTTP server configuration
@@ -61,6 +63,22 @@ type OpenLineageConfig struct {
 	Namespace string
 }
 
+// CrawlerConfig contains schema crawler settings
+type CrawlerConfig struct {
+	Enabled         bool
+	IntervalMinutes int
+}
+
+// ChecksConfig contains check execution settings
+type ChecksConfig struct {
+	MaxConcurrent     int
+	MaxPerDatasource  int
+	TimeoutSeconds    int // 0 for no timeout
+	RetryAttempts     int // Attempts per run, 1 disables retries
+	RetryBackoffMS    int // Wait before the first retry
+	RetryMaxBackoffMS int // Upper bound of a wait between retries
+}
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	cfg := &Config{
@@ -97,6 +115,18 @@ func Load() (*Config, error) {
 			Endpoint:  getEnv("OPENLINEAGE_ENDPOINT", "http://localhost:5000"),
 			Namespace: getEnv("OPENLINEAGE_NAMESPACE", "opendq"),
 		},
+		Crawler: CrawlerConfig{
+			Enabled:         getEnvAsBool("CRAWLER_ENABLED", false),
+			IntervalMinutes: getEnvAsInt("CRAWLER_INTERVAL_MINUTES", 60),
+		},
+		Checks: ChecksConfig{
+			MaxConcurrent:     getEnvAsInt("CHECKS_MAX_CONCURRENT", 8),
+			MaxPerDatasource:  getEnvAsInt("CHECKS_MAX_PER_DATASOURCE", 4),
+			TimeoutSeconds:    getEnvAsInt("CHECKS_TIMEOUT_SECONDS", 0),
+			RetryAttempts:     getEnvAsInt("CHECKS_RETRY_ATTEMPTS", 3),
+			RetryBackoffMS:    getEnvAsInt("CHECKS_RETRY_BACKOFF_MS", 500),
+			RetryMaxBackoffMS: getEnvAsInt("CHECKS_RETRY_MAX_BACKOFF_MS", 10000),
+		},
 	}
 
 	return cfg, nil